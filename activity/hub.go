@@ -0,0 +1,205 @@
+// Package activity fans out account activity that Postgres reports through
+// LISTEN/NOTIFY to the clients watching those accounts.
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// Channel the database triggers notify on
+const channel = "account_activity"
+
+// Events a subscriber has not picked up yet. Once the buffer is full the
+// subscriber is dropped rather than slowing everyone else down.
+const bufferSize = 64
+
+var (
+	ErrLagged = errors.New("subscriber fell behind")
+	// Notifications may have been lost while the connection was down
+	ErrReset = errors.New("lost connection to the database")
+)
+
+// Event is either a new entry or the new state of an account.
+type Event struct {
+	AccountID int64
+	Entry     *db.Entry
+	Account   *db.Account
+}
+
+type notification struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Subscription receives the events of one account until it is closed.
+type Subscription struct {
+	hub       *Hub
+	accountID int64
+	events    chan Event
+	err       error
+}
+
+// Events is closed when the hub drops the subscription, Err then tells why.
+func (sub *Subscription) Events() <-chan Event {
+	return sub.events
+}
+
+// Err returns why the subscription was dropped. Only valid once Events is closed.
+func (sub *Subscription) Err() error {
+	return sub.err
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (sub *Subscription) Close() {
+	sub.hub.remove(sub, nil)
+}
+
+// Hub keeps track of who is watching which account.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe starts receiving the events of an account.
+func (hub *Hub) Subscribe(accountID int64) *Subscription {
+	sub := &Subscription{
+		hub:       hub,
+		accountID: accountID,
+		events:    make(chan Event, bufferSize),
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.subscribers[accountID] == nil {
+		hub.subscribers[accountID] = make(map[*Subscription]struct{})
+	}
+	hub.subscribers[accountID][sub] = struct{}{}
+	return sub
+}
+
+// Publish hands the event to everyone watching its account without blocking.
+func (hub *Hub) Publish(event Event) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for sub := range hub.subscribers[event.AccountID] {
+		select {
+		case sub.events <- event:
+		default:
+			hub.removeLocked(sub, ErrLagged)
+		}
+	}
+}
+
+// Reset drops every subscription with err, so clients start over from a fresh state.
+func (hub *Hub) Reset(err error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for _, subs := range hub.subscribers {
+		for sub := range subs {
+			hub.removeLocked(sub, err)
+		}
+	}
+}
+
+func (hub *Hub) remove(sub *Subscription, err error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.removeLocked(sub, err)
+}
+
+func (hub *Hub) removeLocked(sub *Subscription, err error) {
+	subs := hub.subscribers[sub.accountID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(hub.subscribers, sub.accountID)
+	}
+	// Written before the channel is closed so readers see it once Events is drained
+	sub.err = err
+	close(sub.events)
+}
+
+// Listen publishes the notifications of the database at dbSource until ctx is
+// cancelled. Subscriptions are reset whenever the connection drops since
+// notifications sent in the meantime are gone.
+func (hub *Hub) Listen(ctx context.Context, dbSource string) error {
+	listener := pq.NewListener(dbSource, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("account activity listener")
+		}
+		if event == pq.ListenerEventDisconnected {
+			hub.Reset(ErrReset)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("cannot listen to %s: %w", channel, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// A nil notification is sent after the listener reconnected
+			if n == nil {
+				continue
+			}
+
+			event, err := parseNotification(n.Extra)
+			if err != nil {
+				log.Error().Err(err).Str("payload", n.Extra).Msg("cannot parse account activity")
+				continue
+			}
+			hub.Publish(event)
+		case <-time.After(90 * time.Second):
+			// Notices a dead connection even when nothing is happening
+			go listener.Ping()
+		}
+	}
+}
+
+func parseNotification(payload string) (Event, error) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return Event{}, err
+	}
+
+	switch n.Type {
+	case "entry":
+		var entry db.Entry
+		if err := json.Unmarshal(n.Data, &entry); err != nil {
+			return Event{}, err
+		}
+		return Event{AccountID: entry.AccountID, Entry: &entry}, nil
+	case "account":
+		var account db.Account
+		if err := json.Unmarshal(n.Data, &account); err != nil {
+			return Event{}, err
+		}
+		return Event{AccountID: account.ID, Account: &account}, nil
+	}
+	return Event{}, fmt.Errorf("unknown activity type %q", n.Type)
+}
@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"testing"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	hub := NewHub()
+	accountID := util.RandomInt(1, 1000)
+
+	sub1 := hub.Subscribe(accountID)
+	sub2 := hub.Subscribe(accountID)
+	other := hub.Subscribe(accountID + 1)
+	defer other.Close()
+
+	event := Event{AccountID: accountID, Entry: &db.Entry{AccountID: accountID, Amount: 10}}
+	hub.Publish(event)
+
+	require.Equal(t, event, <-sub1.Events())
+	require.Equal(t, event, <-sub2.Events())
+	require.Empty(t, other.Events())
+
+	// A closed subscription no longer receives anything
+	sub1.Close()
+	sub1.Close()
+	_, ok := <-sub1.Events()
+	require.False(t, ok)
+	require.NoError(t, sub1.Err())
+
+	hub.Publish(event)
+	require.Equal(t, event, <-sub2.Events())
+	sub2.Close()
+	require.Empty(t, hub.subscribers[accountID])
+}
+
+func TestPublishLagged(t *testing.T) {
+	hub := NewHub()
+	sub := hub.Subscribe(1)
+
+	for i := 0; i <= bufferSize; i++ {
+		hub.Publish(Event{AccountID: 1})
+	}
+
+	// Whatever was buffered is still delivered before the subscription ends
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	require.Equal(t, bufferSize, received)
+	require.ErrorIs(t, sub.Err(), ErrLagged)
+}
+
+func TestReset(t *testing.T) {
+	hub := NewHub()
+	sub1 := hub.Subscribe(1)
+	sub2 := hub.Subscribe(2)
+
+	hub.Reset(ErrReset)
+
+	for _, sub := range []*Subscription{sub1, sub2} {
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+		require.ErrorIs(t, sub.Err(), ErrReset)
+	}
+	require.Empty(t, hub.subscribers)
+}
+
+func TestParseNotification(t *testing.T) {
+	event, err := parseNotification(`{"type":"entry","data":{"id":7,"account_id":3,"amount":-50,"created_at":"2026-03-31T23:59:00.123456+00:00"}}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), event.AccountID)
+	require.NotNil(t, event.Entry)
+	require.Equal(t, int64(7), event.Entry.ID)
+	require.Equal(t, int64(-50), event.Entry.Amount)
+	require.Nil(t, event.Account)
+
+	event, err = parseNotification(`{"type":"account","data":{"id":3,"owner":"bob","balance":950,"currency":"USD","created_at":"2026-01-01T00:00:00+00:00","type":"checking","status":"active","credits_frozen":false}}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), event.AccountID)
+	require.NotNil(t, event.Account)
+	require.Equal(t, int64(950), event.Account.Balance)
+	require.Nil(t, event.Entry)
+
+	_, err = parseNotification(`{"type":"transfer","data":{}}`)
+	require.Error(t, err)
+
+	_, err = parseNotification(`not json`)
+	require.Error(t, err)
+}
@@ -1,47 +1,46 @@
 package api
 
 import (
-	"database/sql"
-	"errors"
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/token"
-	"github.com/lib/pq"
+	"github.com/jasonwebb3152/simplebank/util"
 )
 
 // No balance because should always be zero on creation.
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	// Checking when left out
+	Type string `json:"type" binding:"omitempty,account_type"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
+	if req.Type == "" {
+		req.Type = util.CheckingAccount
+	}
+
 	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
 		Owner:    payload.Username,
 		Currency: req.Currency,
 		Balance:  0,
+		Type:     req.Type,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.CreateAccountTx(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-			log.Println(pqErr.Code.Name())
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// A missing owner or a second account in the same currency is
+		// FAILED_PRECONDITION or ALREADY_EXISTS
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -56,25 +55,20 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequest
 	err := ctx.ShouldBindUri(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
 	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodePermissionDenied, "account doesn't belong to the authenticated user")
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -89,7 +83,7 @@ type listAccountsRequest struct {
 func (server *Server) listAccounts(ctx *gin.Context) {
 	var req listAccountsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
@@ -101,12 +95,7 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 	}
 	accounts, err := server.store.ListAccounts(ctx, arg)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, accounts)
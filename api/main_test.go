@@ -7,14 +7,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
+// Hashes passwords with the same costs as the servers of newTestServer
+var testPasswords = func() *password.Manager {
+	passwords, err := password.New(util.Config{})
+	if err != nil {
+		panic(err)
+	}
+	return passwords
+}()
+
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		SessionRiskPolicy:   util.SessionRiskNotify,
 	}
 
 	server, err := NewServer(config, store)
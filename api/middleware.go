@@ -1,12 +1,13 @@
 package api
 
 import (
-	"errors"
-	"fmt"
-	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/ratelimit"
 	"github.com/jasonwebb3152/simplebank/token"
 )
 
@@ -20,32 +21,79 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization header not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			err := apperr.New(apperr.CodeUnauthenticated, "authorization header not provided")
+			abortWithError(ctx, err)
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			err := apperr.New(apperr.CodeUnauthenticated, "invalid authorization header format")
+			abortWithError(ctx, err)
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			err := apperr.Newf(apperr.CodeUnauthenticated, "unsupported authorization type %s", authorizationType)
+			abortWithError(ctx, err)
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, err)
 			return
 		}
 		ctx.Set(authorizationPayloadKey, payload)
+		setAuditActor(ctx, payload.Username)
 		ctx.Next()
 	}
 }
+
+// rateLimitMiddleware applies the limit configured for method. Behind authMiddleware
+// the caller is limited by username, otherwise by client IP.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, method string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := "ip:" + ctx.ClientIP()
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			key = "user:" + payload.(*token.Payload).Username
+		}
+
+		result, limited := limiter.Allow(ctx, method, key)
+		if !limited {
+			ctx.Next()
+			return
+		}
+
+		for header, value := range result.Headers() {
+			ctx.Header(header, value)
+		}
+
+		if !result.Allowed {
+			err := apperr.Newf(apperr.CodeRateLimited, "too many requests, retry in %s", result.RetryAfter.Round(time.Second))
+			abortWithError(ctx, err)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// auditMiddleware tells the store where the request comes from, so the changes it
+// makes are audited with it. authMiddleware adds who makes it.
+func auditMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		setAuditActor(ctx, "")
+		ctx.Next()
+	}
+}
+
+func setAuditActor(ctx *gin.Context, username string) {
+	audit := db.AuditContext{
+		Actor:     username,
+		ClientIP:  ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+	ctx.Request = ctx.Request.WithContext(db.WithAuditContext(ctx.Request.Context(), audit))
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jasonwebb3152/simplebank/ratelimit"
 	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,9 +20,10 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -37,7 +40,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", "jasonwebb", time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", "jasonwebb", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -53,7 +56,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "jasonwebb", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "jasonwebb", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -62,7 +65,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorizationType",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "jasonwebb", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "jasonwebb", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -71,7 +74,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "jasonwebb", -time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "jasonwebb", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -111,3 +114,104 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimitMiddleware(t *testing.T) {
+	server := newTestServer(t, nil)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(), map[string]ratelimit.Limit{
+		"Limited": {Rate: 1.0 / 60, Burst: 2},
+	})
+
+	limitedPath := "/limited"
+	server.router.GET(
+		limitedPath,
+		authMiddleware(server.tokenMaker),
+		rateLimitMiddleware(limiter, "Limited"),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	unlimitedPath := "/unlimited"
+	server.router.GET(
+		unlimitedPath,
+		rateLimitMiddleware(limiter, "Unlimited"),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	send := func(path string, username string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.DepositorRole, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := send(limitedPath, "alice")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get(ratelimit.HeaderLimit))
+	require.Equal(t, "1", recorder.Header().Get(ratelimit.HeaderRemaining))
+
+	recorder = send(limitedPath, "alice")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get(ratelimit.HeaderRemaining))
+
+	recorder = send(limitedPath, "alice")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get(ratelimit.HeaderRetryAfter))
+
+	// Each user has a bucket of their own
+	recorder = send(limitedPath, "bob")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = send(unlimitedPath, "alice")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get(ratelimit.HeaderLimit))
+}
+
+func TestTrustedProxies(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies string
+		clientIP       string
+	}{
+		{"NoneByDefault", "", "10.0.0.5"},
+		{"OtherProxy", "192.168.0.0/16", "10.0.0.5"},
+		{"TrustedProxy", "10.0.0.0/8, 192.168.0.1", "203.0.113.7"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := util.Config{
+				TokenSymmetricKey: util.RandomString(32),
+				SessionRiskPolicy: util.SessionRiskNotify,
+				TrustedProxies:    tc.trustedProxies,
+			}
+			server, err := NewServer(config, nil)
+			require.NoError(t, err)
+
+			var clientIP string
+			server.router.GET("/ip", func(ctx *gin.Context) {
+				clientIP = ctx.ClientIP()
+			})
+
+			request, err := http.NewRequest(http.MethodGet, "/ip", nil)
+			require.NoError(t, err)
+			request.RemoteAddr = "10.0.0.5:41000"
+			request.Header.Set("X-Forwarded-For", "203.0.113.7")
+			server.router.ServeHTTP(httptest.NewRecorder(), request)
+
+			require.Equal(t, tc.clientIP, clientIP)
+		})
+	}
+
+	_, err := NewServer(util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		SessionRiskPolicy: util.SessionRiskNotify,
+		TrustedProxies:    "not-an-address",
+	}, nil)
+	require.Error(t, err)
+}
@@ -2,11 +2,15 @@ package api
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
+	"github.com/jasonwebb3152/simplebank/ratelimit"
 	"github.com/jasonwebb3152/simplebank/token"
 	"github.com/jasonwebb3152/simplebank/util"
 )
@@ -16,7 +20,10 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	passwords  *password.Manager
+	policy     *password.Policy
 	router     *gin.Engine
+	limiter    *ratelimit.Limiter
 }
 
 // NewServer creates a new HTTP server and sets up routing
@@ -25,42 +32,104 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+
+	limiter, err := ratelimit.New(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rate limiter: %w", err)
+	}
+
+	if !util.IsSupportedSessionRiskPolicy(config.SessionRiskPolicy) {
+		return nil, fmt.Errorf("unsupported session risk policy %q", config.SessionRiskPolicy)
+	}
+
+	passwords, err := password.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password manager: %w", err)
+	}
+
+	policy, err := password.NewPolicyFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password policy: %w", err)
+	}
+
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		passwords:  passwords,
+		policy:     policy,
+		limiter:    limiter,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("account_type", validAccountType)
 	}
 
-	server.setupRouter()
+	err = server.setupRouter()
+	if err != nil {
+		return nil, err
+	}
 	return server, nil
 }
 
-func (server *Server) setupRouter() {
+func (server *Server) setupRouter() error {
 	router := gin.Default()
+	// X-Forwarded-For is only believed from the configured proxies. Rate limits and
+	// audit records would otherwise use whatever address the client claims.
+	err := router.SetTrustedProxies(parseTrustedProxies(server.config.TrustedProxies))
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	// Lets the store read the audit context from the request through gin's context
+	router.ContextWithFallback = true
+	router.Use(auditMiddleware())
 
 	// Don't need auth middleware because everyone should be able to
-	router.POST("/users", server.createUser)
-	router.POST("/users/login", server.loginUser)
-	router.POST("/tokens/renew_access", server.renewAccessToken)
+	// Limits are named after the matching gRPC methods so both servers share them
+	router.POST("/users", server.rateLimit("CreateUser"), server.createUser)
+	router.POST("/users/login", server.rateLimit("LoginUser"), server.loginUser)
+	router.POST("/tokens/renew_access", server.rateLimit("RenewAccessToken"), server.renewAccessToken)
 
 	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
 
-	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccount)
-	authRoutes.GET("/accounts", server.listAccounts)
+	authRoutes.POST("/accounts", server.rateLimit("CreateAccount"), server.createAccount)
+	authRoutes.GET("/accounts/:id", server.rateLimit("GetAccount"), server.getAccount)
+	authRoutes.GET("/accounts", server.rateLimit("ListAccounts"), server.listAccounts)
 
-	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers", server.rateLimit("TransferMoney"), server.createTransfer)
 	server.router = router
+	return nil
+}
+
+func parseTrustedProxies(value string) []string {
+	/** Returns nil, which trusts no proxy, when none is configured. */
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+func (server *Server) rateLimit(method string) gin.HandlerFunc {
+	return rateLimitMiddleware(server.limiter, method)
 }
 
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// abortWithError writes err as problem details (RFC 9457), with the status of its
+// code, the same one the gateway answers with
+func abortWithError(ctx *gin.Context, err error) {
+	appErr := apperr.From(err)
+	ctx.Header("Content-Type", apperr.ContentTypeProblem)
+	ctx.AbortWithStatusJSON(appErr.HTTPStatus(), appErr.Problem(ctx.Request.URL.Path))
+}
+
+// bindingError reports a request that could not be bound or failed validation
+func bindingError(err error) error {
+	return apperr.Wrap(apperr.CodeInvalidArgument, err, err.Error())
 }
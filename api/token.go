@@ -1,12 +1,14 @@
 package api
 
 import (
-	"database/sql"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
 )
 
 type renewAccessTokenRequest struct {
@@ -21,59 +23,60 @@ type renewAccessTokenResponse struct {
 func (server *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
 	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
 	session, err := server.store.GetSession(ctx, refreshPayload.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
 	if session.IsBlocked {
-		err := fmt.Errorf("blocked session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodeUnauthenticated, "blocked session")
+		abortWithError(ctx, err)
 		return
 	}
 
 	if session.Username != refreshPayload.Username {
-		err := fmt.Errorf("incorrect session user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodeUnauthenticated, "incorrect session user")
+		abortWithError(ctx, err)
 		return
 	}
 
 	if session.RefreshToken != req.RefreshToken {
-		err := fmt.Errorf("incorrect refresh token")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodeUnauthenticated, "incorrect refresh token")
+		abortWithError(ctx, err)
 		return
 	}
 
 	if time.Now().After(session.ExpiresAt) {
-		err := fmt.Errorf("expired session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodeUnauthenticated, "expired session")
+		abortWithError(ctx, err)
 		return
 	}
 
-	// TODO: if ClientIP is different then block it?
+	if err := server.checkSessionRisk(ctx, session); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
 
 	// Renew the access token here
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		refreshPayload.Username,
+		refreshPayload.Role,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
+		return
 	}
 
 	rsp := renewAccessTokenResponse{
@@ -82,3 +85,33 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// checkSessionRisk applies the session risk policy when the session is renewed from
+// another client than the one that logged in.
+func (server *Server) checkSessionRisk(ctx *gin.Context, session db.Session) error {
+	clientIP := ctx.ClientIP()
+	userAgent := ctx.Request.UserAgent()
+	signals := util.SessionRiskSignals(session.ClientIp, session.UserAgent, clientIP, userAgent)
+	if len(signals) == 0 {
+		return nil
+	}
+
+	_, err := server.store.RecordSuspiciousSessionTx(ctx, db.RecordSuspiciousSessionTxParams{
+		Session:   session,
+		ClientIP:  clientIP,
+		UserAgent: userAgent,
+		Signals:   signals,
+		Policy:    server.config.SessionRiskPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot record suspicious session: %w", err)
+	}
+
+	switch server.config.SessionRiskPolicy {
+	case util.SessionRiskBlock:
+		return apperr.New(apperr.CodeUnauthenticated, "blocked session")
+	case util.SessionRiskReauth:
+		return apperr.New(apperr.CodeUnauthenticated, "session used from another client, log in again")
+	}
+	return nil
+}
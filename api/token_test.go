@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jasonwebb3152/simplebank/apperr"
+	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	const (
+		// httptest requests come from 192.0.2.1
+		sessionIP = "192.0.2.1"
+		userAgent = "simplebank-test"
+	)
+
+	testCases := []struct {
+		name          string
+		policy        string
+		session       func(session db.Session) db.Session
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			policy: util.SessionRiskBlock,
+			session: func(session db.Session) db.Session {
+				return session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					RecordSuspiciousSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "OtherClientNotify",
+			policy: util.SessionRiskNotify,
+			session: func(session db.Session) db.Session {
+				session.ClientIp = "198.51.100.2"
+				return session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				arg := db.RecordSuspiciousSessionTxParams{
+					Session:   session,
+					ClientIP:  sessionIP,
+					UserAgent: userAgent,
+					Signals:   []string{util.SessionClientIPChanged},
+					Policy:    util.SessionRiskNotify,
+				}
+				store.EXPECT().
+					RecordSuspiciousSessionTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "OtherClientReauth",
+			policy: util.SessionRiskReauth,
+			session: func(session db.Session) db.Session {
+				session.UserAgent = "curl/8.5.0"
+				return session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					RecordSuspiciousSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeUnauthenticated)
+			},
+		},
+		{
+			name:   "OtherClientBlock",
+			policy: util.SessionRiskBlock,
+			session: func(session db.Session) db.Session {
+				session.ClientIp = "198.51.100.2"
+				return session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				blocked := session
+				blocked.IsBlocked = true
+				store.EXPECT().
+					RecordSuspiciousSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(blocked, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				problem := requireProblem(t, recorder, apperr.CodeUnauthenticated)
+				require.Equal(t, "blocked session", problem.Detail)
+			},
+		},
+		{
+			name:   "BlockedSession",
+			policy: util.SessionRiskNotify,
+			session: func(session db.Session) db.Session {
+				session.IsBlocked = true
+				return session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					RecordSuspiciousSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			server.config.SessionRiskPolicy = tc.policy
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Hour)
+			require.NoError(t, err)
+
+			session := tc.session(db.Session{
+				ID:           refreshPayload.ID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				UserAgent:    userAgent,
+				ClientIp:     sessionIP,
+				ExpiresAt:    refreshPayload.ExpiredAt,
+				CreatedAt:    time.Now(),
+			})
+			store.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).
+				Times(1).
+				Return(session, nil)
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			request.Header.Set("User-Agent", userAgent)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
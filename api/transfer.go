@@ -1,12 +1,10 @@
 package api
 
 import (
-	"database/sql"
-	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/token"
 )
@@ -22,7 +20,7 @@ type transferRequest struct {
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
@@ -42,7 +40,8 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	result, err := server.store.TransferMoneyTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// A broken transfer limit tells which limit and how much is left in the metadata
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -52,25 +51,20 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string, isFromAccount bool) bool {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return false
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return false
 	}
 
 	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if isFromAccount && account.Owner != payload.Username {
-		err := errors.New("transferring account doesn't belong to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		err := apperr.New(apperr.CodePermissionDenied, "transferring account doesn't belong to authenticated user")
+		abortWithError(ctx, err)
 		return false
 	}
 
 	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch %s vs %s", account.ID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		err := apperr.Newf(apperr.CodeCurrencyMismatch, "account [%d] currency mismatch %s vs %s", account.ID, account.Currency, currency)
+		abortWithError(ctx, err)
 		return false
 	}
 
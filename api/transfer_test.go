@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,9 +11,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,7 +49,7 @@ func TestCreateTransfer(t *testing.T) {
 			toAccount:   account2,
 			currency:    "USD",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", user1.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore, fromAccount, toAccount db.Account, amount int64) {
 				store.EXPECT().
@@ -72,6 +75,39 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name:        "WithFees",
+			fromAccount: account1,
+			toAccount:   account2,
+			currency:    "USD",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, fromAccount, toAccount db.Account, amount int64) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				store.EXPECT().
+					TransferMoneyTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{
+						Fees: []db.TransferFee{
+							{Kind: db.FeeKindFlat, Amount: 10},
+							{Kind: db.FeeKindPercentage, Amount: 3},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.TransferTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				require.NoError(t, err)
+				require.Len(t, result.Fees, 2)
+				require.Equal(t, db.FeeKindFlat, result.Fees[0].Kind)
+				require.Equal(t, int64(3), result.Fees[1].Amount)
+			},
+		},
 		{
 			name:        "Unauthorized",
 			fromAccount: account1,
@@ -85,6 +121,65 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name:        "TransferLimitExceeded",
+			fromAccount: account1,
+			toAccount:   account2,
+			currency:    "USD",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, fromAccount, toAccount db.Account, amount int64) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+					Times(1).
+					Return(fromAccount, nil)
+
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+					Times(1).
+					Return(toAccount, nil)
+
+				store.EXPECT().
+					TransferMoneyTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, &db.TransferLimitError{
+						Limit:     db.LimitDaily,
+						Currency:  "USD",
+						Max:       100,
+						Remaining: 5,
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				problem := requireProblem(t, recorder, apperr.CodeTransferLimitExceeded)
+				require.Equal(t, db.LimitDaily, problem.Metadata["limit"])
+				require.Equal(t, "5", problem.Metadata["remaining"])
+			},
+		},
+		{
+			name:        "AccountFrozen",
+			fromAccount: account1,
+			toAccount:   account2,
+			currency:    "USD",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, fromAccount, toAccount db.Account, amount int64) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				store.EXPECT().
+					TransferMoneyTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, fmt.Errorf("account %d: %w", fromAccount.ID, db.ErrAccountFrozen))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeAccountFrozen)
+			},
+		},
 		// TODO: add test cases for errors (needed for wrong request inputs, invalid data...)
 	}
 
@@ -2,19 +2,23 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/util"
-	"github.com/lib/pq"
 )
 
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	// Checked against the password policy
+	Password string `json:"password" binding:"required"`
 	FullName string `json:"full_name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 }
@@ -40,13 +44,23 @@ func newUserResponse(user db.User) userResponse {
 func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
+		return
+	}
+
+	err := server.checkPasswordPolicy(req.Password, password.PolicyUser{
+		Username: req.Username,
+		Email:    req.Email,
+		FullName: req.FullName,
+	})
+	if err != nil {
+		abortWithError(ctx, err)
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(req.Password)
+	hashedPassword, err := server.passwords.Hash(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -59,14 +73,8 @@ func (server *Server) createUser(ctx *gin.Context) {
 
 	user, err := server.store.CreateUser(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation": // unique username and email stuff
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// A taken username or email is a unique_violation, ALREADY_EXISTS
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -75,13 +83,31 @@ func (server *Server) createUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+// checkPasswordPolicy returns an INVALID_ARGUMENT error with every rule of the
+// password policy plain breaks.
+func (server *Server) checkPasswordPolicy(plain string, user password.PolicyUser) error {
+	errs, err := server.policy.Check(plain, user)
+	if err != nil {
+		return fmt.Errorf("cannot check password: %w", err)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	violations := make([]apperr.Violation, len(errs))
+	for i, violation := range errs {
+		violations[i] = apperr.Violation{Field: "password", Description: violation.Error()}
+	}
+	return apperr.InvalidArgument(violations...)
+}
+
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
 type loginUserResponse struct {
-	SessionID             uuid.UUID    `json:session_id`
+	SessionID             uuid.UUID    `json:"session_id"`
 	AccessToken           string       `json:"access_token"`
 	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
 	RefreshToken          string       `json:"refresh_token"`
@@ -89,49 +115,104 @@ type loginUserResponse struct {
 	User                  userResponse `json:"user_response"`
 }
 
+func invalidCredentialsError() error {
+	return apperr.New(apperr.CodeInvalidCredentials, "incorrect username or password")
+}
+
+// rehashPassword replaces a hash made with an outdated algorithm or costs, now that
+// the password is known. The login goes on if it fails, the next one tries again.
+func (server *Server) rehashPassword(ctx *gin.Context, user db.User, plain string) {
+	hashedPassword, err := server.passwords.Hash(plain)
+	if err == nil {
+		_, err = server.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+			NewHashedPassword: hashedPassword,
+			Username:          user.Username,
+			OldHashedPassword: user.HashedPassword,
+		})
+	}
+	if err != nil {
+		ctx.Error(fmt.Errorf("cannot rehash password: %w", err))
+	}
+}
+
+// loginFailed audits a failed login. The audit log keeps the reason, the client only
+// learns that the credentials are wrong.
+func (server *Server) loginFailed(ctx *gin.Context, username string, reason string) error {
+	err := server.store.RecordAuditFailure(ctx, db.AuditRecord{
+		Action:     util.AuditUserLogin,
+		TargetType: util.AuditTargetUser,
+		TargetID:   username,
+		Reason:     reason,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("cannot audit login: %w", err)
+	}
+	return invalidCredentialsError()
+}
+
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, bindingError(err))
 		return
 	}
 
+	// An unknown username and a wrong password get the same answer, in the same
+	// time, so logins can't be used to find out which usernames exist
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			server.passwords.CheckUnknownUser(req.Password)
+			abortWithError(ctx, server.loginFailed(ctx, req.Username, util.AuditReasonUnknownUser))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
+		return
+	}
+
+	// System users own the bank's own accounts, nobody can log in as them
+	if user.Role == util.SystemRole {
+		server.passwords.CheckUnknownUser(req.Password)
+		abortWithError(ctx, server.loginFailed(ctx, req.Username, util.AuditReasonUnknownUser))
 		return
 	}
 
-	err = util.CheckPassword(req.Password, user.HashedPassword)
+	needsRehash, err := server.passwords.Check(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		if errors.Is(err, password.ErrMismatchedPassword) {
+			abortWithError(ctx, server.loginFailed(ctx, user.Username, util.AuditReasonWrongPassword))
+			return
+		}
+		abortWithError(ctx, fmt.Errorf("cannot check password: %w", err))
 		return
 	}
 
+	if needsRehash {
+		server.rehashPassword(ctx, user, req.Password)
+	}
+
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
 	// Create refresh token in the same way
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+	session, err := server.store.CreateSessionTx(ctx, db.CreateSessionParams{
 		ID:           refreshPayload.ID,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
@@ -141,7 +222,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		ExpiresAt:    refreshPayload.ExpiredAt,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, err)
 		return
 	}
 
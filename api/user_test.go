@@ -15,11 +15,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type eqCreateUserParamsMatcher struct {
@@ -34,7 +36,7 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
-	err := util.CheckPassword(e.password, arg.HashedPassword)
+	_, err := testPasswords.Check(e.password, arg.HashedPassword)
 	if err != nil {
 		return false
 	}
@@ -120,7 +122,8 @@ func TestCreateUserAPI(t *testing.T) {
 					Return(db.User{}, &pq.Error{Code: "23505"})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeAlreadyExists)
 			},
 		},
 		{
@@ -159,6 +162,31 @@ func TestCreateUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "WeakPassword",
+			body: gin.H{
+				"username":  user.Username,
+				"full_name": user.FullName,
+				"email":     user.Email,
+				"password":  user.Username,
+			},
+			buildStubs: func(mockStore *mockdb.MockStore) {
+				mockStore.
+					EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				// Every rule the password breaks is reported
+				problem := requireProblem(t, recorder, apperr.CodeInvalidArgument)
+				require.Len(t, problem.InvalidParams, 3)
+				for _, violation := range problem.InvalidParams {
+					require.Equal(t, "password", violation.Field)
+				}
+			},
+		},
 		{
 			name: "PasswordTooShort",
 			body: gin.H{
@@ -208,6 +236,12 @@ func TestLoginUserAPI(t *testing.T) {
 	user, password := randomUser(t)
 	session := randomSession(t)
 
+	// Hashed before argon2id was used
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	bcryptUser := user
+	bcryptUser.HashedPassword = string(bcryptHash)
+
 	testCases := []struct {
 		name          string
 		body          gin.H
@@ -226,7 +260,7 @@ func TestLoginUserAPI(t *testing.T) {
 					Times(1).
 					Return(user, nil)
 				store.EXPECT().
-					CreateSession(gomock.Any(), gomock.Any()).
+					CreateSessionTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(session, nil)
 			},
@@ -234,7 +268,149 @@ func TestLoginUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
-		// TODO: Add more test cases for failures.
+		{
+			name: "RehashOutdatedHash",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				store.EXPECT().
+					RehashUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					Do(func(_ any, arg db.RehashUserPasswordParams) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, bcryptUser.HashedPassword, arg.OldHashedPassword)
+
+						needsRehash, err := testPasswords.Check(password, arg.NewHashedPassword)
+						require.NoError(t, err)
+						require.False(t, needsRehash)
+					}).
+					Return(int64(1), nil)
+				store.EXPECT().
+					CreateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					RecordAuditFailure(gomock.Any(), gomock.Any(), gomock.Nil()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeInvalidCredentials)
+			},
+		},
+		{
+			// Answered like a username that does not exist
+			name: "SystemUser",
+			body: gin.H{
+				"username": "simplebank",
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("simplebank")).
+					Times(1).
+					Return(db.User{Username: "simplebank", Role: util.SystemRole}, nil)
+				store.EXPECT().
+					RecordAuditFailure(gomock.Any(), gomock.Any(), gomock.Nil()).
+					Times(1).
+					Do(func(_ any, record db.AuditRecord, _ error) {
+						require.Equal(t, "simplebank", record.TargetID)
+						require.Equal(t, util.AuditReasonUnknownUser, record.Reason)
+					}).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeInvalidCredentials)
+			},
+		},
+		{
+			name: "UnusableHash",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				noHashUser := user
+				noHashUser.HashedPassword = ""
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(noHashUser, nil)
+				store.EXPECT().
+					RecordAuditFailure(gomock.Any(), gomock.Any(), gomock.Nil()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeInvalidCredentials)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			body: gin.H{
+				"username": user.Username,
+				"password": "incorrect",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					RecordAuditFailure(gomock.Any(), gomock.Any(), gomock.Nil()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblem(t, recorder, apperr.CodeInvalidCredentials)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+				// The cause stays in the logs
+				problem := requireProblem(t, recorder, apperr.CodeInternal)
+				require.NotContains(t, problem.Detail, sql.ErrConnDone.Error())
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -275,8 +451,8 @@ func randomSession(t *testing.T) db.Session {
 }
 
 func randomUser(t *testing.T) (user db.User, password string) {
-	password = util.RandomString(8)
-	hashedPassword, err := util.HashPassword(password)
+	password = util.RandomPassword()
+	hashedPassword, err := testPasswords.Hash(password)
 	require.NoError(t, err)
 
 	user = db.User{
@@ -284,6 +460,7 @@ func randomUser(t *testing.T) (user db.User, password string) {
 		FullName:          util.RandomString(8),
 		Email:             util.RandomEmail(),
 		HashedPassword:    hashedPassword,
+		Role:              util.DepositorRole,
 		CreatedAt:         time.Now(),
 		PasswordChangedAt: time.Now(),
 	}
@@ -304,3 +481,14 @@ func requireUserBodyMatch(t *testing.T, body *bytes.Buffer, user db.User) {
 	require.WithinDuration(t, user.CreatedAt, gotUser.CreatedAt, time.Second)
 	require.Empty(t, gotUser.HashedPassword)
 }
+
+func requireProblem(t *testing.T, recorder *httptest.ResponseRecorder, code apperr.Code) apperr.Problem {
+	require.Equal(t, apperr.ContentTypeProblem, recorder.Header().Get("Content-Type"))
+
+	var problem apperr.Problem
+	err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+	require.NoError(t, err)
+	require.Equal(t, code, problem.Code)
+	require.Equal(t, recorder.Code, problem.Status)
+	return problem
+}
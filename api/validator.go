@@ -11,3 +11,10 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	}
 	return false
 }
+
+var validAccountType validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if accountType, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsCustomerAccountType(accountType)
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+// Package apperr is the error model shared by the gin and gRPC servers. Every error
+// sent to a client carries a stable code that clients can switch on, and a message
+// that is safe to show: errors nobody classified become INTERNAL with a generic
+// message, so database and library errors never leak out.
+package apperr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Code identifies a kind of error. Codes are part of the API, never rename one.
+type Code string
+
+const (
+	CodeInvalidArgument       Code = "INVALID_ARGUMENT"
+	CodeUnauthenticated       Code = "UNAUTHENTICATED"
+	CodeInvalidCredentials    Code = "INVALID_CREDENTIALS"
+	CodeTokenInvalid          Code = "TOKEN_INVALID"
+	CodeTokenExpired          Code = "TOKEN_EXPIRED"
+	CodePermissionDenied      Code = "PERMISSION_DENIED"
+	CodeNotFound              Code = "NOT_FOUND"
+	CodeAlreadyExists         Code = "ALREADY_EXISTS"
+	CodeFailedPrecondition    Code = "FAILED_PRECONDITION"
+	CodeInsufficientFunds     Code = "INSUFFICIENT_FUNDS"
+	CodeTransferLimitExceeded Code = "TRANSFER_LIMIT_EXCEEDED"
+	CodeCurrencyMismatch      Code = "CURRENCY_MISMATCH"
+	CodeAccountFrozen         Code = "ACCOUNT_FROZEN"
+	CodeAccountClosed         Code = "ACCOUNT_CLOSED"
+	CodeHoldNotActive         Code = "HOLD_NOT_ACTIVE"
+	CodeHoldExpired           Code = "HOLD_EXPIRED"
+	CodeRateLimited           Code = "RATE_LIMITED"
+	CodeCanceled              Code = "CANCELED"
+	CodeDeadlineExceeded      Code = "DEADLINE_EXCEEDED"
+	CodeUnavailable           Code = "UNAVAILABLE"
+	CodeInternal              Code = "INTERNAL"
+)
+
+// kind is how a code is sent over each protocol. The HTTP statuses follow the
+// gateway's mapping of gRPC codes, so both servers answer the same.
+type kind struct {
+	grpc  codes.Code
+	http  int
+	title string
+}
+
+var kinds = map[Code]kind{
+	CodeInvalidArgument:       {codes.InvalidArgument, http.StatusBadRequest, "Invalid argument"},
+	CodeUnauthenticated:       {codes.Unauthenticated, http.StatusUnauthorized, "Unauthenticated"},
+	CodeInvalidCredentials:    {codes.Unauthenticated, http.StatusUnauthorized, "Invalid credentials"},
+	CodeTokenInvalid:          {codes.Unauthenticated, http.StatusUnauthorized, "Invalid token"},
+	CodeTokenExpired:          {codes.Unauthenticated, http.StatusUnauthorized, "Token expired"},
+	CodePermissionDenied:      {codes.PermissionDenied, http.StatusForbidden, "Permission denied"},
+	CodeNotFound:              {codes.NotFound, http.StatusNotFound, "Not found"},
+	CodeAlreadyExists:         {codes.AlreadyExists, http.StatusConflict, "Already exists"},
+	CodeFailedPrecondition:    {codes.FailedPrecondition, http.StatusBadRequest, "Failed precondition"},
+	CodeInsufficientFunds:     {codes.FailedPrecondition, http.StatusBadRequest, "Insufficient funds"},
+	CodeTransferLimitExceeded: {codes.FailedPrecondition, http.StatusBadRequest, "Transfer limit exceeded"},
+	CodeCurrencyMismatch:      {codes.InvalidArgument, http.StatusBadRequest, "Currency mismatch"},
+	CodeAccountFrozen:         {codes.FailedPrecondition, http.StatusBadRequest, "Account frozen"},
+	CodeAccountClosed:         {codes.FailedPrecondition, http.StatusBadRequest, "Account closed"},
+	CodeHoldNotActive:         {codes.FailedPrecondition, http.StatusBadRequest, "Hold not active"},
+	CodeHoldExpired:           {codes.FailedPrecondition, http.StatusBadRequest, "Hold expired"},
+	CodeRateLimited:           {codes.ResourceExhausted, http.StatusTooManyRequests, "Too many requests"},
+	CodeCanceled:              {codes.Canceled, 499, "Request canceled"},
+	CodeDeadlineExceeded:      {codes.DeadlineExceeded, http.StatusGatewayTimeout, "Deadline exceeded"},
+	CodeUnavailable:           {codes.Unavailable, http.StatusServiceUnavailable, "Unavailable"},
+	CodeInternal:              {codes.Internal, http.StatusInternalServerError, "Internal error"},
+}
+
+// Domain errors whose message is safe to send to clients as is
+var sentinels = []struct {
+	err  error
+	code Code
+}{
+	{db.ErrInsufficientFunds, CodeInsufficientFunds},
+	{db.ErrTransferLimitExceeded, CodeTransferLimitExceeded},
+	{db.ErrCurrencyMismatch, CodeCurrencyMismatch},
+	{db.ErrAccountFrozen, CodeAccountFrozen},
+	{db.ErrAccountClosed, CodeAccountClosed},
+	{db.ErrHoldNotActive, CodeHoldNotActive},
+	{db.ErrHoldExpired, CodeHoldExpired},
+	{db.ErrCaptureExceedsHold, CodeFailedPrecondition},
+	{db.ErrPayrollHold, CodeFailedPrecondition},
+	{db.ErrInvalidStatusTransition, CodeFailedPrecondition},
+	{db.ErrNonZeroBalance, CodeFailedPrecondition},
+	{db.ErrAccountHasHolds, CodeFailedPrecondition},
+	{db.ErrInvalidSweepAccount, CodeFailedPrecondition},
+	{db.ErrTransferIsReversal, CodeFailedPrecondition},
+	{db.ErrReversalExceedsTransfer, CodeFailedPrecondition},
+	{db.ErrEmptyBatch, CodeInvalidArgument},
+	{db.ErrInvalidLeg, CodeInvalidArgument},
+	{db.ErrSameAccount, CodeInvalidArgument},
+	{db.ErrAccountNotFound, CodeNotFound},
+	{token.ErrExpiredToken, CodeTokenExpired},
+	{token.ErrInvalidToken, CodeTokenInvalid},
+}
+
+// Error is an error meant for clients.
+type Error struct {
+	Code    Code
+	Message string
+	// Invalid fields, for INVALID_ARGUMENT
+	Violations []Violation
+	// Details clients may act on, like how much of a limit remains
+	Metadata map[string]string
+	// Only logged, never sent
+	cause error
+	// Other details of a status error, like RetryInfo, sent on as they are
+	details []protoadapt.MessageV1
+}
+
+// Violation tells what is wrong with a field of the request.
+type Violation struct {
+	Field       string `json:"name"`
+	Description string `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %s", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Cause returns the underlying error, to be logged.
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+func (e *Error) kind() kind {
+	if k, ok := kinds[e.Code]; ok {
+		return k
+	}
+	return kinds[CodeInternal]
+}
+
+// New creates an error with a message safe to send to clients.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf is New with formatting.
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap creates an error sent to clients with message, keeping err for the logs.
+func Wrap(code Code, err error, message string) *Error {
+	return &Error{Code: code, Message: message, cause: err}
+}
+
+// InvalidArgument reports the fields that failed validation.
+func InvalidArgument(violations ...Violation) *Error {
+	return &Error{
+		Code:       CodeInvalidArgument,
+		Message:    "invalid parameters",
+		Violations: violations,
+	}
+}
+
+// Internal hides err from clients.
+func Internal(err error) *Error {
+	return Wrap(CodeInternal, err, "internal error")
+}
+
+// From classifies any error. This is the one place errors of the db and token
+// packages, Postgres and contexts get their code.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	if st, ok := statusFromError(err); ok {
+		return FromStatus(st)
+	}
+
+	var limitErr *db.TransferLimitError
+	if errors.As(err, &limitErr) {
+		appErr := Wrap(CodeTransferLimitExceeded, err, limitErr.Error())
+		appErr.Metadata = map[string]string{
+			"limit":     limitErr.Limit,
+			"currency":  limitErr.Currency,
+			"max":       strconv.FormatInt(limitErr.Max, 10),
+			"remaining": strconv.FormatInt(limitErr.Remaining, 10),
+		}
+		return appErr
+	}
+
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel.err) {
+			// Only the sentinel's own message is sent, the context wrapped around it
+			// may name internals and is only logged as the cause
+			return Wrap(sentinel.code, err, sentinel.err.Error())
+		}
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Wrap(CodeNotFound, err, "resource not found")
+	case errors.Is(err, context.Canceled):
+		return Wrap(CodeCanceled, err, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(CodeDeadlineExceeded, err, "request took too long")
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return Wrap(CodeAlreadyExists, err, "resource already exists")
+		case "foreign_key_violation":
+			return Wrap(CodeFailedPrecondition, err, "referenced resource does not exist")
+		case "check_violation":
+			return Wrap(CodeInvalidArgument, err, "invalid value")
+		}
+	}
+
+	return Internal(err)
+}
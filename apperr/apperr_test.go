@@ -0,0 +1,204 @@
+package apperr
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFrom(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+	}{
+		{
+			name:    "DomainError",
+			err:     fmt.Errorf("cannot capture transfer: %w", db.ErrHoldExpired),
+			code:    CodeHoldExpired,
+			message: db.ErrHoldExpired.Error(),
+		},
+		{
+			name:    "Precondition",
+			err:     db.ErrNonZeroBalance,
+			code:    CodeFailedPrecondition,
+			message: db.ErrNonZeroBalance.Error(),
+		},
+		{
+			name:    "ExpiredToken",
+			err:     fmt.Errorf("invalid access token: %w", token.ErrExpiredToken),
+			code:    CodeTokenExpired,
+			message: token.ErrExpiredToken.Error(),
+		},
+		{
+			name:    "NoRows",
+			err:     sql.ErrNoRows,
+			code:    CodeNotFound,
+			message: "resource not found",
+		},
+		{
+			name:    "UniqueViolation",
+			err:     &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_key"`},
+			code:    CodeAlreadyExists,
+			message: "resource already exists",
+		},
+		{
+			name:    "DeadlineExceeded",
+			err:     fmt.Errorf("cannot get account: %w", context.DeadlineExceeded),
+			code:    CodeDeadlineExceeded,
+			message: "request took too long",
+		},
+		{
+			name:    "StatusWithoutErrorInfo",
+			err:     status.Errorf(codes.NotFound, "account %d not found", 1),
+			code:    CodeNotFound,
+			message: "account 1 not found",
+		},
+		{
+			name:    "InternalStatus",
+			err:     status.Errorf(codes.Internal, "failed to get account: %s", "connection refused"),
+			code:    CodeInternal,
+			message: "internal error",
+		},
+		{
+			name:    "Unknown",
+			err:     errors.New("pq: relation \"accounts\" does not exist"),
+			code:    CodeInternal,
+			message: "internal error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appErr := From(tc.err)
+			require.Equal(t, tc.code, appErr.Code)
+			require.Equal(t, tc.message, appErr.Message)
+		})
+	}
+}
+
+func TestFromKeepsCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	appErr := From(fmt.Errorf("cannot get user: %w", cause))
+
+	require.Equal(t, CodeInternal, appErr.Code)
+	require.ErrorIs(t, appErr, cause)
+	require.Contains(t, appErr.Error(), "connection refused")
+
+	// The context around a sentinel is logged, never sent
+	appErr = From(fmt.Errorf("cannot capture hold 42 of account 7: %w", db.ErrHoldExpired))
+	require.Equal(t, db.ErrHoldExpired.Error(), appErr.Message)
+	require.ErrorIs(t, appErr, db.ErrHoldExpired)
+	require.Contains(t, appErr.Error(), "cannot capture hold 42 of account 7")
+}
+
+func TestFromTransferLimitError(t *testing.T) {
+	err := &db.TransferLimitError{
+		Limit:     db.LimitDaily,
+		Currency:  "USD",
+		Max:       100,
+		Remaining: 5,
+	}
+
+	appErr := From(fmt.Errorf("cannot transfer: %w", err))
+	require.Equal(t, CodeTransferLimitExceeded, appErr.Code)
+	require.Equal(t, "5", appErr.Metadata["remaining"])
+	require.Equal(t, "100", appErr.Metadata["max"])
+}
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	appErr := InvalidArgument(Violation{Field: "username", Description: "must not be empty"})
+
+	st, ok := status.FromError(appErr)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "invalid parameters", st.Message())
+
+	var errorInfo *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			errorInfo = info
+		}
+	}
+	require.NotNil(t, errorInfo)
+	require.Equal(t, string(CodeInvalidArgument), errorInfo.GetReason())
+	require.Equal(t, Domain, errorInfo.GetDomain())
+
+	// What a client, or the gateway, gets back is the same error
+	got := FromStatus(st)
+	require.Equal(t, appErr.Code, got.Code)
+	require.Equal(t, appErr.Message, got.Message)
+	require.Equal(t, appErr.Violations, got.Violations)
+}
+
+func TestFromStatusKeepsReason(t *testing.T) {
+	// The code says more than the gRPC status, which several codes share
+	st := New(CodeInvalidCredentials, "incorrect username or password").GRPCStatus()
+	require.Equal(t, codes.Unauthenticated, st.Code())
+	require.Equal(t, CodeInvalidCredentials, FromStatus(st).Code)
+}
+
+func TestFromStatusKeepsOtherDetails(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "too many requests").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Second),
+	})
+	require.NoError(t, err)
+
+	appErr := From(st.Err())
+	require.Equal(t, CodeRateLimited, appErr.Code)
+
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range appErr.GRPCStatus().Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	require.NotNil(t, retryInfo)
+	require.Equal(t, time.Second, retryInfo.GetRetryDelay().AsDuration())
+}
+
+func TestWriteProblem(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/transfers", nil)
+
+	WriteProblem(recorder, request, fmt.Errorf("account 1: %w", db.ErrAccountFrozen))
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, ContentTypeProblem, recorder.Header().Get("Content-Type"))
+
+	var problem Problem
+	err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+	require.NoError(t, err)
+	require.Equal(t, "urn:simplebank:error:account_frozen", problem.Type)
+	require.Equal(t, "Account frozen", problem.Title)
+	require.Equal(t, http.StatusBadRequest, problem.Status)
+	require.Equal(t, CodeAccountFrozen, problem.Code)
+	require.Equal(t, "/transfers", problem.Instance)
+}
+
+func TestEveryCodeHasAKind(t *testing.T) {
+	for _, sentinel := range sentinels {
+		_, ok := kinds[sentinel.code]
+		require.True(t, ok, sentinel.code)
+	}
+	for _, code := range grpcCodes {
+		_, ok := kinds[code]
+		require.True(t, ok, code)
+	}
+}
@@ -0,0 +1,111 @@
+package apperr
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the ErrorInfo domain of every error of the API
+const Domain = "simplebank"
+
+// Codes given to status errors that carry no ErrorInfo
+var grpcCodes = map[codes.Code]Code{
+	codes.InvalidArgument:    CodeInvalidArgument,
+	codes.OutOfRange:         CodeInvalidArgument,
+	codes.Unauthenticated:    CodeUnauthenticated,
+	codes.PermissionDenied:   CodePermissionDenied,
+	codes.NotFound:           CodeNotFound,
+	codes.AlreadyExists:      CodeAlreadyExists,
+	codes.FailedPrecondition: CodeFailedPrecondition,
+	codes.ResourceExhausted:  CodeRateLimited,
+	codes.Canceled:           CodeCanceled,
+	codes.DeadlineExceeded:   CodeDeadlineExceeded,
+	codes.Unavailable:        CodeUnavailable,
+}
+
+func statusFromError(err error) (*status.Status, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return nil, false
+	}
+	return grpcErr.GRPCStatus(), true
+}
+
+// FromStatus classifies a status error, by its ErrorInfo when it has one and
+// otherwise by its code. The message of an internal error is never kept.
+func FromStatus(st *status.Status) *Error {
+	appErr := &Error{Message: st.Message()}
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() == Domain {
+				appErr.Code = Code(detail.GetReason())
+				appErr.Metadata = detail.GetMetadata()
+			}
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				appErr.Violations = append(appErr.Violations, Violation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		case protoadapt.MessageV1:
+			appErr.details = append(appErr.details, detail)
+		}
+	}
+
+	if appErr.Code == "" {
+		code, ok := grpcCodes[st.Code()]
+		if !ok {
+			return Internal(st.Err())
+		}
+		appErr.Code = code
+	}
+
+	if _, ok := kinds[appErr.Code]; !ok || appErr.Code == CodeInternal {
+		return Internal(st.Err())
+	}
+	return appErr
+}
+
+// GRPCCode returns the gRPC code the error is sent with.
+func (e *Error) GRPCCode() codes.Code {
+	return e.kind().grpc
+}
+
+// GRPCStatus returns the status sent to gRPC clients, with an ErrorInfo holding the
+// code and a BadRequest listing the violations. It lets status.FromError and the
+// gRPC server use an *Error directly.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.GRPCCode(), e.Message)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   string(e.Code),
+			Domain:   Domain,
+			Metadata: e.Metadata,
+		},
+	}
+	if len(e.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, violation := range e.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	details = append(details, e.details...)
+
+	statusDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return statusDetails
+}
@@ -0,0 +1,61 @@
+package apperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ContentTypeProblem is the media type of problem details (RFC 9457)
+const ContentTypeProblem = "application/problem+json"
+
+// typePrefix makes the problem type of each code a stable URI
+const typePrefix = "urn:simplebank:error:"
+
+// Problem is the body of HTTP error responses, as described by RFC 9457. Code is
+// the same as the ErrorInfo reason sent to gRPC clients.
+type Problem struct {
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Status        int               `json:"status"`
+	Detail        string            `json:"detail,omitempty"`
+	Instance      string            `json:"instance,omitempty"`
+	Code          Code              `json:"code"`
+	InvalidParams []Violation       `json:"invalid_params,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// HTTPStatus returns the HTTP status the error is sent with.
+func (e *Error) HTTPStatus() int {
+	return e.kind().http
+}
+
+// Problem returns the problem details of the error. instance is usually the path
+// of the request.
+func (e *Error) Problem(instance string) Problem {
+	kind := e.kind()
+	code := e.Code
+	if _, ok := kinds[code]; !ok {
+		code = CodeInternal
+	}
+
+	return Problem{
+		Type:          typePrefix + strings.ToLower(string(code)),
+		Title:         kind.title,
+		Status:        kind.http,
+		Detail:        e.Message,
+		Instance:      instance,
+		Code:          code,
+		InvalidParams: e.Violations,
+		Metadata:      e.Metadata,
+	}
+}
+
+// WriteProblem writes err as problem details.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := From(err)
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(appErr.HTTPStatus())
+	json.NewEncoder(w).Encode(appErr.Problem(r.URL.Path))
+}
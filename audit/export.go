@@ -0,0 +1,81 @@
+// Package audit renders exports of the audit log.
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of a rendered export.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
+
+// Filename suggests a name for the downloaded export.
+func Filename(exportedAt time.Time, format string) string {
+	return fmt.Sprintf("audit-events-%s.%s", exportedAt.UTC().Format("20060102T150405Z"), format)
+}
+
+// Render writes the events to w in the given format, in the order given.
+func Render(w io.Writer, events []db.AuditEvent, format string) error {
+	switch format {
+	case FormatCSV:
+		return renderCSV(w, events)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(events)
+	}
+	return fmt.Errorf("unsupported audit export format %q", format)
+}
+
+func renderCSV(w io.Writer, events []db.AuditEvent) error {
+	/** One row per event. The before and after columns hold the changed fields as
+	JSON objects. */
+	writer := csv.NewWriter(w)
+
+	records := [][]string{
+		{"id", "created_at", "actor", "action", "target_type", "target_id", "outcome", "reason", "client_ip", "user_agent", "before", "after"},
+	}
+	for _, event := range events {
+		records = append(records, []string{
+			strconv.FormatInt(event.ID, 10),
+			event.CreatedAt.UTC().Format(time.RFC3339Nano),
+			event.Actor,
+			event.Action,
+			event.TargetType,
+			event.TargetID,
+			event.Outcome,
+			event.Reason,
+			event.ClientIp,
+			event.UserAgent,
+			string(event.Before),
+			string(event.After),
+		})
+	}
+
+	return writer.WriteAll(records)
+}
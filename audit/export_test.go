@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvents(n int) []db.AuditEvent {
+	events := make([]db.AuditEvent, n)
+	for i := range events {
+		events[i] = db.AuditEvent{
+			ID:         int64(n - i),
+			Actor:      util.RandomOwner(),
+			Action:     util.AuditUserUpdated,
+			TargetType: util.AuditTargetUser,
+			TargetID:   util.RandomOwner(),
+			Outcome:    util.AuditSuccess,
+			ClientIp:   "203.0.113.7",
+			UserAgent:  "curl/8.0",
+			Before:     json.RawMessage(`{"email":"old@example.com"}`),
+			After:      json.RawMessage(`{"email":"new@example.com"}`),
+			CreatedAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return events
+}
+
+func TestRenderCSV(t *testing.T) {
+	events := randomEvents(3)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, events, FormatCSV))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1+3)
+	require.Equal(t, "id", records[0][0])
+
+	require.Equal(t, "3", records[1][0])
+	require.Equal(t, "2025-01-01T00:00:00Z", records[1][1])
+	require.Equal(t, events[0].Actor, records[1][2])
+	require.Equal(t, `{"email":"old@example.com"}`, records[1][10])
+	require.Equal(t, `{"email":"new@example.com"}`, records[1][11])
+}
+
+func TestRenderJSON(t *testing.T) {
+	events := randomEvents(2)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, events, FormatJSON))
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got, 2)
+	require.Equal(t, events[1].Actor, got[1]["actor"])
+	require.Equal(t, map[string]any{"email": "new@example.com"}, got[0]["after"])
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	require.False(t, IsSupportedFormat("pdf"))
+	require.Error(t, Render(&bytes.Buffer{}, nil, "pdf"))
+}
+
+func TestFilename(t *testing.T) {
+	exportedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	require.Equal(t, "audit-events-20250304T050607Z.csv", Filename(exportedAt, FormatCSV))
+	require.Equal(t, "application/json", ContentType(FormatJSON))
+}
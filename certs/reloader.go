@@ -0,0 +1,210 @@
+// Package certs provides the TLS configuration of the servers, reloading the
+// certificates whenever their files change so they can be rotated without a restart.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// Files are often replaced in several steps, a rename for the cert and another for
+// the key, so reloading waits for things to settle down first
+const reloadDelay = 500 * time.Millisecond
+
+var ErrNoCertificates = errors.New("no certificates found in CA bundle")
+
+// Reloader holds the current key pair, and the client CA bundle when clients must
+// present a certificate.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	certificate atomic.Pointer[tls.Certificate]
+	clientCAs   atomic.Pointer[x509.CertPool]
+}
+
+// NewReloader loads the key pair and, if clientCAFile is not empty, the CA bundle
+// client certificates are verified against.
+func NewReloader(certFile string, keyFile string, clientCAFile string) (*Reloader, error) {
+	reloader := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+
+	err := reloader.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Reload reads the files again. Nothing changes if any of them is invalid, so a
+// half-written file never replaces a working certificate.
+func (reloader *Reloader) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if reloader.clientCAFile != "" {
+		clientCAs, err = LoadCertPool(reloader.clientCAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	reloader.certificate.Store(&certificate)
+	reloader.clientCAs.Store(clientCAs)
+	return nil
+}
+
+// MutualTLS tells whether clients must present a certificate.
+func (reloader *Reloader) MutualTLS() bool {
+	return reloader.clientCAFile != ""
+}
+
+// Certificate returns the current key pair.
+func (reloader *Reloader) Certificate() *tls.Certificate {
+	return reloader.certificate.Load()
+}
+
+// ServerConfig returns a configuration that always uses the latest files. With
+// requireClientCert, clients must present a certificate signed by the client CA
+// bundle. It is ignored when there is no such bundle.
+func (reloader *Reloader) ServerConfig(requireClientCert bool) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return reloader.Certificate(), nil
+		},
+	}
+
+	// The standard verification only knows a fixed ClientCAs pool, so the chain is
+	// verified here against whichever bundle is current
+	if requireClientCert && reloader.MutualTLS() {
+		config.ClientAuth = tls.RequireAnyClientCert
+		config.VerifyPeerCertificate = reloader.verifyClientCertificate
+	}
+	return config
+}
+
+func (reloader *Reloader) verifyClientCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	certificates := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		certificate, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+		certificates[i] = certificate
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         reloader.clientCAs.Load(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// ClientConfig returns a configuration for connecting to a server whose certificate
+// is signed by rootCAs. The current key pair is presented as client certificate
+// when the server asks for one, so it must also be valid for client authentication.
+func (reloader *Reloader) ClientConfig(rootCAs *x509.CertPool, serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.Certificate(), nil
+		},
+	}
+}
+
+// Watch reloads the files whenever they change, until ctx is cancelled.
+func (reloader *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot watch certificates: %w", err)
+	}
+	defer watcher.Close()
+
+	// Directories are watched rather than the files, since mounted secrets are
+	// replaced by swapping a symlink, which a watch on the file would not notice
+	dirs := map[string]bool{}
+	for _, file := range []string{reloader.certFile, reloader.keyFile, reloader.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("cannot watch %s: %w", dir, err)
+		}
+	}
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			timer.Reset(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error().Err(err).Msg("certificate watcher")
+		case <-timer.C:
+			if err := reloader.Reload(); err != nil {
+				log.Error().Err(err).Msg("cannot reload certificates, keeping the previous ones")
+				continue
+			}
+			log.Info().Msg("reloaded certificates")
+		}
+	}
+}
+
+// LoadCertPool reads a PEM bundle of CA certificates.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%s: %w", file, ErrNoCertificates)
+	}
+	return pool, nil
+}
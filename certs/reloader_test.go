@@ -0,0 +1,245 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return testCA{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a key pair for localhost that is valid for both server and client
+// authentication, like the one the gateway uses to reach the gRPC server.
+func (ca testCA) issue(t *testing.T, serial int64) (certPEM []byte, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return
+}
+
+func writeFile(t *testing.T, file string, data []byte) {
+	require.NoError(t, os.WriteFile(file, data, 0600))
+}
+
+func serialOf(t *testing.T, certificate *tls.Certificate) int64 {
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	return leaf.SerialNumber.Int64()
+}
+
+// handshake connects over TCP so that an alert sent by one side never blocks. With
+// TLS 1.3 the client is done before the server has checked its certificate, so the
+// server's verdict is the one that counts.
+func handshake(serverConfig *tls.Config, clientConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+		errs <- tls.Server(conn, serverConfig).Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	clientErr := tls.Client(conn, clientConfig).Handshake()
+	if serverErr := <-errs; serverErr != nil {
+		return serverErr
+	}
+	return clientErr
+}
+
+func TestReload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	certPEM, keyPEM := ca.issue(t, 2)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	reloader, err := NewReloader(certFile, keyFile, "")
+	require.NoError(t, err)
+	require.False(t, reloader.MutualTLS())
+	require.Equal(t, int64(2), serialOf(t, reloader.Certificate()))
+
+	certPEM, keyPEM = ca.issue(t, 3)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	require.NoError(t, reloader.Reload())
+	require.Equal(t, int64(3), serialOf(t, reloader.Certificate()))
+
+	// A key that does not match keeps the previous pair in use
+	_, otherKeyPEM := ca.issue(t, 4)
+	writeFile(t, keyFile, otherKeyPEM)
+	require.Error(t, reloader.Reload())
+	require.Equal(t, int64(3), serialOf(t, reloader.Certificate()))
+}
+
+func TestNewReloaderInvalidCA(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	certPEM, keyPEM := ca.issue(t, 2)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, []byte("not a certificate"))
+
+	_, err := NewReloader(certFile, keyFile, caFile)
+	require.ErrorIs(t, err, ErrNoCertificates)
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	certPEM, keyPEM := ca.issue(t, 2)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, ca.pem)
+
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	require.True(t, reloader.MutualTLS())
+
+	rootCAs, err := LoadCertPool(caFile)
+	require.NoError(t, err)
+
+	// The gateway presents the same key pair as its client certificate
+	clientConfig := reloader.ClientConfig(rootCAs, "localhost")
+	require.NoError(t, handshake(reloader.ServerConfig(true), clientConfig))
+
+	// Without a certificate only the server that does not ask for one accepts the client
+	anonymous := &tls.Config{RootCAs: rootCAs, ServerName: "localhost"}
+	require.Error(t, handshake(reloader.ServerConfig(true), anonymous))
+	require.NoError(t, handshake(reloader.ServerConfig(false), anonymous))
+
+	// A certificate from another CA is refused
+	otherCA := newTestCA(t)
+	otherCertPEM, otherKeyPEM := otherCA.issue(t, 5)
+	otherCertificate, err := tls.X509KeyPair(otherCertPEM, otherKeyPEM)
+	require.NoError(t, err)
+	stranger := &tls.Config{
+		RootCAs:      rootCAs,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{otherCertificate},
+	}
+	require.Error(t, handshake(reloader.ServerConfig(true), stranger))
+
+	// Until that CA is added to the bundle
+	writeFile(t, caFile, append(ca.pem, otherCA.pem...))
+	require.NoError(t, reloader.Reload())
+	require.NoError(t, handshake(reloader.ServerConfig(true), stranger))
+}
+
+func TestWatch(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	certPEM, keyPEM := ca.issue(t, 2)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	reloader, err := NewReloader(certFile, keyFile, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- reloader.Watch(ctx)
+	}()
+	// Gives the watcher time to start
+	time.Sleep(50 * time.Millisecond)
+
+	certPEM, keyPEM = ca.issue(t, 3)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	require.Eventually(t, func() bool {
+		return serialOf(t, reloader.Certificate()) == 3
+	}, 5*time.Second, 20*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "transfers" DROP CONSTRAINT IF EXISTS "transfers_reversal_of_fkey";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversal_of";
@@ -0,0 +1,27 @@
+CREATE TABLE "account_holds" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "released_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_holds" ("from_account_id", "status");
+
+CREATE INDEX ON "account_holds" ("status", "expires_at");
+
+COMMENT ON COLUMN "account_holds"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "account_holds"."status" IS 'active, captured, voided or expired';
+
+COMMENT ON COLUMN "account_holds"."transfer_id" IS 'set once the hold is captured';
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
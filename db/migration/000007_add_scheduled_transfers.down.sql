@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "scheduled_transfer_runs";
+
+DROP TABLE IF EXISTS "scheduled_transfers";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "idempotency_key";
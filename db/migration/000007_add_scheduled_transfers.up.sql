@@ -0,0 +1,53 @@
+ALTER TABLE "transfers" ADD COLUMN "idempotency_key" varchar UNIQUE;
+
+COMMENT ON COLUMN "transfers"."idempotency_key" IS 'retrying a transfer with the same key returns the original one';
+
+CREATE TABLE "scheduled_transfers" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "schedule" varchar NOT NULL,
+  "next_run_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "failure_policy" varchar NOT NULL DEFAULT 'skip',
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "idempotency_key" varchar UNIQUE NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("from_account_id");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
+
+COMMENT ON COLUMN "scheduled_transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "scheduled_transfers"."schedule" IS 'cron expression or descriptor such as @monthly';
+
+COMMENT ON COLUMN "scheduled_transfers"."end_at" IS 'no occurrences are scheduled after this time';
+
+COMMENT ON COLUMN "scheduled_transfers"."failure_policy" IS 'skip or pause';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, paused, cancelled or completed';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded or failed';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
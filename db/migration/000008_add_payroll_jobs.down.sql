@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "payroll_job_rows";
+
+DROP TABLE IF EXISTS "payroll_jobs";
@@ -0,0 +1,52 @@
+CREATE TABLE "payroll_jobs" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "total_rows" integer NOT NULL,
+  "processed_rows" integer NOT NULL DEFAULT 0,
+  "error" varchar,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "payroll_job_rows" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "job_id" bigint NOT NULL,
+  "row_number" integer NOT NULL,
+  "to_account_id" bigint,
+  "username" varchar,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "error" varchar
+);
+
+CREATE INDEX ON "payroll_jobs" ("owner");
+
+CREATE INDEX ON "payroll_jobs" ("status");
+
+CREATE UNIQUE INDEX ON "payroll_job_rows" ("job_id", "row_number");
+
+CREATE INDEX ON "payroll_job_rows" ("job_id", "status");
+
+COMMENT ON COLUMN "payroll_jobs"."status" IS 'pending, validating, invalid, processing, completed or failed';
+
+COMMENT ON COLUMN "payroll_jobs"."error" IS 'why the job is invalid or failed';
+
+COMMENT ON COLUMN "payroll_job_rows"."row_number" IS 'line in the uploaded file, the header is line 1';
+
+COMMENT ON COLUMN "payroll_job_rows"."to_account_id" IS 'resolved from username when not given';
+
+COMMENT ON COLUMN "payroll_job_rows"."status" IS 'pending, valid, invalid or paid';
+
+ALTER TABLE "payroll_jobs" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payroll_jobs" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payroll_job_rows" ADD FOREIGN KEY ("job_id") REFERENCES "payroll_jobs" ("id");
+
+ALTER TABLE "payroll_job_rows" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
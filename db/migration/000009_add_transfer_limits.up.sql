@@ -0,0 +1,28 @@
+CREATE TABLE "transfer_limits" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "username" varchar,
+  "currency" varchar NOT NULL,
+  "per_transfer" bigint NOT NULL,
+  "daily" bigint NOT NULL,
+  "monthly" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("currency") WHERE "username" IS NULL;
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("username", "currency") WHERE "username" IS NOT NULL;
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'override for one user, null for the currency default';
+
+COMMENT ON COLUMN "transfer_limits"."daily" IS 'rolling 24 hours of outgoing transfers';
+
+COMMENT ON COLUMN "transfer_limits"."monthly" IS 'outgoing transfers since the start of the calendar month (UTC)';
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+INSERT INTO "transfer_limits" ("currency", "per_transfer", "daily", "monthly") VALUES
+  ('USD', 1000000, 5000000, 50000000),
+  ('EUR', 1000000, 5000000, 50000000),
+  ('CAD', 1000000, 5000000, 50000000);
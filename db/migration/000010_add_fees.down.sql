@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "transfer_fees";
+
+DROP TABLE IF EXISTS "fee_schedules";
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'simplebank');
+
+DELETE FROM "accounts" WHERE "owner" = 'simplebank';
+
+DELETE FROM "users" WHERE "username" = 'simplebank';
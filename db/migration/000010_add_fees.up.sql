@@ -0,0 +1,54 @@
+CREATE TABLE "fee_schedules" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "operation" varchar NOT NULL,
+  "flat_fee" bigint NOT NULL DEFAULT 0 CHECK ("flat_fee" >= 0),
+  "percentage_bps" integer NOT NULL DEFAULT 0 CHECK ("percentage_bps" BETWEEN 0 AND 10000),
+  "free_per_month" integer NOT NULL DEFAULT 0 CHECK ("free_per_month" >= 0),
+  "revenue_account_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_fees" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "fee_schedule_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "revenue_entry_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("currency", "operation");
+
+CREATE INDEX ON "transfer_fees" ("transfer_id");
+
+COMMENT ON COLUMN "fee_schedules"."percentage_bps" IS 'hundredths of a percent of the amount, rounded down';
+
+COMMENT ON COLUMN "fee_schedules"."free_per_month" IS 'operations per calendar month (UTC) that are not charged';
+
+COMMENT ON COLUMN "transfer_fees"."kind" IS 'flat or percentage';
+
+COMMENT ON COLUMN "transfer_fees"."entry_id" IS 'debit on the paying account';
+
+COMMENT ON COLUMN "transfer_fees"."revenue_entry_id" IS 'credit on the revenue account';
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("revenue_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("fee_schedule_id") REFERENCES "fee_schedules" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("revenue_entry_id") REFERENCES "entries" ("id");
+
+-- The bank cannot log in as this user, it only owns the revenue accounts
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('simplebank', '', 'Simple Bank', 'revenue@simplebank.invalid');
+
+INSERT INTO "accounts" ("owner", "balance", "currency") VALUES
+  ('simplebank', 0, 'USD'),
+  ('simplebank', 0, 'EUR'),
+  ('simplebank', 0, 'CAD');
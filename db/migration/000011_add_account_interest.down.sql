@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DROP TABLE IF EXISTS "interest_postings";
+
+DROP TABLE IF EXISTS "interest_rates";
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'simplebank' AND "type" = 'expense');
+
+DELETE FROM "accounts" WHERE "owner" = 'simplebank' AND "type" = 'expense';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_type_key";
+
+-- Fails if a customer has both a checking and a savings account in one currency
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE "accounts" DROP COLUMN "type";
@@ -0,0 +1,83 @@
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking'
+  CHECK ("type" IN ('checking', 'savings', 'revenue', 'expense'));
+
+COMMENT ON COLUMN "accounts"."type" IS 'checking or savings, revenue and expense are bank ledger accounts';
+
+-- The fee accounts were the only bank accounts so far
+UPDATE "accounts" SET "type" = 'revenue' WHERE "owner" = 'simplebank';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_type_key" UNIQUE ("owner", "currency", "type");
+
+CREATE TABLE "interest_rates" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "account_type" varchar NOT NULL,
+  "annual_rate" numeric NOT NULL CHECK ("annual_rate" BETWEEN 0 AND 1),
+  "day_count" varchar NOT NULL,
+  "expense_account_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_accruals" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate" numeric NOT NULL,
+  "day_count" varchar NOT NULL,
+  "interest_numerator" numeric NOT NULL,
+  "interest_denominator" numeric NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_postings" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "remainder_numerator" numeric NOT NULL,
+  "remainder_denominator" numeric NOT NULL,
+  "entry_id" bigint,
+  "expense_entry_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_rates" ("currency", "account_type");
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("account_id", "posting_id");
+
+CREATE INDEX ON "interest_postings" ("account_id");
+
+COMMENT ON COLUMN "interest_rates"."annual_rate" IS 'decimal fraction, 0.0425 is 4.25%';
+
+COMMENT ON COLUMN "interest_rates"."day_count" IS 'ACT/365, ACT/360 or ACT/ACT';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance at the end of accrual_date (UTC)';
+
+COMMENT ON COLUMN "interest_accruals"."interest_numerator" IS 'exact interest in minor units is interest_numerator / interest_denominator';
+
+COMMENT ON COLUMN "interest_accruals"."posting_id" IS 'null until the interest is paid out';
+
+COMMENT ON COLUMN "interest_postings"."amount" IS 'whole minor units paid to the account';
+
+COMMENT ON COLUMN "interest_postings"."remainder_numerator" IS 'fraction of a minor unit carried to the next posting';
+
+ALTER TABLE "interest_rates" ADD FOREIGN KEY ("expense_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("expense_entry_id") REFERENCES "entries" ("id");
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "type") VALUES
+  ('simplebank', 0, 'USD', 'expense'),
+  ('simplebank', 0, 'EUR', 'expense'),
+  ('simplebank', 0, 'CAD', 'expense');
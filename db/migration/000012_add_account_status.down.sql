@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "account_status_changes";
+
+ALTER TABLE "accounts" DROP COLUMN "credits_frozen";
+
+ALTER TABLE "accounts" DROP COLUMN "status";
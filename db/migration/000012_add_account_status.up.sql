@@ -0,0 +1,31 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active'
+  CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+ALTER TABLE "accounts" ADD COLUMN "credits_frozen" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed';
+
+COMMENT ON COLUMN "accounts"."credits_frozen" IS 'a frozen account also rejects incoming transfers';
+
+CREATE TABLE "account_status_changes" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "sweep_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_status_changes" ("account_id");
+
+COMMENT ON COLUMN "account_status_changes"."changed_by" IS 'user who made the change';
+
+COMMENT ON COLUMN "account_status_changes"."sweep_transfer_id" IS 'transfer that emptied the account before it was closed';
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("username");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("sweep_transfer_id") REFERENCES "transfers" ("id");
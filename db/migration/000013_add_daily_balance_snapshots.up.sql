@@ -0,0 +1,15 @@
+CREATE TABLE "daily_balance_snapshots" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "snapshot_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "daily_balance_snapshots" ("account_id", "snapshot_date");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+COMMENT ON COLUMN "daily_balance_snapshots"."balance" IS 'balance at the end of snapshot_date (UTC)';
+
+ALTER TABLE "daily_balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,54 @@
+CREATE TABLE "outbox_events" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_endpoints" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "endpoint_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending'
+    CHECK ("status" IN ('pending', 'delivered', 'dead')),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_status_code" integer,
+  "last_error" varchar,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox_events" ("id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox_events" ("created_at");
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("endpoint_id", "event_id");
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+COMMENT ON COLUMN "outbox_events"."event_type" IS 'transfer.created, account.created or user.updated';
+
+COMMENT ON COLUMN "outbox_events"."published_at" IS 'null until a delivery was queued for every matching endpoint';
+
+COMMENT ON COLUMN "webhook_endpoints"."secret" IS 'key of the HMAC-SHA256 signature sent with every delivery';
+
+COMMENT ON COLUMN "webhook_endpoints"."event_types" IS 'empty means every event';
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, delivered or dead';
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox_events" ("id");
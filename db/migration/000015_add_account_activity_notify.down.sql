@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "accounts_notify_activity" ON "accounts";
+
+DROP TRIGGER IF EXISTS "entries_notify_activity" ON "entries";
+
+DROP FUNCTION IF EXISTS notify_account_activity();
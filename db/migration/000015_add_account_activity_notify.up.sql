@@ -0,0 +1,26 @@
+-- Pushes every new entry and every change to an account's balance or status to
+-- listeners of the account_activity channel. Notifications are only sent when the
+-- transaction commits, so listeners never see changes that were rolled back.
+CREATE FUNCTION notify_account_activity() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify(
+    'account_activity',
+    json_build_object('type', TG_ARGV[0], 'data', row_to_json(NEW))::text
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entries_notify_activity"
+AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_account_activity('entry');
+
+CREATE TRIGGER "accounts_notify_activity"
+AFTER UPDATE ON "accounts"
+FOR EACH ROW
+WHEN (
+  OLD.balance IS DISTINCT FROM NEW.balance OR
+  OLD.status IS DISTINCT FROM NEW.status OR
+  OLD.credits_frozen IS DISTINCT FROM NEW.credits_frozen
+)
+EXECUTE FUNCTION notify_account_activity('account');
@@ -0,0 +1,12 @@
+-- Token buckets shared by every replica, stored as the time the bucket will be
+-- full again (GCRA). A bucket whose time has passed is full and can be deleted.
+CREATE TABLE "rate_limits" (
+  "key" varchar PRIMARY KEY,
+  "full_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "rate_limits" ("full_at");
+
+COMMENT ON COLUMN "rate_limits"."key" IS 'method and user or client IP the limit applies to';
+
+COMMENT ON COLUMN "rate_limits"."full_at" IS 'when all tokens will have been refilled';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "audit_events";
+
+DROP FUNCTION IF EXISTS reject_audit_event_change();
@@ -0,0 +1,45 @@
+CREATE TABLE "audit_events" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "target_type" varchar NOT NULL,
+  "target_id" varchar NOT NULL,
+  "outcome" varchar NOT NULL CHECK ("outcome" IN ('success', 'failure')),
+  "reason" varchar NOT NULL DEFAULT '',
+  "client_ip" varchar NOT NULL DEFAULT '',
+  "user_agent" varchar NOT NULL DEFAULT '',
+  "before" jsonb NOT NULL DEFAULT '{}',
+  "after" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_events" ("created_at");
+
+CREATE INDEX ON "audit_events" ("actor", "id");
+
+CREATE INDEX ON "audit_events" ("target_type", "target_id", "id");
+
+COMMENT ON COLUMN "audit_events"."actor" IS 'username, anonymous for callers without a token or system for background work';
+
+COMMENT ON COLUMN "audit_events"."action" IS 'user.login, user.updated, account.created, account.closed, account.status_changed or transfer.created';
+
+COMMENT ON COLUMN "audit_events"."reason" IS 'why the action failed, or why an account status changed';
+
+COMMENT ON COLUMN "audit_events"."before" IS 'fields that changed, as they were';
+
+COMMENT ON COLUMN "audit_events"."after" IS 'fields that changed, as they are now';
+
+-- Audit events are never changed or removed, not even by the application
+CREATE FUNCTION reject_audit_event_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_events_append_only"
+BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW EXECUTE FUNCTION reject_audit_event_change();
+
+CREATE TRIGGER "audit_events_no_truncate"
+BEFORE TRUNCATE ON "audit_events"
+FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_event_change();
@@ -0,0 +1,17 @@
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_address_complete";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "nationality";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "date_of_birth";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "address_country";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "address_postal_code";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "address_city";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "address_line2";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "address_line1";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "phone";
@@ -0,0 +1,28 @@
+ALTER TABLE "users" ADD COLUMN "phone" varchar;
+
+ALTER TABLE "users" ADD COLUMN "address_line1" varchar;
+
+ALTER TABLE "users" ADD COLUMN "address_line2" varchar;
+
+ALTER TABLE "users" ADD COLUMN "address_city" varchar;
+
+ALTER TABLE "users" ADD COLUMN "address_postal_code" varchar;
+
+ALTER TABLE "users" ADD COLUMN "address_country" varchar;
+
+ALTER TABLE "users" ADD COLUMN "date_of_birth" date;
+
+ALTER TABLE "users" ADD COLUMN "nationality" varchar;
+
+ALTER TABLE "users" ADD CONSTRAINT "users_address_complete" CHECK (
+  ("address_line1" IS NULL) = ("address_city" IS NULL) AND
+  ("address_line1" IS NULL) = ("address_country" IS NULL)
+);
+
+COMMENT ON COLUMN "users"."phone" IS 'E.164, like +14155552671';
+
+COMMENT ON COLUMN "users"."address_line1" IS 'null, like the other address columns, when the user has no address';
+
+COMMENT ON COLUMN "users"."address_country" IS 'ISO 3166-1 alpha-2 code';
+
+COMMENT ON COLUMN "users"."nationality" IS 'ISO 3166-1 alpha-2 code';
@@ -0,0 +1,9 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
+
+ALTER TABLE "payroll_jobs" DROP COLUMN IF EXISTS "hold_id";
+
+COMMENT ON COLUMN "payroll_job_rows"."status" IS 'pending, valid, invalid or paid';
+
+DELETE FROM "account_holds" WHERE "to_account_id" IS NULL;
+
+ALTER TABLE "account_holds" ALTER COLUMN "to_account_id" SET NOT NULL;
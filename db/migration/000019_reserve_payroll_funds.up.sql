@@ -0,0 +1,15 @@
+ALTER TABLE "account_holds" ALTER COLUMN "to_account_id" DROP NOT NULL;
+
+ALTER TABLE "payroll_jobs" ADD COLUMN "hold_id" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar;
+
+COMMENT ON COLUMN "account_holds"."to_account_id" IS 'null for the hold that reserves the total of a payroll job';
+
+COMMENT ON COLUMN "payroll_jobs"."hold_id" IS 'reserves the amount of the rows not paid yet once the job is valid';
+
+COMMENT ON COLUMN "payroll_job_rows"."status" IS 'pending, valid, invalid, paid or failed';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text from the payer, like the memo of a payroll row';
+
+ALTER TABLE "payroll_jobs" ADD FOREIGN KEY ("hold_id") REFERENCES "account_holds" ("id");
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "users"."role" IS NULL;
+
+UPDATE "users" SET "role" = 'depositor' WHERE "username" = 'simplebank';
@@ -0,0 +1,3 @@
+UPDATE "users" SET "role" = 'system' WHERE "username" = 'simplebank';
+
+COMMENT ON COLUMN "users"."role" IS 'depositor, banker or system; system users cannot log in';
@@ -2,9 +2,10 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
 RETURNING *;
 
@@ -40,6 +41,33 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
+-- name: GetAccountForOwnerAndCurrency :one
+SELECT * FROM accounts
+WHERE owner = $1 AND currency = $2 AND type = $3
+LIMIT 1;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+  status = $2,
+  credits_frozen = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account_id,
+  from_status,
+  to_status,
+  reason,
+  changed_by,
+  sweep_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListAccountStatusChanges :many
+SELECT * FROM account_status_changes
+WHERE account_id = $1
+ORDER BY id;
@@ -0,0 +1,57 @@
+-- name: CreateAccountHold :one
+INSERT INTO account_holds (
+  from_account_id,
+  to_account_id,
+  amount,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetAccountHold :one
+SELECT * FROM account_holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAccountHoldForUpdate :one
+SELECT * FROM account_holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS held_amount
+FROM account_holds
+WHERE
+  from_account_id = $1 AND
+  status = 'active' AND
+  expires_at > now();
+
+-- name: ReleaseAccountHold :one
+UPDATE account_holds
+SET
+  status = sqlc.arg(status),
+  transfer_id = sqlc.narg(transfer_id),
+  released_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ReduceAccountHold :one
+UPDATE account_holds
+SET amount = amount - sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetAccountHoldTransfer :one
+UPDATE account_holds
+SET transfer_id = sqlc.arg(transfer_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ExpireAccountHolds :execrows
+UPDATE account_holds
+SET
+  status = 'expired',
+  released_at = now()
+WHERE
+  status = 'active' AND
+  expires_at <= now();
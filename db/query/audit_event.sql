@@ -0,0 +1,29 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  actor,
+  action,
+  target_type,
+  target_id,
+  outcome,
+  reason,
+  client_ip,
+  user_agent,
+  before,
+  after
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE
+  (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor)) AND
+  (sqlc.narg(action)::varchar IS NULL OR action = sqlc.narg(action)) AND
+  (sqlc.narg(target_type)::varchar IS NULL OR target_type = sqlc.narg(target_type)) AND
+  (sqlc.narg(target_id)::varchar IS NULL OR target_id = sqlc.narg(target_id)) AND
+  (sqlc.narg(outcome)::varchar IS NULL OR outcome = sqlc.narg(outcome)) AND
+  (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time)) AND
+  (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time)) AND
+  (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
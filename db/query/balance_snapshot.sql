@@ -0,0 +1,26 @@
+-- name: CreateDailyBalanceSnapshots :execrows
+INSERT INTO daily_balance_snapshots (
+  account_id,
+  snapshot_date,
+  balance
+)
+SELECT
+  accounts.id,
+  sqlc.arg(snapshot_date)::date,
+  accounts.balance - COALESCE((
+    SELECT SUM(entries.amount) FROM entries
+    WHERE
+      entries.account_id = accounts.id AND
+      entries.created_at >= sqlc.arg(day_end)
+  ), 0)
+FROM accounts
+WHERE accounts.created_at < sqlc.arg(day_end)
+ON CONFLICT (account_id, snapshot_date) DO NOTHING;
+
+-- name: GetLatestBalanceSnapshot :one
+SELECT * FROM daily_balance_snapshots
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  snapshot_date < sqlc.arg(before)::date
+ORDER BY snapshot_date DESC
+LIMIT 1;
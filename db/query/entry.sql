@@ -17,3 +17,36 @@ WHERE account_id = $1
 ORDER BY id
 LIMIT $2
 OFFSET $3;
+
+-- name: GetEntriesTotalSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: ListEntriesBetween :many
+SELECT * FROM entries
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  created_at >= sqlc.arg(from_time) AND
+  created_at < sqlc.arg(to_time)
+ORDER BY created_at, id;
+
+-- name: GetEntriesTotalBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  created_at >= sqlc.arg(from_time) AND
+  created_at < sqlc.arg(to_time);
+
+-- name: ListDailyEntryTotals :many
+SELECT
+  (created_at AT TIME ZONE 'UTC')::date AS day,
+  SUM(amount)::bigint AS total
+FROM entries
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  created_at >= sqlc.arg(from_time) AND
+  created_at < sqlc.arg(to_time)
+GROUP BY day
+ORDER BY day;
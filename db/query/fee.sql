@@ -0,0 +1,54 @@
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE currency = $1 AND operation = $2
+LIMIT 1;
+
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+  currency,
+  operation,
+  flat_fee,
+  percentage_bps,
+  free_per_month,
+  revenue_account_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (currency, operation)
+DO UPDATE SET
+  flat_fee = EXCLUDED.flat_fee,
+  percentage_bps = EXCLUDED.percentage_bps,
+  free_per_month = EXCLUDED.free_per_month,
+  revenue_account_id = EXCLUDED.revenue_account_id
+RETURNING *;
+
+-- name: DeleteFeeSchedule :exec
+DELETE FROM fee_schedules
+WHERE currency = $1 AND operation = $2;
+
+-- name: CreateTransferFee :one
+INSERT INTO transfer_fees (
+  transfer_id,
+  fee_schedule_id,
+  kind,
+  amount,
+  entry_id,
+  revenue_entry_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListTransferFees :many
+SELECT * FROM transfer_fees
+WHERE transfer_id = $1
+ORDER BY id;
+
+-- name: CountOutgoingTransfers :one
+SELECT COUNT(*)
+FROM transfers
+JOIN accounts ON accounts.id = transfers.from_account_id
+WHERE
+  accounts.owner = sqlc.arg(owner) AND
+  accounts.currency = sqlc.arg(currency) AND
+  transfers.created_at >= sqlc.arg(since);
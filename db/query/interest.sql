@@ -0,0 +1,117 @@
+-- name: GetInterestRate :one
+SELECT * FROM interest_rates
+WHERE currency = $1 AND account_type = $2
+LIMIT 1;
+
+-- name: UpsertInterestRate :one
+INSERT INTO interest_rates (
+  currency,
+  account_type,
+  annual_rate,
+  day_count,
+  expense_account_id
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (currency, account_type)
+DO UPDATE SET
+  annual_rate = EXCLUDED.annual_rate,
+  day_count = EXCLUDED.day_count,
+  expense_account_id = EXCLUDED.expense_account_id
+RETURNING *;
+
+-- name: DeleteInterestRate :exec
+DELETE FROM interest_rates
+WHERE currency = $1 AND account_type = $2;
+
+-- name: GetNextAccountToAccrue :one
+SELECT accounts.* FROM accounts
+JOIN interest_rates ON
+  interest_rates.currency = accounts.currency AND
+  interest_rates.account_type = accounts.type
+WHERE
+  accounts.id > sqlc.arg(after_id) AND
+  accounts.created_at < sqlc.arg(day_end) AND
+  accounts.status <> 'closed' AND
+  NOT EXISTS (
+    SELECT 1 FROM interest_accruals
+    WHERE
+      interest_accruals.account_id = accounts.id AND
+      interest_accruals.accrual_date = sqlc.arg(accrual_date)::date
+  )
+ORDER BY accounts.id
+LIMIT 1
+FOR NO KEY UPDATE OF accounts SKIP LOCKED;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate,
+  day_count,
+  interest_numerator,
+  interest_denominator
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date;
+
+-- name: GetNextAccountToPostInterest :one
+SELECT accounts.* FROM accounts
+JOIN interest_rates ON
+  interest_rates.currency = accounts.currency AND
+  interest_rates.account_type = accounts.type
+WHERE
+  accounts.id > sqlc.arg(after_id) AND
+  accounts.status <> 'closed' AND
+  EXISTS (
+    SELECT 1 FROM interest_accruals
+    WHERE
+      interest_accruals.account_id = accounts.id AND
+      interest_accruals.posting_id IS NULL AND
+      interest_accruals.accrual_date < sqlc.arg(before)::date
+  )
+ORDER BY accounts.id
+LIMIT 1
+FOR NO KEY UPDATE OF accounts SKIP LOCKED;
+
+-- name: ListUnpostedInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  posting_id IS NULL AND
+  accrual_date < sqlc.arg(before)::date
+ORDER BY accrual_date;
+
+-- name: GetLastInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+  account_id,
+  amount,
+  remainder_numerator,
+  remainder_denominator,
+  entry_id,
+  expense_entry_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: SetInterestAccrualsPosting :exec
+UPDATE interest_accruals
+SET posting_id = sqlc.arg(posting_id)
+WHERE
+  account_id = sqlc.arg(account_id) AND
+  posting_id IS NULL AND
+  accrual_date < sqlc.arg(before)::date;
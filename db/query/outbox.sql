@@ -0,0 +1,23 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+  event_type,
+  payload
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1 LIMIT 1;
+
+-- name: GetNextOutboxEventToPublish :one
+SELECT * FROM outbox_events
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: SetOutboxEventPublished :exec
+UPDATE outbox_events
+SET published_at = now()
+WHERE id = $1;
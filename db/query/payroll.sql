@@ -0,0 +1,115 @@
+-- name: CreatePayrollJob :one
+INSERT INTO payroll_jobs (
+  owner,
+  from_account_id,
+  currency,
+  total_rows
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetPayrollJob :one
+SELECT * FROM payroll_jobs
+WHERE id = $1 LIMIT 1;
+
+-- name: GetNextPayrollJobForUpdate :one
+SELECT * FROM payroll_jobs
+WHERE status IN ('pending', 'validating', 'processing')
+ORDER BY id
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: GetPayrollJobForUpdate :one
+SELECT * FROM payroll_jobs
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdatePayrollJobStatus :one
+UPDATE payroll_jobs
+SET
+  status = sqlc.arg(status),
+  error = sqlc.narg(error),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: StartPayrollJobPayment :one
+UPDATE payroll_jobs
+SET
+  status = 'processing',
+  hold_id = sqlc.narg(hold_id),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddPayrollJobProcessedRows :one
+UPDATE payroll_jobs
+SET
+  processed_rows = processed_rows + sqlc.arg(rows),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePayrollJobRow :one
+INSERT INTO payroll_job_rows (
+  job_id,
+  row_number,
+  to_account_id,
+  username,
+  amount,
+  currency,
+  memo
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListPayrollJobRowsByStatus :many
+SELECT * FROM payroll_job_rows
+WHERE job_id = $1 AND status = $2
+ORDER BY row_number
+LIMIT $3;
+
+-- name: ListPayrollJobRowErrors :many
+SELECT * FROM payroll_job_rows
+WHERE job_id = $1 AND error IS NOT NULL
+ORDER BY row_number
+LIMIT $2;
+
+-- name: CountPayrollJobRowsByStatus :one
+SELECT COUNT(*) FROM payroll_job_rows
+WHERE job_id = $1 AND status = $2;
+
+-- name: GetPayrollJobTotalAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total_amount
+FROM payroll_job_rows
+WHERE job_id = $1;
+
+-- name: ValidatePayrollJobRow :one
+UPDATE payroll_job_rows
+SET
+  status = sqlc.arg(status),
+  to_account_id = COALESCE(sqlc.narg(to_account_id), to_account_id),
+  error = sqlc.narg(error)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: PayPayrollJobRow :one
+UPDATE payroll_job_rows
+SET
+  status = 'paid',
+  transfer_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: FailPayrollJobRow :one
+UPDATE payroll_job_rows
+SET
+  status = 'failed',
+  error = sqlc.arg(error)::varchar
+WHERE
+  job_id = sqlc.arg(job_id) AND
+  row_number = sqlc.arg(row_number) AND
+  status = 'valid'
+RETURNING *;
@@ -0,0 +1,21 @@
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limits (
+  key,
+  full_at
+) VALUES (
+  sqlc.arg(key)::varchar, now() + make_interval(secs => sqlc.arg(emission_interval)::float8)
+)
+ON CONFLICT (key) DO UPDATE
+SET full_at = GREATEST(rate_limits.full_at, now()) + make_interval(secs => sqlc.arg(emission_interval)::float8)
+WHERE GREATEST(rate_limits.full_at, now()) + make_interval(secs => sqlc.arg(emission_interval)::float8)
+  <= now() + make_interval(secs => sqlc.arg(tolerance)::float8)
+RETURNING date_part('epoch', full_at - now())::float8 AS seconds_until_full;
+
+-- name: GetRateLimitSecondsUntilFull :one
+SELECT date_part('epoch', full_at - now())::float8 AS seconds_until_full
+FROM rate_limits
+WHERE key = $1;
+
+-- name: DeleteFullRateLimits :execrows
+DELETE FROM rate_limits
+WHERE full_at < now();
@@ -0,0 +1,85 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  schedule,
+  next_run_at,
+  end_at,
+  failure_policy
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetDueScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE
+  status = 'active' AND
+  next_run_at <= now()
+ORDER BY next_run_at
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: ListScheduledTransfers :many
+SELECT scheduled_transfers.* FROM scheduled_transfers
+JOIN accounts ON accounts.id = scheduled_transfers.from_account_id
+WHERE accounts.owner = $1
+ORDER BY scheduled_transfers.id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateScheduledTransferStatus :one
+UPDATE scheduled_transfers
+SET status = sqlc.arg(status)
+WHERE
+  id = sqlc.arg(id) AND
+  status = ANY(sqlc.arg(from_statuses)::varchar[])
+RETURNING *;
+
+-- name: AdvanceScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+  next_run_at = sqlc.arg(next_run_at),
+  status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+  scheduled_transfer_id,
+  scheduled_for,
+  idempotency_key,
+  status,
+  transfer_id,
+  error
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ResumeScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+  status = 'active',
+  next_run_at = GREATEST(next_run_at, sqlc.arg(next_run_at)::timestamptz)
+WHERE
+  id = sqlc.arg(id) AND
+  status = 'paused'
+RETURNING *;
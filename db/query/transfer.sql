@@ -2,9 +2,11 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  idempotency_key,
+  memo
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -20,3 +22,28 @@ WHERE
 ORDER BY id
 LIMIT $3
 OFFSET $4;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversal_of
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversal_of = sqlc.arg(transfer_id)::bigint;
+
+-- name: GetTransferByIdempotencyKey :one
+SELECT * FROM transfers
+WHERE idempotency_key = $1 LIMIT 1;
@@ -0,0 +1,33 @@
+-- name: GetTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE
+  currency = sqlc.arg(currency) AND
+  (username = sqlc.arg(username)::varchar OR username IS NULL)
+ORDER BY username NULLS LAST
+LIMIT 1;
+
+-- name: UpsertTransferLimitOverride :one
+INSERT INTO transfer_limits (
+  username,
+  currency,
+  per_transfer,
+  daily,
+  monthly
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (username, currency) WHERE username IS NOT NULL
+DO UPDATE SET
+  per_transfer = EXCLUDED.per_transfer,
+  daily = EXCLUDED.daily,
+  monthly = EXCLUDED.monthly
+RETURNING *;
+
+-- name: GetOutgoingTransferTotal :one
+SELECT COALESCE(SUM(transfers.amount), 0)::bigint AS total
+FROM transfers
+JOIN accounts ON accounts.id = transfers.from_account_id
+WHERE
+  accounts.owner = sqlc.arg(owner) AND
+  accounts.currency = sqlc.arg(currency) AND
+  transfers.created_at >= sqlc.arg(since);
@@ -16,10 +16,30 @@ WHERE username = $1 LIMIT 1;
 -- name: UpdateUser :one
 UPDATE users
 SET
-  hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
-  password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
-  full_name = COALESCE(sqlc.narg(full_name), full_name),
-  email = COALESCE(sqlc.narg(email), email)
+  hashed_password = CASE WHEN sqlc.arg(set_hashed_password)::boolean THEN sqlc.arg(hashed_password)::varchar ELSE hashed_password END,
+  password_changed_at = CASE WHEN sqlc.arg(set_hashed_password)::boolean THEN sqlc.arg(password_changed_at)::timestamptz ELSE password_changed_at END,
+  full_name = CASE WHEN sqlc.arg(set_full_name)::boolean THEN sqlc.arg(full_name)::varchar ELSE full_name END,
+  email = CASE WHEN sqlc.arg(set_email)::boolean THEN sqlc.arg(email)::varchar ELSE email END,
+  phone = CASE WHEN sqlc.arg(set_phone)::boolean THEN sqlc.narg(phone)::varchar ELSE phone END,
+  address_line1 = CASE WHEN sqlc.arg(set_address)::boolean THEN sqlc.narg(address_line1)::varchar ELSE address_line1 END,
+  address_line2 = CASE WHEN sqlc.arg(set_address)::boolean THEN sqlc.narg(address_line2)::varchar ELSE address_line2 END,
+  address_city = CASE WHEN sqlc.arg(set_address)::boolean THEN sqlc.narg(address_city)::varchar ELSE address_city END,
+  address_postal_code = CASE WHEN sqlc.arg(set_address)::boolean THEN sqlc.narg(address_postal_code)::varchar ELSE address_postal_code END,
+  address_country = CASE WHEN sqlc.arg(set_address)::boolean THEN sqlc.narg(address_country)::varchar ELSE address_country END,
+  date_of_birth = CASE WHEN sqlc.arg(set_date_of_birth)::boolean THEN sqlc.narg(date_of_birth)::date ELSE date_of_birth END,
+  nationality = CASE WHEN sqlc.arg(set_nationality)::boolean THEN sqlc.narg(nationality)::varchar ELSE nationality END
 WHERE
   username = sqlc.arg(username)
 RETURNING *;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: RehashUserPassword :execrows
+UPDATE users
+SET hashed_password = sqlc.arg(new_hashed_password)
+WHERE
+  username = sqlc.arg(username) AND
+  hashed_password = sqlc.arg(old_hashed_password);
@@ -0,0 +1,73 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id
+)
+SELECT webhook_endpoints.id, sqlc.arg(event_id)::bigint
+FROM webhook_endpoints
+WHERE
+  cardinality(webhook_endpoints.event_types) = 0 OR
+  sqlc.arg(event_type)::varchar = ANY(webhook_endpoints.event_types)
+ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: GetNextWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= now()
+ORDER BY next_attempt_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: StartWebhookAttempt :one
+UPDATE webhook_deliveries
+SET
+  attempts = attempts + 1,
+  next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: RecordWebhookAttempt :one
+UPDATE webhook_deliveries
+SET
+  status = sqlc.arg(status),
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  last_status_code = sqlc.narg(last_status_code),
+  last_error = sqlc.narg(last_error),
+  delivered_at = sqlc.narg(delivered_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ReplayWebhookEvents :execrows
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id
+)
+SELECT webhook_endpoints.id, outbox_events.id
+FROM outbox_events
+JOIN webhook_endpoints ON
+  cardinality(webhook_endpoints.event_types) = 0 OR
+  outbox_events.event_type = ANY(webhook_endpoints.event_types)
+WHERE
+  webhook_endpoints.id = sqlc.arg(endpoint_id) AND
+  outbox_events.created_at >= sqlc.arg(from_time) AND
+  outbox_events.created_at < sqlc.arg(to_time)
+ON CONFLICT (endpoint_id, event_id) DO UPDATE SET
+  status = 'pending',
+  attempts = 0,
+  next_attempt_at = now(),
+  last_status_code = NULL,
+  last_error = NULL,
+  delivered_at = NULL;
@@ -7,13 +7,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, type, status, credits_frozen
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +31,9 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
@@ -38,21 +42,28 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, type, status, credits_frozen
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Type     string `json:"type"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -60,22 +71,61 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
+const createAccountStatusChange = `-- name: CreateAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account_id,
+  from_status,
+  to_status,
+  reason,
+  changed_by,
+  sweep_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, account_id, from_status, to_status, reason, changed_by, sweep_transfer_id, created_at
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
+type CreateAccountStatusChangeParams struct {
+	AccountID       int64         `json:"account_id"`
+	FromStatus      string        `json:"from_status"`
+	ToStatus        string        `json:"to_status"`
+	Reason          string        `json:"reason"`
+	ChangedBy       string        `json:"changed_by"`
+	SweepTransferID sql.NullInt64 `json:"sweep_transfer_id"`
+}
+
+func (q *Queries) CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error) {
+	row := q.db.QueryRowContext(ctx, createAccountStatusChange,
+		arg.AccountID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.ChangedBy,
+		arg.SweepTransferID,
+	)
+	var i AccountStatusChange
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Reason,
+		&i.ChangedBy,
+		&i.SweepTransferID,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, type, status, credits_frozen FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -88,12 +138,15 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
 
 const getAccountForOwner = `-- name: GetAccountForOwner :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, type, status, credits_frozen FROM accounts
 WHERE owner = $1 LIMIT 1
 `
 
@@ -106,12 +159,43 @@ func (q *Queries) GetAccountForOwner(ctx context.Context, owner string) (Account
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
+	)
+	return i, err
+}
+
+const getAccountForOwnerAndCurrency = `-- name: GetAccountForOwnerAndCurrency :one
+SELECT id, owner, balance, currency, created_at, type, status, credits_frozen FROM accounts
+WHERE owner = $1 AND currency = $2 AND type = $3
+LIMIT 1
+`
+
+type GetAccountForOwnerAndCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	Type     string `json:"type"`
+}
+
+func (q *Queries) GetAccountForOwnerAndCurrency(ctx context.Context, arg GetAccountForOwnerAndCurrencyParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForOwnerAndCurrency, arg.Owner, arg.Currency, arg.Type)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, type, status, credits_frozen FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -125,12 +209,53 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
 
+const listAccountStatusChanges = `-- name: ListAccountStatusChanges :many
+SELECT id, account_id, from_status, to_status, reason, changed_by, sweep_transfer_id, created_at FROM account_status_changes
+WHERE account_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListAccountStatusChanges(ctx context.Context, accountID int64) ([]AccountStatusChange, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountStatusChanges, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountStatusChange{}
+	for rows.Next() {
+		var i AccountStatusChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Reason,
+			&i.ChangedBy,
+			&i.SweepTransferID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, type, status, credits_frozen FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -158,6 +283,9 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Type,
+			&i.Status,
+			&i.CreditsFrozen,
 		); err != nil {
 			return nil, err
 		}
@@ -176,7 +304,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, type, status, credits_frozen
 `
 
 type UpdateAccountParams struct {
@@ -193,6 +321,40 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+  status = $2,
+  credits_frozen = $3
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, type, status, credits_frozen
+`
+
+type UpdateAccountStatusParams struct {
+	ID            int64  `json:"id"`
+	Status        string `json:"status"`
+	CreditsFrozen bool   `json:"credits_frozen"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ID, arg.Status, arg.CreditsFrozen)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Type,
+		&i.Status,
+		&i.CreditsFrozen,
 	)
 	return i, err
 }
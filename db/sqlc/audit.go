@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+// AuditContext tells who makes the changes done with a context and from where. It is
+// set once per request, audit events written without one are done by the system.
+type AuditContext struct {
+	// Empty when the caller is not signed in
+	Actor     string
+	ClientIP  string
+	UserAgent string
+}
+
+type auditContextKey struct{}
+
+func WithAuditContext(ctx context.Context, audit AuditContext) context.Context {
+	/** Returns a context whose changes are audited as made by audit.Actor. */
+	return context.WithValue(ctx, auditContextKey{}, audit)
+}
+
+func auditContextFrom(ctx context.Context) AuditContext {
+	audit, ok := ctx.Value(auditContextKey{}).(AuditContext)
+	if !ok {
+		return AuditContext{Actor: util.AuditActorSystem}
+	}
+	if audit.Actor == "" {
+		audit.Actor = util.AuditActorAnonymous
+	}
+	return audit
+}
+
+// AuditRecord describes an audited action. Before and After are snapshots of the
+// target, only the fields that differ between them are kept.
+type AuditRecord struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	// Overrides the actor of the context, like the user a new session signs in
+	Actor  string
+	Reason string
+	Before any
+	After  any
+}
+
+func recordAudit(ctx context.Context, q *Queries, record AuditRecord, outcome string) error {
+	/** Appends to the audit log. Called with the transaction making the change, the
+	audit event is committed or rolled back together with it. */
+	audit := auditContextFrom(ctx)
+	if record.Actor != "" {
+		audit.Actor = record.Actor
+	}
+
+	before, after, err := auditDiff(record.Before, record.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAuditEvent(ctx, CreateAuditEventParams{
+		Actor:      audit.Actor,
+		Action:     record.Action,
+		TargetType: record.TargetType,
+		TargetID:   record.TargetID,
+		Outcome:    outcome,
+		Reason:     record.Reason,
+		ClientIp:   audit.ClientIP,
+		UserAgent:  audit.UserAgent,
+		Before:     before,
+		After:      after,
+	})
+	return err
+}
+
+func (store *SQLStore) RecordAuditFailure(ctx context.Context, record AuditRecord, cause error) error {
+	/** Appends a failed action to the audit log, outside of the transaction that was
+	rolled back. The event is written even when the request was cancelled. */
+	if record.Reason == "" && cause != nil {
+		record.Reason = cause.Error()
+	}
+	return recordAudit(context.WithoutCancel(ctx), store.Queries, record, util.AuditFailure)
+}
+
+func (store *SQLStore) auditFailure(ctx context.Context, record AuditRecord, err error) error {
+	/** Audits a failed transaction and returns its error, along with the error of
+	the audit log if it could not be written. */
+	if auditErr := store.RecordAuditFailure(ctx, record, err); auditErr != nil {
+		return errors.Join(err, auditErr)
+	}
+	return err
+}
+
+func auditDiff(before any, after any) (json.RawMessage, json.RawMessage, error) {
+	/** Keeps the fields whose value changed. A nil snapshot, like the one before a
+	creation, keeps every field of the other one. */
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+			delete(beforeFields, key)
+			delete(afterFields, key)
+		}
+	}
+
+	beforeJSON, err := json.Marshal(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := json.Marshal(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func auditFields(snapshot any) (map[string]any, error) {
+	fields := map[string]any{}
+	if snapshot == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+func auditTransferCreated(ctx context.Context, q *Queries, transfer Transfer) error {
+	return recordAudit(ctx, q, AuditRecord{
+		Action:     util.AuditTransferCreated,
+		TargetType: util.AuditTargetTransfer,
+		TargetID:   strconv.FormatInt(transfer.ID, 10),
+		After:      newTransferEvent(transfer),
+	}, util.AuditSuccess)
+}
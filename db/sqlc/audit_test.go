@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func findAuditEvents(t *testing.T, action string, targetID string) []AuditEvent {
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Action:   sql.NullString{String: action, Valid: true},
+		TargetID: sql.NullString{String: targetID, Valid: true},
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	return events
+}
+
+func TestAuditDiff(t *testing.T) {
+	before, after, err := auditDiff(
+		map[string]any{"full_name": "Jason", "email": "old@example.com"},
+		map[string]any{"full_name": "Jason", "email": "new@example.com"},
+	)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"email": "old@example.com"}`, string(before))
+	require.JSONEq(t, `{"email": "new@example.com"}`, string(after))
+
+	// Nothing before a creation, every field after it
+	before, after, err = auditDiff(nil, map[string]any{"id": 1})
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(before))
+	require.JSONEq(t, `{"id": 1}`, string(after))
+}
+
+func TestUpdateUserTxAudit(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	newEmail := util.RandomEmail()
+
+	ctx := WithAuditContext(context.Background(), AuditContext{
+		Actor:     user.Username,
+		ClientIP:  "203.0.113.7",
+		UserAgent: "simplebank-test",
+	})
+	_, err := store.UpdateUserTx(ctx, UpdateUserParams{
+		Username: user.Username,
+		SetEmail: true,
+		Email:    newEmail,
+	})
+	require.NoError(t, err)
+
+	events := findAuditEvents(t, util.AuditUserUpdated, user.Username)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, user.Username, event.Actor)
+	require.Equal(t, util.AuditTargetUser, event.TargetType)
+	require.Equal(t, util.AuditSuccess, event.Outcome)
+	require.Equal(t, "203.0.113.7", event.ClientIp)
+	require.Equal(t, "simplebank-test", event.UserAgent)
+
+	var before, after map[string]any
+	require.NoError(t, json.Unmarshal(event.Before, &before))
+	require.NoError(t, json.Unmarshal(event.After, &after))
+	require.Equal(t, map[string]any{"email": user.Email}, before)
+	require.Equal(t, map[string]any{"email": newEmail}, after)
+}
+
+func TestUpdateUserTxMasksDetails(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	_, err := store.UpdateUserTx(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		SetPhone: true,
+		Phone:    sql.NullString{String: "+14155552671", Valid: true},
+	})
+	require.NoError(t, err)
+
+	events := findAuditEvents(t, util.AuditUserUpdated, user.Username)
+	require.Len(t, events, 1)
+	require.NotContains(t, string(events[0].Before), "+14155552671")
+	require.NotContains(t, string(events[0].After), "+14155552671")
+
+	var after map[string]any
+	require.NoError(t, json.Unmarshal(events[0].After, &after))
+	require.Equal(t, map[string]any{"changed_details": []any{"phone"}}, after)
+}
+
+func TestUpdateUserTxAuditFailure(t *testing.T) {
+	store := NewStore(testDB)
+	username := util.RandomOwner()
+
+	// Unknown users can't be updated, the attempt is still audited
+	_, err := store.UpdateUserTx(context.Background(), UpdateUserParams{
+		Username:    username,
+		SetFullName: true,
+		FullName:    util.RandomOwner(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	events := findAuditEvents(t, util.AuditUserUpdated, username)
+	require.Len(t, events, 1)
+	require.Equal(t, util.AuditFailure, events[0].Outcome)
+	require.Equal(t, util.AuditActorSystem, events[0].Actor)
+	require.NotEmpty(t, events[0].Reason)
+}
+
+func TestAuditEventsAreAppendOnly(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+		Type:     util.CheckingAccount,
+	})
+	require.NoError(t, err)
+
+	events := findAuditEvents(t, util.AuditAccountCreated, strconv.FormatInt(account.ID, 10))
+	require.Len(t, events, 1)
+	require.Equal(t, util.AuditActorSystem, events[0].Actor)
+
+	_, err = testDB.Exec("UPDATE audit_events SET outcome = 'failure' WHERE id = $1", events[0].ID)
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = testDB.Exec("DELETE FROM audit_events WHERE id = $1", events[0].ID)
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestListAuditEventsPaging(t *testing.T) {
+	account1 := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	store := NewStore(testDB)
+
+	ctx := WithAuditContext(context.Background(), AuditContext{Actor: account1.Owner})
+	for i := 0; i < 3; i++ {
+		_, err := store.TransferMoneyTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListAuditEventsParams{
+		Actor:    sql.NullString{String: account1.Owner, Valid: true},
+		Action:   sql.NullString{String: util.AuditTransferCreated, Valid: true},
+		PageSize: 2,
+	}
+	page1, err := testQueries.ListAuditEvents(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Greater(t, page1[0].ID, page1[1].ID)
+
+	arg.BeforeID = sql.NullInt64{Int64: page1[1].ID, Valid: true}
+	page2, err := testQueries.ListAuditEvents(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Less(t, page2[0].ID, page1[1].ID)
+}
+
+func TestNewUserUpdatedEvent(t *testing.T) {
+	before := User{
+		Username:     "alice",
+		Phone:        sql.NullString{String: "+14155552671", Valid: true},
+		AddressLine1: sql.NullString{String: "1 Main Street", Valid: true},
+		DateOfBirth:  sql.NullTime{Time: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC), Valid: true},
+		Nationality:  sql.NullString{String: "CA", Valid: true},
+	}
+
+	event := newUserUpdatedEvent(before, before)
+	require.Empty(t, event.ChangedDetails)
+
+	// Clearing a detail is a change too
+	after := before
+	after.Phone = sql.NullString{}
+	after.AddressLine1.String = "2 Main Street"
+	after.DateOfBirth.Time = after.DateOfBirth.Time.In(time.FixedZone("", 3600))
+	event = newUserUpdatedEvent(before, after)
+	require.Equal(t, []string{"phone", "address"}, event.ChangedDetails)
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	for _, value := range []string{"+14155552671", "Main Street", "1990", "CA"} {
+		require.NotContains(t, string(data), value)
+	}
+}
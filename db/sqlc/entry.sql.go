@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -36,6 +37,46 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 	return i, err
 }
 
+const getEntriesTotalBetween = `-- name: GetEntriesTotalBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE
+  account_id = $1 AND
+  created_at >= $2 AND
+  created_at < $3
+`
+
+type GetEntriesTotalBetweenParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) GetEntriesTotalBetween(ctx context.Context, arg GetEntriesTotalBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getEntriesTotalBetween, arg.AccountID, arg.FromTime, arg.ToTime)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const getEntriesTotalSince = `-- name: GetEntriesTotalSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type GetEntriesTotalSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetEntriesTotalSince(ctx context.Context, arg GetEntriesTotalSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getEntriesTotalSince, arg.AccountID, arg.CreatedAt)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
 const getEntry = `-- name: GetEntry :one
 SELECT id, account_id, amount, created_at FROM entries
 WHERE id = $1
@@ -53,6 +94,53 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 	return i, err
 }
 
+const listDailyEntryTotals = `-- name: ListDailyEntryTotals :many
+SELECT
+  (created_at AT TIME ZONE 'UTC')::date AS day,
+  SUM(amount)::bigint AS total
+FROM entries
+WHERE
+  account_id = $1 AND
+  created_at >= $2 AND
+  created_at < $3
+GROUP BY day
+ORDER BY day
+`
+
+type ListDailyEntryTotalsParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type ListDailyEntryTotalsRow struct {
+	Day   time.Time `json:"day"`
+	Total int64     `json:"total"`
+}
+
+func (q *Queries) ListDailyEntryTotals(ctx context.Context, arg ListDailyEntryTotalsParams) ([]ListDailyEntryTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDailyEntryTotals, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDailyEntryTotalsRow{}
+	for rows.Next() {
+		var i ListDailyEntryTotalsRow
+		if err := rows.Scan(&i.Day, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
 SELECT id, account_id, amount, created_at FROM entries
 WHERE account_id = $1
@@ -94,3 +182,46 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const listEntriesBetween = `-- name: ListEntriesBetween :many
+SELECT id, account_id, amount, created_at FROM entries
+WHERE
+  account_id = $1 AND
+  created_at >= $2 AND
+  created_at < $3
+ORDER BY created_at, id
+`
+
+type ListEntriesBetweenParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesBetween, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
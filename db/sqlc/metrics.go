@@ -0,0 +1,34 @@
+package db
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	transfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "transfers_total",
+		Help:      "Committed transfers, reversals included.",
+	}, []string{"currency"})
+
+	transferVolumeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "transfer_volume_total",
+		Help:      "Money moved by committed transfers, in minor units.",
+	}, []string{"currency"})
+
+	txAbortsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "db_tx_aborts_total",
+		Help:      "Transactions aborted by a serialization failure or deadlock. They are not retried, the caller has to retry them.",
+	}, []string{"reason"})
+)
+
+func observeTransfer(q *Queries, currency string, amount int64) {
+	/** Counts the transfer once the transaction it is part of has committed. */
+	afterCommit(q, func() {
+		transfersTotal.WithLabelValues(currency).Inc()
+		transferVolumeTotal.WithLabelValues(currency).Add(float64(amount))
+	})
+}
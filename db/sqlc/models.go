@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,70 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// checking or savings, revenue and expense are bank ledger accounts
+	Type string `json:"type"`
+	// active, frozen or closed
+	Status string `json:"status"`
+	// a frozen account also rejects incoming transfers
+	CreditsFrozen bool `json:"credits_frozen"`
+}
+
+type AccountHold struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	// null for the hold that reserves the total of a payroll job
+	ToAccountID sql.NullInt64 `json:"to_account_id"`
+	// must be positive
+	Amount int64 `json:"amount"`
+	// active, captured, voided or expired
+	Status string `json:"status"`
+	// set once the hold is captured
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	ReleasedAt sql.NullTime  `json:"released_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type AccountStatusChange struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"account_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	// user who made the change
+	ChangedBy string `json:"changed_by"`
+	// transfer that emptied the account before it was closed
+	SweepTransferID sql.NullInt64 `json:"sweep_transfer_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// username, anonymous for callers without a token or system for background work
+	Actor string `json:"actor"`
+	// user.login, user.updated, account.created, account.closed, account.status_changed or transfer.created
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Outcome    string `json:"outcome"`
+	// why the action failed, or why an account status changed
+	Reason    string `json:"reason"`
+	ClientIp  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+	// fields that changed, as they were
+	Before json.RawMessage `json:"before"`
+	// fields that changed, as they are now
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type DailyBalanceSnapshot struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	SnapshotDate time.Time `json:"snapshot_date"`
+	// balance at the end of snapshot_date (UTC)
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Entry struct {
@@ -26,6 +92,141 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type FeeSchedule struct {
+	ID        int64  `json:"id"`
+	Currency  string `json:"currency"`
+	Operation string `json:"operation"`
+	FlatFee   int64  `json:"flat_fee"`
+	// hundredths of a percent of the amount, rounded down
+	PercentageBps int32 `json:"percentage_bps"`
+	// operations per calendar month (UTC) that are not charged
+	FreePerMonth     int32     `json:"free_per_month"`
+	RevenueAccountID int64     `json:"revenue_account_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// balance at the end of accrual_date (UTC)
+	Balance    int64  `json:"balance"`
+	AnnualRate string `json:"annual_rate"`
+	DayCount   string `json:"day_count"`
+	// exact interest in minor units is interest_numerator / interest_denominator
+	InterestNumerator   string `json:"interest_numerator"`
+	InterestDenominator string `json:"interest_denominator"`
+	// null until the interest is paid out
+	PostingID sql.NullInt64 `json:"posting_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// whole minor units paid to the account
+	Amount int64 `json:"amount"`
+	// fraction of a minor unit carried to the next posting
+	RemainderNumerator   string        `json:"remainder_numerator"`
+	RemainderDenominator string        `json:"remainder_denominator"`
+	EntryID              sql.NullInt64 `json:"entry_id"`
+	ExpenseEntryID       sql.NullInt64 `json:"expense_entry_id"`
+	CreatedAt            time.Time     `json:"created_at"`
+}
+
+type InterestRate struct {
+	ID          int64  `json:"id"`
+	Currency    string `json:"currency"`
+	AccountType string `json:"account_type"`
+	// decimal fraction, 0.0425 is 4.25%
+	AnnualRate string `json:"annual_rate"`
+	// ACT/365, ACT/360 or ACT/ACT
+	DayCount         string    `json:"day_count"`
+	ExpenseAccountID int64     `json:"expense_account_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	ID int64 `json:"id"`
+	// transfer.created, account.created or user.updated
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	// null until a delivery was queued for every matching endpoint
+	PublishedAt sql.NullTime `json:"published_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type PayrollJob struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	Currency      string `json:"currency"`
+	// pending, validating, invalid, processing, completed or failed
+	Status        string `json:"status"`
+	TotalRows     int32  `json:"total_rows"`
+	ProcessedRows int32  `json:"processed_rows"`
+	// why the job is invalid or failed
+	Error     sql.NullString `json:"error"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	// reserves the amount of the rows not paid yet once the job is valid
+	HoldID sql.NullInt64 `json:"hold_id"`
+}
+
+type PayrollJobRow struct {
+	ID    int64 `json:"id"`
+	JobID int64 `json:"job_id"`
+	// line in the uploaded file, the header is line 1
+	RowNumber int32 `json:"row_number"`
+	// resolved from username when not given
+	ToAccountID sql.NullInt64  `json:"to_account_id"`
+	Username    sql.NullString `json:"username"`
+	Amount      int64          `json:"amount"`
+	Currency    string         `json:"currency"`
+	Memo        string         `json:"memo"`
+	// pending, valid, invalid, paid or failed
+	Status     string         `json:"status"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Error      sql.NullString `json:"error"`
+}
+
+type RateLimit struct {
+	// method and user or client IP the limit applies to
+	Key string `json:"key"`
+	// when all tokens will have been refilled
+	FullAt time.Time `json:"full_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	// must be positive
+	Amount int64 `json:"amount"`
+	// cron expression or descriptor such as @monthly
+	Schedule  string    `json:"schedule"`
+	NextRunAt time.Time `json:"next_run_at"`
+	// no occurrences are scheduled after this time
+	EndAt sql.NullTime `json:"end_at"`
+	// skip or pause
+	FailurePolicy string `json:"failure_policy"`
+	// active, paused, cancelled or completed
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	IdempotencyKey      string    `json:"idempotency_key"`
+	// succeeded or failed
+	Status     string         `json:"status"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Error      sql.NullString `json:"error"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -44,6 +245,39 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// original transfer this one compensates
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
+	// retrying a transfer with the same key returns the original one
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	// free text from the payer, like the memo of a payroll row
+	Memo sql.NullString `json:"memo"`
+}
+
+type TransferFee struct {
+	ID            int64 `json:"id"`
+	TransferID    int64 `json:"transfer_id"`
+	FeeScheduleID int64 `json:"fee_schedule_id"`
+	// flat or percentage
+	Kind   string `json:"kind"`
+	Amount int64  `json:"amount"`
+	// debit on the paying account
+	EntryID int64 `json:"entry_id"`
+	// credit on the revenue account
+	RevenueEntryID int64     `json:"revenue_entry_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// override for one user, null for the currency default
+	Username    sql.NullString `json:"username"`
+	Currency    string         `json:"currency"`
+	PerTransfer int64          `json:"per_transfer"`
+	// rolling 24 hours of outgoing transfers
+	Daily int64 `json:"daily"`
+	// outgoing transfers since the start of the calendar month (UTC)
+	Monthly   int64     `json:"monthly"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -53,4 +287,43 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// depositor, banker or system; system users cannot log in
+	Role string `json:"role"`
+	// E.164, like +14155552671
+	Phone sql.NullString `json:"phone"`
+	// null, like the other address columns, when the user has no address
+	AddressLine1      sql.NullString `json:"address_line1"`
+	AddressLine2      sql.NullString `json:"address_line2"`
+	AddressCity       sql.NullString `json:"address_city"`
+	AddressPostalCode sql.NullString `json:"address_postal_code"`
+	// ISO 3166-1 alpha-2 code
+	AddressCountry sql.NullString `json:"address_country"`
+	DateOfBirth    sql.NullTime   `json:"date_of_birth"`
+	// ISO 3166-1 alpha-2 code
+	Nationality sql.NullString `json:"nationality"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EndpointID int64 `json:"endpoint_id"`
+	EventID    int64 `json:"event_id"`
+	// pending, delivered or dead
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type WebhookEndpoint struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// key of the HMAC-SHA256 signature sent with every delivery
+	Secret string `json:"secret"`
+	// empty means every event
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
 }
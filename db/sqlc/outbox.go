@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+// Event payloads leave out nullable columns' sql wrappers and anything secret
+// such as password hashes, they are what webhook receivers get to see. The audit
+// log uses them as snapshots for the same reason. A user's KYC details are never
+// included, only the names of those that changed.
+type transferEvent struct {
+	ID            int64     `json:"id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ReversalOf    *int64    `json:"reversal_of,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type userEvent struct {
+	Username          string    `json:"username"`
+	FullName          string    `json:"full_name"`
+	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	CreatedAt         time.Time `json:"created_at"`
+	// Named like the paths of UpdateUser's update_mask, such as date_of_birth
+	ChangedDetails []string `json:"changed_details,omitempty"`
+}
+
+func recordEvent(ctx context.Context, q *Queries, eventType string, payload any) error {
+	/** Writes an event to the outbox using the caller's transaction, so it is only
+	delivered if the change it describes is committed. */
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType: eventType,
+		Payload:   data,
+	})
+	return err
+}
+
+func newTransferEvent(transfer Transfer) transferEvent {
+	event := transferEvent{
+		ID:            transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		CreatedAt:     transfer.CreatedAt,
+	}
+	if transfer.ReversalOf.Valid {
+		event.ReversalOf = &transfer.ReversalOf.Int64
+	}
+	return event
+}
+
+func newUserEvent(user User) userEvent {
+	return userEvent{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		Role:              user.Role,
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreatedAt:         user.CreatedAt,
+	}
+}
+
+func newUserUpdatedEvent(before User, after User) userEvent {
+	event := newUserEvent(after)
+	if before.Phone != after.Phone {
+		event.ChangedDetails = append(event.ChangedDetails, "phone")
+	}
+	if before.AddressLine1 != after.AddressLine1 ||
+		before.AddressLine2 != after.AddressLine2 ||
+		before.AddressCity != after.AddressCity ||
+		before.AddressPostalCode != after.AddressPostalCode ||
+		before.AddressCountry != after.AddressCountry {
+		event.ChangedDetails = append(event.ChangedDetails, "address")
+	}
+	if before.DateOfBirth.Valid != after.DateOfBirth.Valid || !before.DateOfBirth.Time.Equal(after.DateOfBirth.Time) {
+		event.ChangedDetails = append(event.ChangedDetails, "date_of_birth")
+	}
+	if before.Nationality != after.Nationality {
+		event.ChangedDetails = append(event.ChangedDetails, "nationality")
+	}
+	return event
+}
+
+func recordTransferCreated(ctx context.Context, q *Queries, transfer Transfer) error {
+	return recordEvent(ctx, q, util.EventTransferCreated, newTransferEvent(transfer))
+}
+
+func recordUserUpdated(ctx context.Context, q *Queries, before User, after User) error {
+	return recordEvent(ctx, q, util.EventUserUpdated, newUserUpdatedEvent(before, after))
+}
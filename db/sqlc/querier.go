@@ -6,30 +6,116 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddPayrollJobProcessedRows(ctx context.Context, arg AddPayrollJobProcessedRowsParams) (PayrollJob, error)
+	AdvanceScheduledTransfer(ctx context.Context, arg AdvanceScheduledTransferParams) (ScheduledTransfer, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	CountOutgoingTransfers(ctx context.Context, arg CountOutgoingTransfersParams) (int64, error)
+	CountPayrollJobRowsByStatus(ctx context.Context, arg CountPayrollJobRowsByStatusParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountHold(ctx context.Context, arg CreateAccountHoldParams) (AccountHold, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateDailyBalanceSnapshots(ctx context.Context, arg CreateDailyBalanceSnapshotsParams) (int64, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreatePayrollJob(ctx context.Context, arg CreatePayrollJobParams) (PayrollJob, error)
+	CreatePayrollJobRow(ctx context.Context, arg CreatePayrollJobRowParams) (PayrollJobRow, error)
+	CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferFee(ctx context.Context, arg CreateTransferFeeParams) (TransferFee, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) (int64, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeleteFeeSchedule(ctx context.Context, arg DeleteFeeScheduleParams) error
+	DeleteFullRateLimits(ctx context.Context) (int64, error)
+	DeleteInterestRate(ctx context.Context, arg DeleteInterestRateParams) error
+	ExpireAccountHolds(ctx context.Context) (int64, error)
+	FailPayrollJobRow(ctx context.Context, arg FailPayrollJobRowParams) (PayrollJobRow, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForOwner(ctx context.Context, owner string) (Account, error)
+	GetAccountForOwnerAndCurrency(ctx context.Context, arg GetAccountForOwnerAndCurrencyParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountHold(ctx context.Context, id int64) (AccountHold, error)
+	GetAccountHoldForUpdate(ctx context.Context, id int64) (AccountHold, error)
+	GetDueScheduledTransferForUpdate(ctx context.Context) (ScheduledTransfer, error)
+	GetEntriesTotalBetween(ctx context.Context, arg GetEntriesTotalBetweenParams) (int64, error)
+	GetEntriesTotalSince(ctx context.Context, arg GetEntriesTotalSinceParams) (int64, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFeeSchedule(ctx context.Context, arg GetFeeScheduleParams) (FeeSchedule, error)
+	GetHeldAmount(ctx context.Context, fromAccountID int64) (int64, error)
+	GetInterestRate(ctx context.Context, arg GetInterestRateParams) (InterestRate, error)
+	GetLastInterestPosting(ctx context.Context, accountID int64) (InterestPosting, error)
+	GetLatestBalanceSnapshot(ctx context.Context, arg GetLatestBalanceSnapshotParams) (DailyBalanceSnapshot, error)
+	GetNextAccountToAccrue(ctx context.Context, arg GetNextAccountToAccrueParams) (Account, error)
+	GetNextAccountToPostInterest(ctx context.Context, arg GetNextAccountToPostInterestParams) (Account, error)
+	GetNextOutboxEventToPublish(ctx context.Context) (OutboxEvent, error)
+	GetNextPayrollJobForUpdate(ctx context.Context) (PayrollJob, error)
+	GetNextWebhookDelivery(ctx context.Context) (WebhookDelivery, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetOutgoingTransferTotal(ctx context.Context, arg GetOutgoingTransferTotalParams) (int64, error)
+	GetPayrollJob(ctx context.Context, id int64) (PayrollJob, error)
+	GetPayrollJobForUpdate(ctx context.Context, id int64) (PayrollJob, error)
+	GetPayrollJobTotalAmount(ctx context.Context, jobID int64) (int64, error)
+	GetRateLimitSecondsUntilFull(ctx context.Context, key string) (float64, error)
+	GetReversedAmount(ctx context.Context, transferID int64) (int64, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferByIdempotencyKey(ctx context.Context, idempotencyKey sql.NullString) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferLimit(ctx context.Context, arg GetTransferLimitParams) (TransferLimit, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ListAccountStatusChanges(ctx context.Context, accountID int64) ([]AccountStatusChange, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListDailyEntryTotals(ctx context.Context, arg ListDailyEntryTotalsParams) ([]ListDailyEntryTotalsRow, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error)
+	ListInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccrual, error)
+	ListPayrollJobRowErrors(ctx context.Context, arg ListPayrollJobRowErrorsParams) ([]PayrollJobRow, error)
+	ListPayrollJobRowsByStatus(ctx context.Context, arg ListPayrollJobRowsByStatusParams) ([]PayrollJobRow, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListTransferFees(ctx context.Context, transferID int64) ([]TransferFee, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUnpostedInterestAccruals(ctx context.Context, arg ListUnpostedInterestAccrualsParams) ([]InterestAccrual, error)
+	PayPayrollJobRow(ctx context.Context, arg PayPayrollJobRowParams) (PayrollJobRow, error)
+	RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) (WebhookDelivery, error)
+	ReduceAccountHold(ctx context.Context, arg ReduceAccountHoldParams) (AccountHold, error)
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error)
+	ReleaseAccountHold(ctx context.Context, arg ReleaseAccountHoldParams) (AccountHold, error)
+	ReplayWebhookEvents(ctx context.Context, arg ReplayWebhookEventsParams) (int64, error)
+	ResumeScheduledTransfer(ctx context.Context, arg ResumeScheduledTransferParams) (ScheduledTransfer, error)
+	SetAccountHoldTransfer(ctx context.Context, arg SetAccountHoldTransferParams) (AccountHold, error)
+	SetInterestAccrualsPosting(ctx context.Context, arg SetInterestAccrualsPostingParams) error
+	SetOutboxEventPublished(ctx context.Context, id int64) error
+	StartPayrollJobPayment(ctx context.Context, arg StartPayrollJobPaymentParams) (PayrollJob, error)
+	StartWebhookAttempt(ctx context.Context, arg StartWebhookAttemptParams) (WebhookDelivery, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdatePayrollJobStatus(ctx context.Context, arg UpdatePayrollJobStatusParams) (PayrollJob, error)
+	UpdateScheduledTransferStatus(ctx context.Context, arg UpdateScheduledTransferStatusParams) (ScheduledTransfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedule, error)
+	UpsertInterestRate(ctx context.Context, arg UpsertInterestRateParams) (InterestRate, error)
+	UpsertTransferLimitOverride(ctx context.Context, arg UpsertTransferLimitOverrideParams) (TransferLimit, error)
+	ValidatePayrollJobRow(ctx context.Context, arg ValidatePayrollJobRowParams) (PayrollJobRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeRateLimitToken(t *testing.T) {
+	key := "LoginUser:ip:" + util.RandomString(12)
+	// 1 token a minute, 3 at once
+	arg := TakeRateLimitTokenParams{
+		Key:              key,
+		EmissionInterval: 60,
+		Tolerance:        180,
+	}
+
+	for i := 1; i <= 3; i++ {
+		secondsUntilFull, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+		require.NoError(t, err)
+		require.InDelta(t, float64(60*i), secondsUntilFull, 1)
+	}
+
+	// The bucket is empty and stays as it is
+	_, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	secondsUntilFull, err := testQueries.GetRateLimitSecondsUntilFull(context.Background(), key)
+	require.NoError(t, err)
+	require.InDelta(t, 180, secondsUntilFull, 1)
+
+	// Only full buckets are deleted
+	_, err = testQueries.DeleteFullRateLimits(context.Background())
+	require.NoError(t, err)
+	_, err = testQueries.GetRateLimitSecondsUntilFull(context.Background(), key)
+	require.NoError(t, err)
+
+	_, err = testDB.Exec("UPDATE rate_limits SET full_at = now() - interval '1 second' WHERE key = $1", key)
+	require.NoError(t, err)
+	deleted, err := testQueries.DeleteFullRateLimits(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+	_, err = testQueries.GetRateLimitSecondsUntilFull(context.Background(), key)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
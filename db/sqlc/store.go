@@ -3,13 +3,43 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
 )
 
 /** Store provides all functions to execute db queries and transactions*/
 type Store interface {
 	Querier
 	TransferMoneyTx(context.Context, TransferTxParams) (TransferTxResult, error)
+	ReverseTransferTx(context.Context, ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	AuthorizeTransferTx(context.Context, AuthorizeTransferTxParams) (AuthorizeTransferTxResult, error)
+	CaptureTransferTx(context.Context, CaptureTransferTxParams) (CaptureTransferTxResult, error)
+	VoidTransferTx(context.Context, VoidTransferTxParams) (AccountHold, error)
+	BatchTransferTx(context.Context, BatchTransferTxParams) (BatchTransferTxResult, error)
+	CreatePayrollJobTx(context.Context, CreatePayrollJobTxParams) (PayrollJob, error)
+	ProcessPayrollJobTx(context.Context, ProcessPayrollJobTxParams) (PayrollJob, error)
+	FailPayrollJobTx(context.Context, FailPayrollJobTxParams) (PayrollJob, error)
+	ExecuteScheduledTransferTx(context.Context) (ExecuteScheduledTransferTxResult, error)
+	FailScheduledTransferTx(context.Context, FailScheduledTransferTxParams) (ScheduledTransfer, error)
+	GetStatementTx(context.Context, GetStatementTxParams) (Statement, error)
+	UpdateAccountStatusTx(context.Context, UpdateAccountStatusTxParams) (UpdateAccountStatusTxResult, error)
+	AccrueInterestTx(context.Context, AccrueInterestTxParams) (InterestAccrual, error)
+	PostInterestTx(context.Context, PostInterestTxParams) (InterestPosting, error)
+	SnapshotBalancesTx(context.Context, SnapshotBalancesTxParams) (int64, error)
+	GetBalanceAtTx(context.Context, GetBalanceAtTxParams) (GetBalanceAtTxResult, error)
+	GetBalanceHistoryTx(context.Context, GetBalanceHistoryTxParams) (GetBalanceHistoryTxResult, error)
+	CreateAccountTx(context.Context, CreateAccountParams) (Account, error)
+	UpdateUserTx(context.Context, UpdateUserParams) (User, error)
+	CreateSessionTx(context.Context, CreateSessionParams) (Session, error)
+	RecordSuspiciousSessionTx(context.Context, RecordSuspiciousSessionTxParams) (Session, error)
+	RecordAuditFailure(context.Context, AuditRecord, error) error
+	PublishOutboxEventTx(context.Context) (PublishOutboxEventTxResult, error)
+	ClaimWebhookDeliveryTx(context.Context, ClaimWebhookDeliveryTxParams) (ClaimWebhookDeliveryTxResult, error)
 }
 
 /** SQLStore provides all functions to execute SQL queries and transactions*/
@@ -22,18 +52,38 @@ func NewStore(db *sql.DB) Store {
 	/** Creates a new Store. */
 	return &SQLStore{
 		db:      db,
-		Queries: New(db),
+		Queries: New(&tracedDBTX{db: db}),
+	}
+}
+
+func (store *SQLStore) execTx(ctx context.Context, name string, fn func(*Queries) error) error {
+	/** Executes a function within a database transaction. name is the Store method
+	running it, which the transaction's span is named after. */
+	return store.execTxWithOptions(ctx, name, nil, fn)
+}
+
+func (store *SQLStore) execTxWithOptions(ctx context.Context, name string, opts *sql.TxOptions, fn func(*Queries) error) error {
+	/** Runs fn in a transaction. Transactions Postgres aborted because of a serialization
+	failure or a deadlock are counted, they are left to the caller to retry. */
+	ctx, span := startTxSpan(ctx, name)
+	defer span.End()
+
+	err := store.runTx(ctx, span, opts, fn)
+	if reason, ok := retryableTxError(err); ok {
+		txAbortsTotal.WithLabelValues(reason).Inc()
 	}
+	recordError(span, err)
+	return err
 }
 
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	/** Executes a function within a database transaction */
-	tx, err := store.db.BeginTx(ctx, nil)
+func (store *SQLStore) runTx(ctx context.Context, span trace.Span, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
 
-	q := New(tx)
+	state := &txState{DBTX: &tracedDBTX{db: tx, parent: span}}
+	q := New(state)
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -42,13 +92,54 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 		return err
 	}
 
-	return tx.Commit()
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range state.onCommit {
+		hook()
+	}
+	return nil
+}
+
+// txState is the connection of a transaction's queries. It remembers work that
+// should only happen once the transaction has committed.
+type txState struct {
+	DBTX
+	onCommit []func()
+}
+
+func afterCommit(q *Queries, hook func()) {
+	/** Runs hook once q's transaction commits, or right away outside a transaction. */
+	if state, ok := q.db.(*txState); ok {
+		state.onCommit = append(state.onCommit, hook)
+		return
+	}
+	hook()
+}
+
+func retryableTxError(err error) (reason string, ok bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return "", false
+	}
+
+	switch pqErr.Code.Name() {
+	case "serialization_failure", "deadlock_detected":
+		return pqErr.Code.Name(), true
+	}
+	return "", false
 }
 
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// Optional. A transfer that was already made with the same key is returned instead.
+	IdempotencyKey string `json:"idempotency_key"`
+	// Optional note stored on the transfer
+	Memo string `json:"memo"`
 }
 
 type TransferTxResult struct {
@@ -57,77 +148,298 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// Charged to the source account on top of the amount, one per fee kind
+	Fees []TransferFee `json:"fees"`
 }
 
 func (store *SQLStore) TransferMoneyTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	/** Performs a money transfer from one account to another in transactional way in DB
-	Creates transfer record, adds account entries, and updates account balances. */
+	Creates transfer record, adds account entries, and updates account balances.
+	Fees from the currency's schedule are charged to the source account in the same transaction,
+	and a transfer.created event is written to the outbox. */
 	var result TransferTxResult
 
 	transaction := func(q *Queries) error {
 		var err error
-		// Create Transfer record
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		result, err = checkedTransfer(ctx, q, arg, true)
+		return err
+	}
+	err := store.execTx(ctx, "TransferMoneyTx", transaction)
+	if err != nil {
+		// A failed transfer has no ID yet, what was asked for is kept instead
+		err = store.auditFailure(ctx, AuditRecord{
+			Action:     util.AuditTransferCreated,
+			TargetType: util.AuditTargetTransfer,
+			After:      arg,
+		}, err)
+	}
+	return result, err
+}
+
+func checkedTransfer(ctx context.Context, q *Queries, arg TransferTxParams, chargeFees bool) (TransferTxResult, error) {
+	/** Makes a transfer on behalf of the owner of the source account using the caller's
+	transaction. It must fit within the owner's transfer limits, and with chargeFees the
+	fees from the currency's schedule are charged on top. A transfer that was already made
+	with the same idempotency key is returned instead and counts against nothing. */
+	existing, found, err := findIdempotentTransfer(ctx, q, arg.IdempotencyKey)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	if found {
+		return replayTransfer(ctx, q, existing)
+	}
+
+	fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+
+	err = checkTransferLimits(ctx, q, fromAccount, arg.Amount)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+
+	var quote feeQuote
+	if chargeFees {
+		quote, err = quoteTransferFees(ctx, q, fromAccount, arg.Amount)
+		if err != nil {
+			return TransferTxResult{}, err
+		}
+	}
+	return transferMoneyWithFees(ctx, q, arg, quote)
+}
+
+func transferMoney(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	/** Makes a transfer that is one step of a larger customer operation: a batch leg, a
+	payroll row or a captured hold. The owner's transfer limits apply, no fees are charged. */
+	return checkedTransfer(ctx, q, arg, false)
+}
+
+func internalTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	/** Moves money the bank moves on its own account rather than the owner's, which is
+	only the sweep of a closing account's balance into another account of the same owner.
+	Transfer limits and fees don't apply; the source account must still cover the amount.
+	Reversals and interest postings update balances through their own paths. */
+	return transferMoneyWithFees(ctx, q, arg, feeQuote{})
+}
+
+func transferMoneyWithFees(ctx context.Context, q *Queries, arg TransferTxParams, quote feeQuote) (result TransferTxResult, err error) {
+	existing, found, err := findIdempotentTransfer(ctx, q, arg.IdempotencyKey)
+	if err != nil {
+		return
+	}
+	if found {
+		return replayTransfer(ctx, q, existing)
+	}
+
+	// Create Transfer record
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		IdempotencyKey: sql.NullString{
+			String: arg.IdempotencyKey,
+			Valid:  arg.IdempotencyKey != "",
+		},
+		Memo: sql.NullString{
+			String: arg.Memo,
+			Valid:  arg.Memo != "",
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	err = recordTransferCreated(ctx, q, result.Transfer)
+	if err != nil {
+		return
+	}
+
+	err = auditTransferCreated(ctx, q, result.Transfer)
+	if err != nil {
+		return
+	}
+
+	// Create entry records
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    -1 * arg.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	if len(quote.charges) == 0 {
+		result.FromAccount, result.ToAccount, err = transferBalances(
+			ctx,
+			q,
 			arg.FromAccountID,
 			arg.ToAccountID,
 			arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+		)
+	} else {
+		result.FromAccount, result.ToAccount, result.Fees, err = transferBalancesWithFees(ctx, q, arg, result.Transfer, quote)
+	}
+	if err != nil {
+		return
+	}
 
-		// Create entry records
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -1 * arg.Amount,
-		})
-		if err != nil {
-			return err
+	err = checkTransferAccounts(result.FromAccount, result.ToAccount)
+	if err != nil {
+		return
+	}
+
+	err = checkAvailableFunds(ctx, q, result.FromAccount)
+	if err != nil {
+		return
+	}
+
+	observeTransfer(q, result.FromAccount.Currency, arg.Amount)
+	return
+}
+
+func transferBalancesWithFees(
+	ctx context.Context,
+	q *Queries,
+	arg TransferTxParams,
+	transfer Transfer,
+	quote feeQuote,
+) (fromAccount Account, toAccount Account, fees []TransferFee, err error) {
+	/** Posts the fees and moves the amount and the fees between the balances. */
+	fees, err = postTransferFees(ctx, q, transfer, quote)
+	if err != nil {
+		return
+	}
+
+	// Summed per account since the recipient may be the revenue account itself
+	amounts := map[int64]int64{}
+	amounts[arg.FromAccountID] -= arg.Amount + quote.total()
+	amounts[arg.ToAccountID] += arg.Amount
+	amounts[quote.schedule.RevenueAccountID] += quote.total()
+
+	accounts, err := addBalances(ctx, q, amounts)
+	if err != nil {
+		return
+	}
+	return accounts[arg.FromAccountID], accounts[arg.ToAccountID], fees, nil
+}
+
+// IsTransferRejection reports whether a transfer was refused by the rules, such as
+// insufficient funds or a frozen account, as opposed to a database error worth retrying.
+func IsTransferRejection(err error) bool {
+	for _, rejection := range []error{
+		ErrAccountFrozen,
+		ErrAccountClosed,
+		ErrCurrencyMismatch,
+		ErrInsufficientFunds,
+		ErrTransferLimitExceeded,
+	} {
+		if errors.Is(err, rejection) {
+			return true
 		}
+	}
+	return false
+}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+func checkAvailableFunds(ctx context.Context, q *Queries, fromAccount Account) error {
+	/** Runs on the source account row returned by the balance update, which stays locked
+	until the transaction ends. Authorizations lock the same row before they add a hold,
+	so the money a transfer takes can't be reserved by another hold at the same time.
+	The new balance must still cover every active hold. */
+	held, err := q.GetHeldAmount(ctx, fromAccount.ID)
+	if err != nil {
+		return err
+	}
+
+	if fromAccount.Balance < held {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+func findIdempotentTransfer(ctx context.Context, q *Queries, idempotencyKey string) (transfer Transfer, found bool, err error) {
+	/** Looks up a transfer previously made with the same key. An empty key never matches. */
+	if idempotencyKey == "" {
+		return
+	}
+
+	transfer, err = q.GetTransferByIdempotencyKey(ctx, sql.NullString{
+		String: idempotencyKey,
+		Valid:  true,
+	})
+	if err == sql.ErrNoRows {
+		return transfer, false, nil
+	}
+	return transfer, err == nil, err
+}
+
+func replayTransfer(ctx context.Context, q *Queries, transfer Transfer) (result TransferTxResult, err error) {
+	/** Builds the result for a transfer that already happened. No money moves, so the
+	entries are left empty and the accounts reflect their current balances. */
+	result.Transfer = transfer
+
+	result.FromAccount, err = q.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return
+	}
+
+	result.ToAccount, err = q.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return
+	}
+
+	result.Fees, err = q.ListTransferFees(ctx, transfer.ID)
+	return
+}
+
+func transferBalances(
+	ctx context.Context,
+	q *Queries,
+	fromAccountID int64,
+	toAccountID int64,
+	amount int64,
+) (fromAccount Account, toAccount Account, err error) {
+	/** Moves amount between the two account balances.
+	AddAccountBalance does the update atomically so no reading needed previously.
+	Accounts are always locked in ascending ID order (resource hierarchy) so two
+	opposing transactions happening at once cannot deadlock. */
+	if fromAccountID < toAccountID {
+		fromAccount, toAccount, err = addMoney(ctx, q, fromAccountID, -amount, toAccountID, amount)
+	} else {
+		toAccount, fromAccount, err = addMoney(ctx, q, toAccountID, amount, fromAccountID, -amount)
+	}
+	return
+}
+
+func addBalances(ctx context.Context, q *Queries, amounts map[int64]int64) (map[int64]Account, error) {
+	/** Applies several balance changes at once. Accounts are updated in ascending ID
+	order like in transferBalances, and each account appears once with its net amount. */
+	ids := make([]int64, 0, len(amounts))
+	for id := range amounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	accounts := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     id,
+			Amount: amounts[id],
 		})
 		if err != nil {
-			return err
-		}
-
-		// Update account balances based on amount
-		// AddAccountBalance does the update atomically so no reading needed previously
-		// GetAccountForUpdate locks the row so we can update it consistently
-
-		// Also need to do resource hierarchy to avoid deadlocking on accounts if
-		// two opposing transactions happening at once.
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(
-				ctx,
-				q,
-				arg.FromAccountID,
-				-arg.Amount,
-				arg.ToAccountID,
-				arg.Amount,
-			)
-			if err != nil {
-				return err
-			}
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(
-				ctx,
-				q,
-				arg.ToAccountID,
-				arg.Amount,
-				arg.FromAccountID,
-				-arg.Amount,
-			)
-			if err != nil {
-				return err
-			}
+			return nil, err
 		}
-		return nil
+		accounts[id] = account
 	}
-	err := store.execTx(ctx, transaction)
-	return result, err
+	return accounts, nil
 }
 
 func addMoney(
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferMoneyTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := CreateRandomAccount(t)
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
 	account2 := CreateRandomAccount(t)
 
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
@@ -117,8 +118,8 @@ func TestTransferMoneyTx(t *testing.T) {
 func TestTransferMoneyTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := CreateRandomAccount(t)
-	account2 := CreateRandomAccount(t)
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
 
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
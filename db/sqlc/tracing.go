@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/jasonwebb3152/simplebank/db/sqlc")
+
+// Open opens a database like sql.Open, with a driver that traces the store's queries.
+// A query's span lasts until its rows are closed, so it covers reading and scanning the
+// result, where errors of statements like INSERT ... RETURNING only show up.
+func Open(driverName, dataSourceName string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	var connector driver.Connector = dsnConnector{driver: drv, dsn: dataSourceName}
+	if drvCtx, ok := drv.(driver.DriverContext); ok {
+		connector, err = drvCtx.OpenConnector(dataSourceName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(tracedConnector{connector}), nil
+}
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type tracedConnector struct {
+	driver.Connector
+}
+
+func (c tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+// tracedDBTX marks the queries of the store for tracing. Inside a transaction the
+// queries are attached to the transaction's span, since the closures running them
+// only have the context the transaction was started with.
+type tracedDBTX struct {
+	db     DBTX
+	parent trace.Span
+}
+
+type tracedQueryKey struct{}
+
+func (t *tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.db.ExecContext(t.context(ctx), query, args...)
+}
+
+func (t *tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.db.PrepareContext(t.context(ctx), query)
+}
+
+func (t *tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.db.QueryContext(t.context(ctx), query, args...)
+}
+
+func (t *tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.db.QueryRowContext(t.context(ctx), query, args...)
+}
+
+func (t *tracedDBTX) context(ctx context.Context) context.Context {
+	if t.parent != nil {
+		ctx = trace.ContextWithSpan(ctx, t.parent)
+	}
+	// Health checks and migrations use the connection too, but stay out of the traces
+	return context.WithValue(ctx, tracedQueryKey{}, true)
+}
+
+// tracedConn starts a span for every statement of the store. Only the methods
+// database/sql calls for the store's queries are traced, the rest is passed on.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startQuerySpan(ctx, query)
+	if span == nil {
+		return execer.ExecContext(ctx, query, args)
+	}
+	defer span.End()
+
+	result, err := execer.ExecContext(ctx, query, args)
+	recordError(span, err)
+	return result, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startQuerySpan(ctx, query)
+	if span == nil {
+		return queryer.QueryContext(ctx, query, args)
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // only drivers without BeginTx get here
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// tracedRows ends the query's span once database/sql is done reading, which for
+// QueryRowContext is after Scan.
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	read int
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch err {
+	case nil:
+		r.read++
+	case io.EOF:
+	default:
+		recordError(r.span, err)
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	recordError(r.span, err)
+	// Zero rows is how a single-row query that found nothing shows up
+	r.span.SetAttributes(semconv.DBResponseReturnedRows(r.read))
+	r.span.End()
+	return err
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	/** Returns a nil span for queries that did not come through tracedDBTX. */
+	if traced, _ := ctx.Value(tracedQueryKey{}).(bool); !traced {
+		return ctx, nil
+	}
+
+	name := queryName(query)
+	return tracer.Start(ctx, "db."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(name),
+		),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	// No rows is an answer, not a failure
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+func queryName(query string) string {
+	/** sqlc starts every query with "-- name: GetAccount :one". */
+	const prefix = "-- name: "
+	if !strings.HasPrefix(query, prefix) {
+		return "query"
+	}
+
+	name, _, _ := strings.Cut(query[len(prefix):], " ")
+	return name
+}
+
+func startTxSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	/** name is the Store method that runs the transaction. */
+	return tracer.Start(ctx, "db."+name, trace.WithAttributes(semconv.DBSystemNamePostgreSQL))
+}
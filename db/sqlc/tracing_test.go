@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+var errFakeRead = errors.New("unique violation while returning the row")
+
+// fakeDriver answers every query with the rows named after it, so the tests need no database
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	err  error
+}
+
+func init() {
+	sql.Register("tracing-fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	switch queryName(query) {
+	case "GetOne":
+		return &fakeRows{rows: [][]driver.Value{{int64(1)}}}, nil
+	case "GetBroken":
+		return &fakeRows{err: errFakeRead}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestTracedQueryRow(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	conn, err := Open("tracing-fake", "")
+	require.NoError(t, err)
+	defer conn.Close()
+	q := New(&tracedDBTX{db: conn})
+
+	testCases := []struct {
+		name   string
+		err    error
+		status codes.Code
+		rows   int
+	}{
+		{"GetOne", nil, codes.Unset, 1},
+		{"GetNone", sql.ErrNoRows, codes.Unset, 0},
+		// Only surfaces when the row is read, after QueryRowContext returned
+		{"GetBroken", errFakeRead, codes.Error, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var id int64
+			err := q.db.QueryRowContext(context.Background(), "-- name: "+tc.name+" :one").Scan(&id)
+			require.ErrorIs(t, err, tc.err)
+
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+			require.Equal(t, "db."+tc.name, span.Name())
+			require.Equal(t, tc.status, span.Status().Code)
+			require.Contains(t, span.Attributes(), semconv.DBResponseReturnedRows(tc.rows))
+		})
+	}
+
+	// Queries that do not come from the store are not traced
+	before := len(recorder.Ended())
+	rows, err := conn.QueryContext(context.Background(), "-- name: GetOne :one")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Len(t, recorder.Ended(), before)
+}
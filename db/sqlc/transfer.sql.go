@@ -7,27 +7,78 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const createReversalTransfer = `-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversal_of
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo
+`
+
+type CreateReversalTransferParams struct {
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        int64         `json:"amount"`
+	ReversalOf    sql.NullInt64 `json:"reversal_of"`
+}
+
+func (q *Queries) CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createReversalTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ReversalOf,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
+		&i.IdempotencyKey,
+		&i.Memo,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  idempotency_key,
+  memo
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID  int64          `json:"from_account_id"`
+	ToAccountID    int64          `json:"to_account_id"`
+	Amount         int64          `json:"amount"`
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	Memo           sql.NullString `json:"memo"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.IdempotencyKey,
+		arg.Memo,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -35,12 +86,28 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ReversalOf,
+		&i.IdempotencyKey,
+		&i.Memo,
 	)
 	return i, err
 }
 
+const getReversedAmount = `-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversal_of = $1::bigint
+`
+
+func (q *Queries) GetReversedAmount(ctx context.Context, transferID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getReversedAmount, transferID)
+	var reversed_amount int64
+	err := row.Scan(&reversed_amount)
+	return reversed_amount, err
+}
+
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo FROM transfers
 WHERE id = $1
 `
 
@@ -53,12 +120,58 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ReversalOf,
+		&i.IdempotencyKey,
+		&i.Memo,
+	)
+	return i, err
+}
+
+const getTransferByIdempotencyKey = `-- name: GetTransferByIdempotencyKey :one
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo FROM transfers
+WHERE idempotency_key = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferByIdempotencyKey(ctx context.Context, idempotencyKey sql.NullString) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferByIdempotencyKey, idempotencyKey)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
+		&i.IdempotencyKey,
+		&i.Memo,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
+		&i.IdempotencyKey,
+		&i.Memo,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of, idempotency_key, memo FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -94,6 +207,9 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ReversalOf,
+			&i.IdempotencyKey,
+			&i.Memo,
 		); err != nil {
 			return nil, err
 		}
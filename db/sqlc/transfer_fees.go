@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+const (
+	FeeKindFlat       = "flat"
+	FeeKindPercentage = "percentage"
+)
+
+// feeCharge is one part of the fee an operation will be charged.
+type feeCharge struct {
+	kind   string
+	amount int64
+}
+
+// feeQuote is what an operation costs under its fee schedule, before anything is posted.
+type feeQuote struct {
+	schedule FeeSchedule
+	charges  []feeCharge
+}
+
+func (quote feeQuote) total() (total int64) {
+	for _, charge := range quote.charges {
+		total += charge.amount
+	}
+	return
+}
+
+func quoteTransferFees(ctx context.Context, q *Queries, fromAccount Account, amount int64) (quote feeQuote, err error) {
+	/** Works out the fees of a transfer from the schedule of its currency.
+	Currencies without a schedule are free, and so are the owner's first free_per_month
+	transfers of the calendar month (UTC). The caller must already hold the owner's user
+	lock so concurrent transfers agree on how many free ones are left. */
+	quote.schedule, err = q.GetFeeSchedule(ctx, GetFeeScheduleParams{
+		Currency:  fromAccount.Currency,
+		Operation: util.FeeOperationTransfer,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+
+	if quote.schedule.FreePerMonth > 0 {
+		now := time.Now().UTC()
+		count, err := q.CountOutgoingTransfers(ctx, CountOutgoingTransfersParams{
+			Owner:    fromAccount.Owner,
+			Currency: fromAccount.Currency,
+			Since:    time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return quote, err
+		}
+		if count < int64(quote.schedule.FreePerMonth) {
+			return quote, nil
+		}
+	}
+
+	charges := []feeCharge{
+		{FeeKindFlat, quote.schedule.FlatFee},
+		{FeeKindPercentage, util.PercentageFee(amount, quote.schedule.PercentageBps)},
+	}
+	for _, charge := range charges {
+		if charge.amount > 0 {
+			quote.charges = append(quote.charges, charge)
+		}
+	}
+	return
+}
+
+func postTransferFees(ctx context.Context, q *Queries, transfer Transfer, quote feeQuote) ([]TransferFee, error) {
+	/** Posts every charge of the quote as its own pair of entries, a debit on the paying
+	account and a credit on the revenue account. Balances are left to the caller. */
+	fees := make([]TransferFee, 0, len(quote.charges))
+	for _, charge := range quote.charges {
+		entry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: transfer.FromAccountID,
+			Amount:    -charge.amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		revenueEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: quote.schedule.RevenueAccountID,
+			Amount:    charge.amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		fee, err := q.CreateTransferFee(ctx, CreateTransferFeeParams{
+			TransferID:     transfer.ID,
+			FeeScheduleID:  quote.schedule.ID,
+			Kind:           charge.kind,
+			Amount:         charge.amount,
+			EntryID:        entry.ID,
+			RevenueEntryID: revenueEntry.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+	return fees, nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setFeeSchedule(t *testing.T, currency string, flatFee int64, percentageBps, freePerMonth int32) Account {
+	revenue := createTestAccount(t, 0, currency, util.CheckingAccount)
+	_, err := testQueries.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		Currency:         currency,
+		Operation:        util.FeeOperationTransfer,
+		FlatFee:          flatFee,
+		PercentageBps:    percentageBps,
+		FreePerMonth:     freePerMonth,
+		RevenueAccountID: revenue.ID,
+	})
+	require.NoError(t, err)
+
+	// Schedules apply to every transfer in the currency, so don't leak them into other tests
+	t.Cleanup(func() {
+		err := testQueries.DeleteFeeSchedule(context.Background(), DeleteFeeScheduleParams{
+			Currency:  currency,
+			Operation: util.FeeOperationTransfer,
+		})
+		require.NoError(t, err)
+	})
+	return revenue
+}
+
+func TestTransferTxFees(t *testing.T) {
+	store := NewStore(testDB)
+
+	revenue := setFeeSchedule(t, util.EUR, 10, 100, 1)
+	account1 := createTestAccount(t, 10000, util.EUR, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.EUR, util.CheckingAccount)
+
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         500,
+		IdempotencyKey: util.RandomString(20),
+	}
+
+	// The first transfer of the month is free
+	result, err := store.TransferMoneyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, result.Fees)
+	require.Equal(t, int64(9500), result.FromAccount.Balance)
+
+	arg.IdempotencyKey = util.RandomString(20)
+	result, err = store.TransferMoneyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 2)
+	require.Equal(t, int64(9500-500-15), result.FromAccount.Balance)
+	require.Equal(t, int64(1000), result.ToAccount.Balance)
+
+	expected := []struct {
+		kind   string
+		amount int64
+	}{
+		{FeeKindFlat, 10},
+		{FeeKindPercentage, 5},
+	}
+	for i, fee := range result.Fees {
+		require.Equal(t, result.Transfer.ID, fee.TransferID)
+		require.Equal(t, expected[i].kind, fee.Kind)
+		require.Equal(t, expected[i].amount, fee.Amount)
+
+		entry, err := testQueries.GetEntry(context.Background(), fee.EntryID)
+		require.NoError(t, err)
+		require.Equal(t, account1.ID, entry.AccountID)
+		require.Equal(t, -fee.Amount, entry.Amount)
+
+		revenueEntry, err := testQueries.GetEntry(context.Background(), fee.RevenueEntryID)
+		require.NoError(t, err)
+		require.Equal(t, revenue.ID, revenueEntry.AccountID)
+		require.Equal(t, fee.Amount, revenueEntry.Amount)
+	}
+
+	updatedRevenue, err := testQueries.GetAccount(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), updatedRevenue.Balance)
+
+	// Replaying the transfer shows the same fees without charging them again
+	replayed, err := store.TransferMoneyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result.Fees, replayed.Fees)
+	require.Equal(t, result.FromAccount.Balance, replayed.FromAccount.Balance)
+}
+
+func TestTransferTxFeeToRevenueAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	revenue := setFeeSchedule(t, util.CAD, 7, 0, 0)
+	account := createTestAccount(t, 100, util.CAD, util.CheckingAccount)
+
+	result, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   revenue.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 1)
+	require.Equal(t, int64(43), result.FromAccount.Balance)
+	require.Equal(t, int64(57), result.ToAccount.Balance)
+}
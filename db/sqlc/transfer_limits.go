@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	LimitPerTransfer = "per_transfer"
+	LimitDaily       = "daily"
+	LimitMonthly     = "monthly"
+)
+
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// TransferLimitError reports which limit a transfer would break and how much
+// could still be sent under it.
+type TransferLimitError struct {
+	Limit     string `json:"limit"`
+	Currency  string `json:"currency"`
+	Max       int64  `json:"max"`
+	Remaining int64  `json:"remaining"`
+}
+
+func (e *TransferLimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %d %s, %d remaining", ErrTransferLimitExceeded, e.Limit, e.Max, e.Currency, e.Remaining)
+}
+
+func (e *TransferLimitError) Is(target error) bool {
+	return target == ErrTransferLimitExceeded
+}
+
+func checkTransferLimits(ctx context.Context, q *Queries, fromAccount Account, amount int64) error {
+	/** Makes sure the owner of the source account stays within their limits for its currency.
+	The owner's user row is locked first so concurrent transfers by the same user are
+	serialized and each one sees the totals of those committed before it. Every customer
+	transfer goes through here; see internalTransfer for the one exception. */
+	_, err := q.GetUserForUpdate(ctx, fromAccount.Owner)
+	if err != nil {
+		return err
+	}
+
+	limit, err := q.GetTransferLimit(ctx, GetTransferLimitParams{
+		Currency: fromAccount.Currency,
+		Username: fromAccount.Owner,
+	})
+	if err != nil {
+		// Currencies without a configured limit are unrestricted
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if amount > limit.PerTransfer {
+		return &TransferLimitError{
+			Limit:     LimitPerTransfer,
+			Currency:  limit.Currency,
+			Max:       limit.PerTransfer,
+			Remaining: limit.PerTransfer,
+		}
+	}
+
+	now := time.Now().UTC()
+	windows := []struct {
+		name  string
+		max   int64
+		since time.Time
+	}{
+		{LimitDaily, limit.Daily, now.Add(-24 * time.Hour)},
+		{LimitMonthly, limit.Monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, window := range windows {
+		total, err := q.GetOutgoingTransferTotal(ctx, GetOutgoingTransferTotalParams{
+			Owner:    fromAccount.Owner,
+			Currency: fromAccount.Currency,
+			Since:    window.since,
+		})
+		if err != nil {
+			return err
+		}
+
+		if total+amount > window.max {
+			return &TransferLimitError{
+				Limit:     window.name,
+				Currency:  limit.Currency,
+				Max:       window.max,
+				Remaining: max(window.max-total, 0),
+			}
+		}
+	}
+	return nil
+}
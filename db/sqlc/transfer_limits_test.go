@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setTransferLimitOverride(t *testing.T, account Account, perTransfer, daily, monthly int64) {
+	limit, err := testQueries.UpsertTransferLimitOverride(context.Background(), UpsertTransferLimitOverrideParams{
+		Username:    sql.NullString{String: account.Owner, Valid: true},
+		Currency:    account.Currency,
+		PerTransfer: perTransfer,
+		Daily:       daily,
+		Monthly:     monthly,
+	})
+	require.NoError(t, err)
+	require.Equal(t, daily, limit.Daily)
+}
+
+func TestTransferLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+
+	// Upserting twice replaces the first override
+	setTransferLimitOverride(t, account1, 1, 1, 1)
+	setTransferLimitOverride(t, account1, 50, 100, 120)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	requireLimit := func(err error, limit string, remaining int64) {
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+		var limitErr *TransferLimitError
+		require.ErrorAs(t, err, &limitErr)
+		require.Equal(t, limit, limitErr.Limit)
+		require.Equal(t, util.USD, limitErr.Currency)
+		require.Equal(t, remaining, limitErr.Remaining)
+	}
+
+	requireLimit(transfer(51), LimitPerTransfer, 50)
+
+	require.NoError(t, transfer(50))
+	require.NoError(t, transfer(40))
+	requireLimit(transfer(20), LimitDaily, 10)
+	require.NoError(t, transfer(10))
+
+	// Raise the daily limit above the monthly one
+	setTransferLimitOverride(t, account1, 50, 1000, 120)
+	requireLimit(transfer(30), LimitMonthly, 20)
+
+	// Money flowing in does not count
+	_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+	require.NoError(t, transfer(20))
+}
+
+func TestTransferLimitsDefault(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 0, util.EUR, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.EUR, util.CheckingAccount)
+
+	limit, err := testQueries.GetTransferLimit(context.Background(), GetTransferLimitParams{
+		Currency: util.EUR,
+		Username: account1.Owner,
+	})
+	require.NoError(t, err)
+	require.False(t, limit.Username.Valid)
+
+	_, err = store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        limit.PerTransfer + 1,
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+}
+
+func TestTransferLimitsConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	setTransferLimitOverride(t, account1, 100, 100, 1000)
+
+	// Only five of the ten concurrent transfers fit into the daily limit
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        20,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+	}
+	require.Equal(t, 5, succeeded)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(900), account.Balance)
+}
+
+func TestTransferLimitsBatchAndCapture(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	setTransferLimitOverride(t, account1, 50, 100, 1000)
+
+	batch := func(amounts ...int64) error {
+		legs := make([]BatchTransferLeg, len(amounts))
+		for i, amount := range amounts {
+			legs[i] = BatchTransferLeg{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			}
+		}
+		_, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+			Currency: util.USD,
+			Legs:     legs,
+		})
+		return err
+	}
+
+	// Each leg counts on its own, and against the legs before it
+	err := batch(30, 51)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+	var legErr *BatchLegError
+	require.ErrorAs(t, err, &legErr)
+	require.Equal(t, 1, legErr.Index)
+
+	require.NoError(t, batch(50, 40))
+	require.ErrorIs(t, batch(10, 10), ErrTransferLimitExceeded)
+
+	// Captures are transfers of the account's owner too
+	authorized, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        20,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+}
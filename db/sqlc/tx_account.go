@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	/** Opens an account and announces it with an account.created event. */
+	var result Account
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     util.AuditAccountCreated,
+			TargetType: util.AuditTargetAccount,
+			TargetID:   strconv.FormatInt(result.ID, 10),
+			After:      result,
+		}, util.AuditSuccess)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, util.EventAccountCreated, result)
+	}
+	err := store.execTx(ctx, "CreateAccountTx", transaction)
+	if err != nil {
+		err = store.auditFailure(ctx, AuditRecord{
+			Action:     util.AuditAccountCreated,
+			TargetType: util.AuditTargetAccount,
+			After:      arg,
+		}, err)
+	}
+	return result, err
+}
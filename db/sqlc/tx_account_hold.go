@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+var (
+	ErrInsufficientFunds  = errors.New("insufficient available balance")
+	ErrSameAccount        = errors.New("cannot transfer to the same account")
+	ErrHoldNotActive      = errors.New("hold is no longer active")
+	ErrHoldExpired        = errors.New("hold has expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+	ErrPayrollHold        = errors.New("hold reserves a payroll job and is settled by it")
+)
+
+type AuthorizeTransferTxParams struct {
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        int64         `json:"amount"`
+	TTL           time.Duration `json:"ttl"`
+}
+
+type AuthorizeTransferTxResult struct {
+	Hold AccountHold `json:"hold"`
+	// Balance minus all active holds, including the new one
+	AvailableBalance int64 `json:"available_balance"`
+}
+
+func (store *SQLStore) AuthorizeTransferTx(ctx context.Context, arg AuthorizeTransferTxParams) (AuthorizeTransferTxResult, error) {
+	/** Reserves funds on the source account without moving any money.
+	The hold is later captured into a real transfer, voided, or expires. */
+	var result AuthorizeTransferTxResult
+	if arg.FromAccountID == arg.ToAccountID {
+		return result, ErrSameAccount
+	}
+
+	transaction := func(q *Queries) error {
+		// Lock the source account so concurrent authorizations see each other's holds
+		account, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		err = checkDebit(account)
+		if err != nil {
+			return err
+		}
+
+		held, err := q.GetHeldAmount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		available := account.Balance - held
+		if available < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Hold, err = q.CreateAccountHold(ctx, CreateAccountHoldParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: sql.NullInt64{
+				Int64: arg.ToAccountID,
+				Valid: true,
+			},
+			Amount:    arg.Amount,
+			ExpiresAt: time.Now().Add(arg.TTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.AvailableBalance = available - arg.Amount
+		return nil
+	}
+	err := store.execTx(ctx, "AuthorizeTransferTx", transaction)
+	return result, err
+}
+
+type CaptureTransferTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Zero captures the full held amount. Whatever is not captured is released.
+	Amount int64 `json:"amount"`
+}
+
+type CaptureTransferTxResult struct {
+	TransferTxResult
+	Hold AccountHold `json:"hold"`
+}
+
+func (store *SQLStore) CaptureTransferTx(ctx context.Context, arg CaptureTransferTxParams) (CaptureTransferTxResult, error) {
+	/** Settles an active hold by performing the actual transfer and marking the hold captured. */
+	var result CaptureTransferTxResult
+
+	transaction := func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount <= 0 || amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		// Released before the transfer, whose funds check must not count this hold
+		_, err = q.ReleaseAccountHold(ctx, ReleaseAccountHoldParams{
+			ID:     hold.ID,
+			Status: HoldStatusCaptured,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountID: hold.FromAccountID,
+			ToAccountID:   hold.ToAccountID.Int64,
+			Amount:        amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.SetAccountHoldTransfer(ctx, SetAccountHoldTransferParams{
+			ID: hold.ID,
+			TransferID: sql.NullInt64{
+				Int64: result.Transfer.ID,
+				Valid: true,
+			},
+		})
+		return err
+	}
+	err := store.execTx(ctx, "CaptureTransferTx", transaction)
+	return result, err
+}
+
+type VoidTransferTxParams struct {
+	HoldID int64 `json:"hold_id"`
+}
+
+func (store *SQLStore) VoidTransferTx(ctx context.Context, arg VoidTransferTxParams) (AccountHold, error) {
+	/** Cancels an active hold and releases the reserved funds. */
+	var result AccountHold
+
+	transaction := func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.ReleaseAccountHold(ctx, ReleaseAccountHoldParams{
+			ID:     hold.ID,
+			Status: HoldStatusVoided,
+		})
+		return err
+	}
+	err := store.execTx(ctx, "VoidTransferTx", transaction)
+	return result, err
+}
+
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64) (AccountHold, error) {
+	/** Locks the hold row and makes sure it can still be captured or voided. */
+	hold, err := q.GetAccountHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if hold.Status != HoldStatusActive {
+		return hold, ErrHoldNotActive
+	}
+
+	// Only the payroll job draws on its hold, row by row
+	if !hold.ToAccountID.Valid {
+		return hold, ErrPayrollHold
+	}
+
+	// The sweeper may not have caught up with this hold yet
+	if !hold.ExpiresAt.After(time.Now()) {
+		return hold, ErrHoldExpired
+	}
+	return hold, nil
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizeAndCaptureTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	// Cannot hold more than the account has
+	_, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+		TTL:           time.Minute,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	authorized, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusActive, authorized.Hold.Status)
+	require.Equal(t, int64(10), authorized.Hold.Amount)
+	require.Equal(t, account1.Balance-10, authorized.AvailableBalance)
+	require.WithinDuration(t, time.Now().Add(time.Minute), authorized.Hold.ExpiresAt, time.Second)
+
+	// Authorizing does not move money
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 11,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	captured, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 7,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, captured.Hold.Status)
+	require.True(t, captured.Hold.TransferID.Valid)
+	require.Equal(t, captured.Transfer.ID, captured.Hold.TransferID.Int64)
+	require.True(t, captured.Hold.ReleasedAt.Valid)
+	require.Equal(t, int64(7), captured.Transfer.Amount)
+	require.Equal(t, account1.Balance-7, captured.FromAccount.Balance)
+	require.Equal(t, account2.Balance+7, captured.ToAccount.Balance)
+
+	// A hold can only be settled once
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	_, err = store.VoidTransferTx(context.Background(), VoidTransferTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+}
+
+func TestVoidTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	authorized, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+	require.Zero(t, authorized.AvailableBalance)
+
+	// Everything is already held
+	_, err = store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+		TTL:           time.Minute,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	voided, err := store.VoidTransferTx(context.Background(), VoidTransferTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusVoided, voided.Status)
+	require.False(t, voided.TransferID.Valid)
+	require.True(t, voided.ReleasedAt.Valid)
+
+	// Voiding frees the funds again
+	_, err = store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+}
+
+func TestExpireAccountHolds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	hold, err := testQueries.CreateAccountHold(context.Background(), CreateAccountHoldParams{
+		FromAccountID: account1.ID,
+		ToAccountID: sql.NullInt64{
+			Int64: account2.ID,
+			Valid: true,
+		},
+		Amount:    account1.Balance,
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	// Expired holds no longer count against the balance, even before the sweep
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+
+	_, err = store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	rows, err := testQueries.ExpireAccountHolds(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, rows, int64(1))
+
+	expired, err := testQueries.GetAccountHold(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusExpired, expired.Status)
+	require.True(t, expired.ReleasedAt.Valid)
+}
+
+func TestAuthorizeTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	// Concurrent holds must never reserve more than the balance
+	n := 10
+	amount := int64(30)
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+				TTL:           time.Minute,
+			})
+			errs <- err
+		}()
+	}
+
+	// Only three holds of 30 fit into a balance of 100
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrInsufficientFunds)
+	}
+	require.Equal(t, 3, succeeded)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), held)
+}
+
+func TestTransferMoneyTxHeldFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	authorize := func(amount int64) AccountHold {
+		result, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+			TTL:           time.Minute,
+		})
+		require.NoError(t, err)
+		return result.Hold
+	}
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	hold1 := authorize(60)
+	hold2 := authorize(30)
+
+	// Only the 10 nobody holds can be transferred
+	require.ErrorIs(t, transfer(11), ErrInsufficientFunds)
+	require.NoError(t, transfer(10))
+
+	// Capturing spends the hold's own funds, never those of another hold
+	_, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: hold1.ID,
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, transfer(1), ErrInsufficientFunds)
+
+	captured, err := store.CaptureTransferTx(context.Background(), CaptureTransferTxParams{
+		HoldID: hold2.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, captured.FromAccount.Balance)
+}
+
+func TestAuthorizeTransferTxSameAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+
+	_, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   account.ID,
+		Amount:        10,
+		TTL:           time.Minute,
+	})
+	require.ErrorIs(t, err, ErrSameAccount)
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+var (
+	ErrAccountFrozen           = errors.New("account is frozen")
+	ErrAccountClosed           = errors.New("account is closed")
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrNonZeroBalance          = errors.New("account balance is not zero")
+	ErrAccountHasHolds         = errors.New("account has active holds")
+	ErrInvalidSweepAccount     = errors.New("invalid sweep account")
+)
+
+// Statuses each status may move to. Frozen accounts must be unfrozen before they are closed.
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive},
+	AccountStatusClosed: {AccountStatusActive},
+}
+
+func canChangeAccountStatus(from, to string) bool {
+	for _, allowed := range accountStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTransferAccounts(fromAccount, toAccount Account) error {
+	/** Runs on the rows returned by the balance update, which are locked, so a
+	concurrent status change cannot slip in between. */
+	if err := checkDebit(fromAccount); err != nil {
+		return err
+	}
+	return checkCredit(toAccount)
+}
+
+func checkDebit(account Account) error {
+	/** Money may only leave active accounts. */
+	switch account.Status {
+	case AccountStatusFrozen:
+		return fmt.Errorf("account %d: %w", account.ID, ErrAccountFrozen)
+	case AccountStatusClosed:
+		return fmt.Errorf("account %d: %w", account.ID, ErrAccountClosed)
+	}
+	return nil
+}
+
+func checkCredit(account Account) error {
+	/** Frozen accounts keep receiving money unless their credits are frozen too. */
+	switch {
+	case account.Status == AccountStatusClosed:
+		return fmt.Errorf("account %d: %w", account.ID, ErrAccountClosed)
+	case account.Status == AccountStatusFrozen && account.CreditsFrozen:
+		return fmt.Errorf("account %d: %w", account.ID, ErrAccountFrozen)
+	}
+	return nil
+}
+
+type UpdateAccountStatusTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	// Username of whoever makes the change
+	ChangedBy string `json:"changed_by"`
+	// Only used when freezing, also reject incoming transfers
+	FreezeCredits bool `json:"freeze_credits"`
+	// Only used when closing, another account of the same owner and currency that
+	// receives the remaining balance. Zero requires the balance to be zero already.
+	SweepToAccountID int64 `json:"sweep_to_account_id"`
+}
+
+type UpdateAccountStatusTxResult struct {
+	Account Account             `json:"account"`
+	Change  AccountStatusChange `json:"change"`
+	// Set when closing moved the remaining balance
+	Sweep *TransferTxResult `json:"sweep,omitempty"`
+}
+
+func (store *SQLStore) UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (UpdateAccountStatusTxResult, error) {
+	/** Freezes, unfreezes, closes or reopens an account and records who did it and why.
+	Accounts are never deleted, so their entries and transfers stay intact. */
+	var result UpdateAccountStatusTxResult
+
+	transaction := func(q *Queries) error {
+		account, sweepAccount, err := lockStatusAccounts(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		if !canChangeAccountStatus(account.Status, arg.Status) {
+			return fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, account.Status, arg.Status)
+		}
+
+		var sweepTransferID sql.NullInt64
+		if arg.Status == AccountStatusClosed {
+			held, err := q.GetHeldAmount(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+			if held > 0 {
+				return ErrAccountHasHolds
+			}
+
+			// Interest earned so far is part of the balance that must be swept out
+			account, err = settleInterest(ctx, q, account)
+			if err != nil {
+				return err
+			}
+
+			if account.Balance != 0 {
+				result.Sweep, err = sweepAccountBalance(ctx, q, account, sweepAccount)
+				if err != nil {
+					return err
+				}
+				sweepTransferID = sql.NullInt64{
+					Int64: result.Sweep.Transfer.ID,
+					Valid: true,
+				}
+			}
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:            account.ID,
+			Status:        arg.Status,
+			CreditsFrozen: arg.Status == AccountStatusFrozen && arg.FreezeCredits,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Change, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID:       account.ID,
+			FromStatus:      account.Status,
+			ToStatus:        arg.Status,
+			Reason:          arg.Reason,
+			ChangedBy:       arg.ChangedBy,
+			SweepTransferID: sweepTransferID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, AuditRecord{
+			Action:     accountStatusAuditAction(arg.Status),
+			TargetType: util.AuditTargetAccount,
+			TargetID:   strconv.FormatInt(account.ID, 10),
+			Reason:     arg.Reason,
+			Before:     account,
+			After:      result.Account,
+		}, util.AuditSuccess)
+	}
+	err := store.execTx(ctx, "UpdateAccountStatusTx", transaction)
+	if err != nil {
+		err = store.auditFailure(ctx, AuditRecord{
+			Action:     accountStatusAuditAction(arg.Status),
+			TargetType: util.AuditTargetAccount,
+			TargetID:   strconv.FormatInt(arg.AccountID, 10),
+			After:      arg,
+		}, err)
+	}
+	return result, err
+}
+
+func accountStatusAuditAction(status string) string {
+	/** Accounts are never deleted, closing one is what the audit log records instead. */
+	if status == AccountStatusClosed {
+		return util.AuditAccountClosed
+	}
+	return util.AuditAccountStatusChange
+}
+
+func lockStatusAccounts(ctx context.Context, q *Queries, arg UpdateAccountStatusTxParams) (account Account, sweepAccount Account, err error) {
+	/** Locks the account and the sweep account, if any, in ascending ID order like transfers do. */
+	if arg.SweepToAccountID == 0 {
+		account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		return
+	}
+
+	if arg.Status != AccountStatusClosed || arg.SweepToAccountID == arg.AccountID {
+		err = ErrInvalidSweepAccount
+		return
+	}
+
+	ids := []int64{arg.AccountID, arg.SweepToAccountID}
+	if ids[1] < ids[0] {
+		ids[0], ids[1] = ids[1], ids[0]
+	}
+
+	locked := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		locked[id], err = q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows && id == arg.SweepToAccountID {
+				err = ErrInvalidSweepAccount
+			}
+			return
+		}
+	}
+	return locked[arg.AccountID], locked[arg.SweepToAccountID], nil
+}
+
+func sweepAccountBalance(ctx context.Context, q *Queries, account Account, sweepAccount Account) (*TransferTxResult, error) {
+	/** Moves the whole balance of an account that is about to close into another
+	active account of the same owner and currency. */
+	if account.Balance < 0 || sweepAccount.ID == 0 {
+		return nil, ErrNonZeroBalance
+	}
+
+	if sweepAccount.Owner != account.Owner ||
+		sweepAccount.Currency != account.Currency ||
+		sweepAccount.Status != AccountStatusActive {
+		return nil, ErrInvalidSweepAccount
+	}
+
+	sweep, err := internalTransfer(ctx, q, TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   sweepAccount.ID,
+		Amount:        account.Balance,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sweep, nil
+}
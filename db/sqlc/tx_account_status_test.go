@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func changeAccountStatus(store Store, account Account, status string, freezeCredits bool, sweepTo int64) (UpdateAccountStatusTxResult, error) {
+	return store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID:        account.ID,
+		Status:           status,
+		Reason:           util.RandomString(10),
+		ChangedBy:        account.Owner,
+		FreezeCredits:    freezeCredits,
+		SweepToAccountID: sweepTo,
+	})
+}
+
+func TestFreezeAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+
+	transfer := func(from, to Account) error {
+		_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        10,
+		})
+		return err
+	}
+
+	result, err := changeAccountStatus(store, account1, AccountStatusFrozen, false, 0)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, result.Account.Status)
+	require.False(t, result.Account.CreditsFrozen)
+	require.Equal(t, AccountStatusActive, result.Change.FromStatus)
+	require.Equal(t, AccountStatusFrozen, result.Change.ToStatus)
+	require.Equal(t, account1.Owner, result.Change.ChangedBy)
+	require.Nil(t, result.Sweep)
+
+	// Debits are rejected and rolled back, credits still arrive
+	require.ErrorIs(t, transfer(account1, account2), ErrAccountFrozen)
+	require.NoError(t, transfer(account2, account1))
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(110), updated.Balance)
+
+	// Frozen accounts must be unfrozen before anything else
+	_, err = changeAccountStatus(store, account1, AccountStatusClosed, false, 0)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	_, err = changeAccountStatus(store, account1, AccountStatusActive, false, 0)
+	require.NoError(t, err)
+
+	_, err = changeAccountStatus(store, account1, AccountStatusFrozen, true, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, transfer(account2, account1), ErrAccountFrozen)
+
+	_, err = changeAccountStatus(store, account1, AccountStatusActive, false, 0)
+	require.NoError(t, err)
+	require.NoError(t, transfer(account1, account2))
+
+	changes, err := testQueries.ListAccountStatusChanges(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+}
+
+func TestCloseAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	checking := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	savings, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    checking.Owner,
+		Balance:  0,
+		Currency: util.USD,
+		Type:     util.SavingsAccount,
+	})
+	require.NoError(t, err)
+	other := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+
+	_, err = changeAccountStatus(store, checking, AccountStatusClosed, false, 0)
+	require.ErrorIs(t, err, ErrNonZeroBalance)
+
+	// The balance can only be swept to the same owner
+	_, err = changeAccountStatus(store, checking, AccountStatusClosed, false, other.ID)
+	require.ErrorIs(t, err, ErrInvalidSweepAccount)
+
+	result, err := changeAccountStatus(store, checking, AccountStatusClosed, false, savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, result.Account.Status)
+	require.Zero(t, result.Account.Balance)
+	require.NotNil(t, result.Sweep)
+	require.Equal(t, int64(100), result.Sweep.Transfer.Amount)
+	require.Equal(t, int64(100), result.Sweep.ToAccount.Balance)
+	require.Equal(t, result.Sweep.Transfer.ID, result.Change.SweepTransferID.Int64)
+
+	_, err = store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: savings.ID,
+		ToAccountID:   checking.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// Closing an empty account needs no sweep
+	_, err = changeAccountStatus(store, other, AccountStatusClosed, false, 0)
+	require.NoError(t, err)
+
+	result, err = changeAccountStatus(store, checking, AccountStatusActive, false, 0)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, result.Account.Status)
+	require.Equal(t, AccountStatusClosed, result.Change.FromStatus)
+}
+
+func TestCloseAccountSettlesInterest(t *testing.T) {
+	store := NewStore(testDB)
+
+	setInterestRate(t, util.USD, util.SavingsAccount, "0.02")
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	savings := createInterestAccount(t, 100000, util.SavingsAccount, yesterday)
+	checking, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    savings.Owner,
+		Balance:  0,
+		Currency: util.USD,
+		Type:     util.CheckingAccount,
+	})
+	require.NoError(t, err)
+
+	accrueAll(t, store, yesterday)
+
+	// 400/73 was accrued but the month is not over, so it is paid on closing
+	result, err := changeAccountStatus(store, savings, AccountStatusClosed, false, checking.ID)
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Balance)
+	require.Equal(t, int64(100005), result.Sweep.Transfer.Amount)
+
+	posting, err := testQueries.GetLastInterestPosting(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), posting.Amount)
+
+	// Nothing is left for the monthly posting to pay into the closed account
+	postAll(t, store, today)
+	account, err := testQueries.GetAccount(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.Balance)
+}
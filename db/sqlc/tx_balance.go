@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SnapshotBalancesTxParams struct {
+	// Day (UTC) whose closing balances are recorded, the time of day is ignored
+	Date time.Time `json:"date"`
+}
+
+func (store *SQLStore) SnapshotBalancesTx(ctx context.Context, arg SnapshotBalancesTxParams) (int64, error) {
+	/** Records the closing balance of arg.Date for every account that existed by then.
+	The closing balance is the current balance with the entries made after the day
+	taken back out. Accounts that already have a snapshot for the day keep it, so the
+	job can be rerun safely. Returns how many snapshots were taken. */
+	var result int64
+
+	transaction := func(q *Queries) error {
+		day := startOfDay(arg.Date)
+
+		var err error
+		result, err = q.CreateDailyBalanceSnapshots(ctx, CreateDailyBalanceSnapshotsParams{
+			SnapshotDate: day,
+			DayEnd:       day.AddDate(0, 0, 1),
+		})
+		return err
+	}
+	err := store.execTx(ctx, "SnapshotBalancesTx", transaction)
+	return result, err
+}
+
+type GetBalanceAtTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Entries made before At count towards the balance
+	At time.Time `json:"at"`
+}
+
+type GetBalanceAtTxResult struct {
+	Account Account `json:"account"`
+	Balance int64   `json:"balance"`
+}
+
+func (store *SQLStore) GetBalanceAtTx(ctx context.Context, arg GetBalanceAtTxParams) (GetBalanceAtTxResult, error) {
+	/** Works out what the balance of an account was at any point in time. */
+	var result GetBalanceAtTxResult
+
+	transaction := func(q *Queries) error {
+		var err error
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result.Balance, err = balanceAt(ctx, q, result.Account, arg.At)
+		return err
+	}
+
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	}
+	err := store.execTxWithOptions(ctx, "GetBalanceAtTx", opts, transaction)
+	return result, err
+}
+
+type GetBalanceHistoryTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// First and last day (UTC) of the series, the time of day is ignored
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type DailyBalance struct {
+	Date time.Time `json:"date"`
+	// Balance at the end of the day, or the current balance for today
+	Balance int64 `json:"balance"`
+}
+
+type GetBalanceHistoryTxResult struct {
+	Account  Account        `json:"account"`
+	Balances []DailyBalance `json:"balances"`
+}
+
+func (store *SQLStore) GetBalanceHistoryTx(ctx context.Context, arg GetBalanceHistoryTxParams) (GetBalanceHistoryTxResult, error) {
+	/** Returns the closing balance of every day between arg.From and arg.To, both included.
+	The series starts from the balance when the first day began and adds up the entries
+	of each day, so it costs one query however long it is. Days that have not started
+	yet are left out. */
+	var result GetBalanceHistoryTxResult
+
+	transaction := func(q *Queries) error {
+		var err error
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		from := startOfDay(arg.From)
+		to := startOfDay(arg.To)
+		if today := startOfDay(time.Now()); to.After(today) {
+			to = today
+		}
+		result.Balances = []DailyBalance{}
+		if from.After(to) {
+			return nil
+		}
+
+		balance, err := balanceAt(ctx, q, result.Account, from)
+		if err != nil {
+			return err
+		}
+
+		totals, err := q.ListDailyEntryTotals(ctx, ListDailyEntryTotalsParams{
+			AccountID: arg.AccountID,
+			FromTime:  from,
+			ToTime:    to.AddDate(0, 0, 1),
+		})
+		if err != nil {
+			return err
+		}
+
+		next := 0
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if next < len(totals) && totals[next].Day.Equal(day) {
+				balance += totals[next].Total
+				next++
+			}
+			result.Balances = append(result.Balances, DailyBalance{
+				Date:    day,
+				Balance: balance,
+			})
+		}
+		return nil
+	}
+
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	}
+	err := store.execTxWithOptions(ctx, "GetBalanceHistoryTx", opts, transaction)
+	return result, err
+}
+
+func balanceAt(ctx context.Context, q *Queries, account Account, at time.Time) (int64, error) {
+	/** Starts from the latest daily snapshot taken before at and adds the entries made
+	between the end of that day and at. Without a snapshot the entries made since at are
+	taken back out of the current balance instead, like the opening balance of a statement. */
+	snapshot, err := q.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+		AccountID: account.ID,
+		Before:    startOfDay(at),
+	})
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return 0, err
+		}
+
+		since, err := q.GetEntriesTotalSince(ctx, GetEntriesTotalSinceParams{
+			AccountID: account.ID,
+			CreatedAt: at,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return account.Balance - since, nil
+	}
+
+	between, err := q.GetEntriesTotalBetween(ctx, GetEntriesTotalBetweenParams{
+		AccountID: account.ID,
+		FromTime:  startOfDay(snapshot.SnapshotDate).AddDate(0, 0, 1),
+		ToTime:    at,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.Balance + between, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBalanceAtTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+
+	before := time.Now()
+	_, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+	after := time.Now().Add(time.Second)
+
+	result, err := store.GetBalanceAtTx(context.Background(), GetBalanceAtTxParams{
+		AccountID: account1.ID,
+		At:        before.Add(-time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, result.Account.ID)
+	require.Equal(t, int64(1000), result.Balance)
+
+	result, err = store.GetBalanceAtTx(context.Background(), GetBalanceAtTxParams{
+		AccountID: account1.ID,
+		At:        after,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(900), result.Balance)
+}
+
+func TestGetBalanceAtTxFromSnapshot(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+
+	// Pretend the accounts were opened two days ago with that balance
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	for _, id := range []int64{account1.ID, account2.ID} {
+		_, err := testDB.Exec("UPDATE accounts SET created_at = $1 WHERE id = $2", yesterday.AddDate(0, 0, -1), id)
+		require.NoError(t, err)
+	}
+
+	count, err := store.SnapshotBalancesTx(context.Background(), SnapshotBalancesTxParams{
+		Date: yesterday,
+	})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, int64(2))
+
+	// Taking the snapshot again leaves the existing one alone
+	_, err = store.SnapshotBalancesTx(context.Background(), SnapshotBalancesTxParams{
+		Date: yesterday,
+	})
+	require.NoError(t, err)
+
+	snapshot, err := store.GetLatestBalanceSnapshot(context.Background(), GetLatestBalanceSnapshotParams{
+		AccountID: account1.ID,
+		Before:    today,
+	})
+	require.NoError(t, err)
+	require.True(t, snapshot.SnapshotDate.Equal(yesterday))
+	require.Equal(t, int64(1000), snapshot.Balance)
+
+	_, err = store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	result, err := store.GetBalanceAtTx(context.Background(), GetBalanceAtTxParams{
+		AccountID: account1.ID,
+		At:        time.Now().Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(900), result.Balance)
+
+	history, err := store.GetBalanceHistoryTx(context.Background(), GetBalanceHistoryTxParams{
+		AccountID: account1.ID,
+		From:      yesterday,
+		To:        today.AddDate(0, 0, 5),
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Balances, 2)
+	require.True(t, history.Balances[0].Date.Equal(yesterday))
+	require.Equal(t, int64(1000), history.Balances[0].Balance)
+	require.True(t, history.Balances[1].Date.Equal(today))
+	require.Equal(t, int64(900), history.Balances[1].Balance)
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrEmptyBatch       = errors.New("batch has no legs")
+	ErrInvalidLeg       = errors.New("amount must be positive and accounts must differ")
+	ErrAccountNotFound  = errors.New("account not found")
+	ErrCurrencyMismatch = errors.New("account currency does not match the batch currency")
+)
+
+// BatchLegError tells which leg of a batch made the whole batch fail.
+type BatchLegError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("leg %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+type BatchTransferLeg struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+	// Optional note stored on the leg's transfer
+	Memo string `json:"memo"`
+}
+
+type BatchTransferTxParams struct {
+	// Every account in the batch must hold this currency
+	Currency string             `json:"currency"`
+	Legs     []BatchTransferLeg `json:"legs"`
+}
+
+type BatchTransferTxResult struct {
+	// One result per leg, in the same order as the legs
+	Legs []TransferTxResult `json:"legs"`
+}
+
+func (store *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	/** Performs all legs in a single transaction, so either every leg succeeds or none do.
+	A failure is reported as a *BatchLegError carrying the index of the offending leg. */
+	var result BatchTransferTxResult
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = batchTransfer(ctx, q, arg)
+		return err
+	}
+	err := store.execTx(ctx, "BatchTransferTx", transaction)
+	return result, err
+}
+
+func batchTransfer(ctx context.Context, q *Queries, arg BatchTransferTxParams) (result BatchTransferTxResult, err error) {
+	/** Runs the legs of a batch using the caller's transaction.
+	The owners of the source accounts and then all accounts are locked up front in a fixed
+	order to stay deadlock free, then currencies and available funds are checked before any
+	money moves. Every leg must fit within its owner's transfer limits. */
+	if len(arg.Legs) == 0 {
+		err = ErrEmptyBatch
+		return
+	}
+
+	for i, leg := range arg.Legs {
+		if leg.Amount <= 0 || leg.FromAccountID == leg.ToAccountID {
+			err = &BatchLegError{Index: i, Err: ErrInvalidLeg}
+			return
+		}
+	}
+
+	err = lockBatchOwners(ctx, q, arg.Legs)
+	if err != nil {
+		return
+	}
+
+	accounts, err := lockBatchAccounts(ctx, q, arg.Legs)
+	if err != nil {
+		return
+	}
+
+	err = checkBatchFunds(ctx, q, arg, accounts)
+	if err != nil {
+		return
+	}
+
+	result.Legs = make([]TransferTxResult, len(arg.Legs))
+	for i, leg := range arg.Legs {
+		result.Legs[i], err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountID: leg.FromAccountID,
+			ToAccountID:   leg.ToAccountID,
+			Amount:        leg.Amount,
+			Memo:          leg.Memo,
+		})
+		if err != nil {
+			err = &BatchLegError{Index: i, Err: err}
+			return
+		}
+	}
+	return
+}
+
+func lockBatchOwners(ctx context.Context, q *Queries, legs []BatchTransferLeg) error {
+	/** Locks the owners of the source accounts in username order. Single transfers take
+	their owner's lock before any account lock for the limit check, so the batch does too. */
+	firstLeg := map[string]int{}
+	owners := map[int64]string{}
+	for i, leg := range legs {
+		owner, ok := owners[leg.FromAccountID]
+		if !ok {
+			account, err := q.GetAccount(ctx, leg.FromAccountID)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					err = ErrAccountNotFound
+				}
+				return &BatchLegError{Index: i, Err: err}
+			}
+			owner = account.Owner
+			owners[leg.FromAccountID] = owner
+		}
+		if _, ok := firstLeg[owner]; !ok {
+			firstLeg[owner] = i
+		}
+	}
+
+	usernames := make([]string, 0, len(firstLeg))
+	for username := range firstLeg {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		_, err := q.GetUserForUpdate(ctx, username)
+		if err != nil {
+			return &BatchLegError{Index: firstLeg[username], Err: err}
+		}
+	}
+	return nil
+}
+
+func lockBatchAccounts(ctx context.Context, q *Queries, legs []BatchTransferLeg) (map[int64]Account, error) {
+	/** Locks every account touched by the batch in ascending ID order. */
+	firstLeg := map[int64]int{}
+	for i := len(legs) - 1; i >= 0; i-- {
+		firstLeg[legs[i].FromAccountID] = i
+		firstLeg[legs[i].ToAccountID] = i
+	}
+
+	ids := make([]int64, 0, len(firstLeg))
+	for id := range firstLeg {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	accounts := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = ErrAccountNotFound
+			}
+			return nil, &BatchLegError{Index: firstLeg[id], Err: err}
+		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
+
+func checkBatchFunds(ctx context.Context, q *Queries, arg BatchTransferTxParams, accounts map[int64]Account) error {
+	/** Replays the legs in order against the locked balances minus active holds,
+	failing on the first leg that would overdraw its source account. */
+	available := make(map[int64]int64, len(accounts))
+	for id, account := range accounts {
+		held, err := q.GetHeldAmount(ctx, id)
+		if err != nil {
+			return err
+		}
+		available[id] = account.Balance - held
+	}
+
+	for i, leg := range arg.Legs {
+		if accounts[leg.FromAccountID].Currency != arg.Currency ||
+			accounts[leg.ToAccountID].Currency != arg.Currency {
+			return &BatchLegError{Index: i, Err: ErrCurrencyMismatch}
+		}
+
+		available[leg.FromAccountID] -= leg.Amount
+		available[leg.ToAccountID] += leg.Amount
+		if available[leg.FromAccountID] < 0 {
+			return &BatchLegError{Index: i, Err: ErrInsufficientFunds}
+		}
+	}
+	return nil
+}
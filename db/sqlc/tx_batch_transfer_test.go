@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	employees := make([]Account, 3)
+	legs := make([]BatchTransferLeg, len(employees))
+	for i := range employees {
+		employees[i] = createTestAccount(t, 0, util.USD, util.CheckingAccount)
+		legs[i] = BatchTransferLeg{
+			FromAccountID: employer.ID,
+			ToAccountID:   employees[i].ID,
+			Amount:        int64(10 * (i + 1)),
+		}
+	}
+
+	result, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Currency: util.USD,
+		Legs:     legs,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Legs, len(legs))
+
+	balance := employer.Balance
+	for i, leg := range result.Legs {
+		balance -= legs[i].Amount
+		require.Equal(t, legs[i].Amount, leg.Transfer.Amount)
+		require.Equal(t, employees[i].ID, leg.Transfer.ToAccountID)
+		require.Equal(t, -legs[i].Amount, leg.FromEntry.Amount)
+		require.Equal(t, legs[i].Amount, leg.ToEntry.Amount)
+		require.Equal(t, balance, leg.FromAccount.Balance)
+		require.Equal(t, legs[i].Amount, leg.ToAccount.Balance)
+	}
+
+	updatedEmployer, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(40), updatedEmployer.Balance)
+}
+
+func TestBatchTransferTxFailingLeg(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	employee1 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	employee2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	foreign := createTestAccount(t, 0, util.EUR, util.CheckingAccount)
+
+	testCases := []struct {
+		name  string
+		legs  []BatchTransferLeg
+		index int
+		err   error
+	}{
+		{
+			name: "InvalidAmount",
+			legs: []BatchTransferLeg{
+				{employer.ID, employee1.ID, 10, ""},
+				{employer.ID, employee2.ID, 0, ""},
+			},
+			index: 1,
+			err:   ErrInvalidLeg,
+		},
+		{
+			name: "AccountNotFound",
+			legs: []BatchTransferLeg{
+				{employer.ID, employee1.ID, 10, ""},
+				{employer.ID, employee2.ID, 10, ""},
+				{employer.ID, 0, 10, ""},
+			},
+			index: 2,
+			err:   ErrAccountNotFound,
+		},
+		{
+			name: "CurrencyMismatch",
+			legs: []BatchTransferLeg{
+				{employer.ID, foreign.ID, 10, ""},
+			},
+			index: 0,
+			err:   ErrCurrencyMismatch,
+		},
+		{
+			name: "InsufficientFunds",
+			legs: []BatchTransferLeg{
+				{employer.ID, employee1.ID, 60, ""},
+				{employer.ID, employee2.ID, 50, ""},
+			},
+			index: 1,
+			err:   ErrInsufficientFunds,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+				Currency: util.USD,
+				Legs:     tc.legs,
+			})
+			require.ErrorIs(t, err, tc.err)
+
+			var legErr *BatchLegError
+			require.ErrorAs(t, err, &legErr)
+			require.Equal(t, tc.index, legErr.Index)
+		})
+	}
+
+	// Nothing moved
+	updatedEmployer, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, employer.Balance, updatedEmployer.Balance)
+
+	_, err = store.BatchTransferTx(context.Background(), BatchTransferTxParams{Currency: util.USD})
+	require.ErrorIs(t, err, ErrEmptyBatch)
+}
+
+func TestBatchTransferTxIncomingFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 50, util.USD, util.CheckingAccount)
+	account3 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+
+	// Account 1 can pass on money it receives earlier in the same batch
+	result, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Currency: util.USD,
+		Legs: []BatchTransferLeg{
+			{account2.ID, account1.ID, 50, ""},
+			{account1.ID, account3.ID, 50, ""},
+		},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Legs[1].FromAccount.Balance)
+	require.Equal(t, int64(50), result.Legs[1].ToAccount.Balance)
+}
+
+func TestBatchTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account3 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+
+	// Batches touching the same accounts in opposite orders must not deadlock
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		legs := []BatchTransferLeg{
+			{account1.ID, account2.ID, 10, ""},
+			{account2.ID, account3.ID, 10, ""},
+			{account3.ID, account1.ID, 10, ""},
+		}
+		if i%2 == 1 {
+			legs = []BatchTransferLeg{
+				{account3.ID, account2.ID, 10, ""},
+				{account2.ID, account1.ID, 10, ""},
+				{account1.ID, account3.ID, 10, ""},
+			}
+		}
+
+		go func() {
+			_, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+				Currency: util.USD,
+				Legs:     legs,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	for _, account := range []Account{account1, account2, account3} {
+		updated, err := testQueries.GetAccount(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account.Balance, updated.Balance)
+	}
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+var (
+	ErrNoInterestToAccrue = errors.New("no account is waiting for interest")
+	ErrNoInterestToPost   = errors.New("no interest is waiting to be posted")
+	ErrDayNotOver         = errors.New("interest can only be accrued for days that are over")
+)
+
+type AccrueInterestTxParams struct {
+	// Day (UTC) the interest is earned on, the time of day is ignored
+	Date time.Time `json:"date"`
+	// Only accounts with a greater ID are considered, to move past one that fails
+	AfterAccountID int64 `json:"after_account_id"`
+}
+
+func (store *SQLStore) AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (InterestAccrual, error) {
+	/** Accrues one day of interest for the next account that has an interest rate and
+	no accrual for that day yet, skipping accounts other workers have locked.
+	Interest is earned on the balance at the end of the day and stored as an exact
+	fraction, so accruing the same day again is a no-op. Returns ErrNoInterestToAccrue
+	when every account is done, and ErrDayNotOver for today or a later day, whose
+	closing balance is not known yet. When the account's accrual fails, the result
+	still holds its AccountID so the caller can skip it. */
+	var result InterestAccrual
+
+	transaction := func(q *Queries) error {
+		day := time.Date(arg.Date.Year(), arg.Date.Month(), arg.Date.Day(), 0, 0, 0, 0, time.UTC)
+		dayEnd := day.AddDate(0, 0, 1)
+		if dayEnd.After(time.Now()) {
+			return ErrDayNotOver
+		}
+
+		account, err := q.GetNextAccountToAccrue(ctx, GetNextAccountToAccrueParams{
+			AfterID:     arg.AfterAccountID,
+			DayEnd:      dayEnd,
+			AccrualDate: day,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoInterestToAccrue
+			}
+			return err
+		}
+		result.AccountID = account.ID
+
+		rate, err := q.GetInterestRate(ctx, GetInterestRateParams{
+			Currency:    account.Currency,
+			AccountType: account.Type,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Undo whatever happened after the day ended to get its closing balance
+		later, err := q.GetEntriesTotalSince(ctx, GetEntriesTotalSinceParams{
+			AccountID: account.ID,
+			CreatedAt: dayEnd,
+		})
+		if err != nil {
+			return err
+		}
+		balance := account.Balance - later
+
+		annualRate, err := util.ParseInterestRate(rate.AnnualRate)
+		if err != nil {
+			return err
+		}
+
+		interest, err := util.DailyInterest(balance, annualRate, rate.DayCount, day)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:           account.ID,
+			AccrualDate:         day,
+			Balance:             balance,
+			AnnualRate:          rate.AnnualRate,
+			DayCount:            rate.DayCount,
+			InterestNumerator:   interest.Num().String(),
+			InterestDenominator: interest.Denom().String(),
+		})
+		return err
+	}
+	err := store.execTx(ctx, "AccrueInterestTx", transaction)
+	if err != nil {
+		result = InterestAccrual{AccountID: result.AccountID}
+	}
+	return result, err
+}
+
+type PostInterestTxParams struct {
+	// Accruals for days before this date (UTC) are paid out
+	Before time.Time `json:"before"`
+	// Only accounts with a greater ID are considered, to move past one that fails
+	AfterAccountID int64 `json:"after_account_id"`
+}
+
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (InterestPosting, error) {
+	/** Pays the accrued interest of the next account with unpaid accruals before arg.Before
+	from the bank's interest expense account. Only whole minor units are paid, the fraction
+	left over is carried into the account's next posting. Returns ErrNoInterestToPost when
+	every account is done. When the posting fails, the result still holds its AccountID
+	so the caller can skip it. */
+	var result InterestPosting
+
+	transaction := func(q *Queries) error {
+		before := time.Date(arg.Before.Year(), arg.Before.Month(), arg.Before.Day(), 0, 0, 0, 0, time.UTC)
+
+		account, err := q.GetNextAccountToPostInterest(ctx, GetNextAccountToPostInterestParams{
+			AfterID: arg.AfterAccountID,
+			Before:  before,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoInterestToPost
+			}
+			return err
+		}
+		result.AccountID = account.ID
+
+		result, err = postInterest(ctx, q, account, before)
+		return err
+	}
+	err := store.execTx(ctx, "PostInterestTx", transaction)
+	if err != nil {
+		result = InterestPosting{AccountID: result.AccountID}
+	}
+	return result, err
+}
+
+func postInterest(ctx context.Context, q *Queries, account Account, before time.Time) (InterestPosting, error) {
+	/** Pays the account's accruals for days before the given date. The account must be locked. */
+	var result InterestPosting
+
+	rate, err := q.GetInterestRate(ctx, GetInterestRateParams{
+		Currency:    account.Currency,
+		AccountType: account.Type,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	accrued, err := lastInterestRemainder(ctx, q, account.ID)
+	if err != nil {
+		return result, err
+	}
+
+	accruals, err := q.ListUnpostedInterestAccruals(ctx, ListUnpostedInterestAccrualsParams{
+		AccountID: account.ID,
+		Before:    before,
+	})
+	if err != nil {
+		return result, err
+	}
+	for _, accrual := range accruals {
+		interest, err := parseFraction(accrual.InterestNumerator, accrual.InterestDenominator)
+		if err != nil {
+			return result, err
+		}
+		accrued.Add(accrued, interest)
+	}
+
+	amount, remainder := util.SplitInterest(accrued)
+	posting := CreateInterestPostingParams{
+		AccountID:            account.ID,
+		Amount:               amount,
+		RemainderNumerator:   remainder.Num().String(),
+		RemainderDenominator: remainder.Denom().String(),
+	}
+
+	if amount > 0 {
+		posting.EntryID, posting.ExpenseEntryID, err = payInterest(ctx, q, account.ID, rate.ExpenseAccountID, amount)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result, err = q.CreateInterestPosting(ctx, posting)
+	if err != nil {
+		return result, err
+	}
+
+	err = q.SetInterestAccrualsPosting(ctx, SetInterestAccrualsPostingParams{
+		PostingID: sql.NullInt64{
+			Int64: result.ID,
+			Valid: true,
+		},
+		AccountID: account.ID,
+		Before:    before,
+	})
+	return result, err
+}
+
+func settleInterest(ctx context.Context, q *Queries, account Account) (Account, error) {
+	/** Pays every accrual of a locked account that is about to close, instead of waiting
+	for the monthly posting, which skips closed accounts. Returns the updated account. */
+	before := startOfDay(time.Now()).AddDate(0, 0, 1)
+	accruals, err := q.ListUnpostedInterestAccruals(ctx, ListUnpostedInterestAccrualsParams{
+		AccountID: account.ID,
+		Before:    before,
+	})
+	if err != nil || len(accruals) == 0 {
+		return account, err
+	}
+
+	posting, err := postInterest(ctx, q, account, before)
+	if err != nil || posting.Amount == 0 {
+		return account, err
+	}
+	return q.GetAccount(ctx, account.ID)
+}
+
+func lastInterestRemainder(ctx context.Context, q *Queries, accountID int64) (*big.Rat, error) {
+	/** Returns the fraction of a minor unit the previous posting could not pay. */
+	posting, err := q.GetLastInterestPosting(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(big.Rat), nil
+		}
+		return nil, err
+	}
+	return parseFraction(posting.RemainderNumerator, posting.RemainderDenominator)
+}
+
+func payInterest(ctx context.Context, q *Queries, accountID, expenseAccountID, amount int64) (entryID, expenseEntryID sql.NullInt64, err error) {
+	expenseEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: expenseAccountID,
+		Amount:    -amount,
+	})
+	if err != nil {
+		return
+	}
+
+	entry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: accountID,
+		Amount:    amount,
+	})
+	if err != nil {
+		return
+	}
+
+	_, err = addBalances(ctx, q, map[int64]int64{
+		accountID:        amount,
+		expenseAccountID: -amount,
+	})
+	if err != nil {
+		return
+	}
+
+	entryID = sql.NullInt64{Int64: entry.ID, Valid: true}
+	expenseEntryID = sql.NullInt64{Int64: expenseEntry.ID, Valid: true}
+	return
+}
+
+func parseFraction(numerator, denominator string) (*big.Rat, error) {
+	fraction, ok := new(big.Rat).SetString(numerator + "/" + denominator)
+	if !ok {
+		return nil, fmt.Errorf("invalid fraction %s/%s", numerator, denominator)
+	}
+	return fraction, nil
+}
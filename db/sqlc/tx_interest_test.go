@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func accrueAll(t *testing.T, store Store, day time.Time) {
+	for {
+		_, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{Date: day})
+		if err == ErrNoInterestToAccrue {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func postAll(t *testing.T, store Store, before time.Time) {
+	for {
+		_, err := store.PostInterestTx(context.Background(), PostInterestTxParams{Before: before})
+		if err == ErrNoInterestToPost {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func setInterestRate(t *testing.T, currency, accountType, annualRate string) {
+	expense := createTestAccount(t, 0, currency, util.ExpenseAccount)
+	_, err := testQueries.UpsertInterestRate(context.Background(), UpsertInterestRateParams{
+		Currency:         currency,
+		AccountType:      accountType,
+		AnnualRate:       annualRate,
+		DayCount:         util.DayCountActual365,
+		ExpenseAccountID: expense.ID,
+	})
+	require.NoError(t, err)
+
+	// Rates apply to every account of the type, so don't leak them into other tests
+	t.Cleanup(func() {
+		err := testQueries.DeleteInterestRate(context.Background(), DeleteInterestRateParams{
+			Currency:    currency,
+			AccountType: accountType,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func createInterestAccount(t *testing.T, balance int64, accountType string, openedAt time.Time) Account {
+	account := createTestAccount(t, balance, util.USD, accountType)
+	_, err := testDB.Exec("UPDATE accounts SET created_at = $1 WHERE id = $2", openedAt, account.ID)
+	require.NoError(t, err)
+	return account
+}
+
+func TestAccrueInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	setInterestRate(t, util.USD, util.SavingsAccount, "0.02")
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	// Opened at the start of yesterday, so the day before it did not exist yet
+	account := createInterestAccount(t, 100000, util.SavingsAccount, yesterday)
+	checking := createInterestAccount(t, 100000, util.CheckingAccount, yesterday)
+
+	accrueAll(t, store, yesterday.AddDate(0, 0, -1))
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Empty(t, accruals)
+
+	accrueAll(t, store, yesterday)
+	accruals, err = testQueries.ListInterestAccruals(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+
+	accrual := accruals[0]
+	require.Equal(t, int64(100000), accrual.Balance)
+	require.Equal(t, util.DayCountActual365, accrual.DayCount)
+	require.Equal(t, "400", accrual.InterestNumerator)
+	require.Equal(t, "73", accrual.InterestDenominator)
+	require.False(t, accrual.PostingID.Valid)
+
+	// Accruing the same day again changes nothing
+	_, err = store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{Date: yesterday})
+	require.ErrorIs(t, err, ErrNoInterestToAccrue)
+
+	// Today's closing balance is not known yet
+	_, err = store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{Date: today})
+	require.ErrorIs(t, err, ErrDayNotOver)
+
+	// Checking accounts have no interest rate
+	accruals, err = testQueries.ListInterestAccruals(context.Background(), checking.ID)
+	require.NoError(t, err)
+	require.Empty(t, accruals)
+}
+
+func TestPostInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	setInterestRate(t, util.USD, util.SavingsAccount, "0.02")
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	account := createInterestAccount(t, 100000, util.SavingsAccount, yesterday)
+	accrueAll(t, store, yesterday)
+
+	// 400/73 is paid as 5 with 35/73 carried over
+	postAll(t, store, today)
+
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100005), updated.Balance)
+
+	posting, err := testQueries.GetLastInterestPosting(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), posting.Amount)
+	require.Equal(t, "35", posting.RemainderNumerator)
+	require.Equal(t, "73", posting.RemainderDenominator)
+	require.True(t, posting.EntryID.Valid)
+	require.True(t, posting.ExpenseEntryID.Valid)
+
+	entry, err := testQueries.GetEntry(context.Background(), posting.EntryID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, entry.AccountID)
+	require.Equal(t, int64(5), entry.Amount)
+
+	expenseEntry, err := testQueries.GetEntry(context.Background(), posting.ExpenseEntryID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, int64(-5), expenseEntry.Amount)
+
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	require.Equal(t, posting.ID, accruals[0].PostingID.Int64)
+
+	// Posted accruals are not paid twice
+	postAll(t, store, today)
+	updated, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100005), updated.Balance)
+}
@@ -0,0 +1,502 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+const (
+	PayrollJobStatusPending    = "pending"
+	PayrollJobStatusValidating = "validating"
+	PayrollJobStatusInvalid    = "invalid"
+	PayrollJobStatusProcessing = "processing"
+	PayrollJobStatusCompleted  = "completed"
+	PayrollJobStatusFailed     = "failed"
+
+	PayrollRowStatusPending = "pending"
+	PayrollRowStatusValid   = "valid"
+	PayrollRowStatusInvalid = "invalid"
+	PayrollRowStatusPaid    = "paid"
+	PayrollRowStatusFailed  = "failed"
+)
+
+// payrollHoldTTL is how long the funds of a validated job stay reserved. The worker
+// pays a job within minutes, so the hold only runs out for a job stuck far longer
+// than any retry would take; paying then still checks the available funds.
+const payrollHoldTTL = 30 * 24 * time.Hour
+
+var ErrNoPayrollJobPending = errors.New("no payroll job is pending")
+
+// PayrollRowError tells which row of a payroll file made a chunk fail.
+type PayrollRowError struct {
+	RowNumber int32
+	Err       error
+}
+
+func (e *PayrollRowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.RowNumber, e.Err)
+}
+
+func (e *PayrollRowError) Unwrap() error {
+	return e.Err
+}
+
+type PayrollJobRowParams struct {
+	RowNumber int32 `json:"row_number"`
+	// Zero when the recipient is given by username
+	ToAccountID int64  `json:"to_account_id"`
+	Username    string `json:"username"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Memo        string `json:"memo"`
+}
+
+type CreatePayrollJobTxParams struct {
+	Owner         string                `json:"owner"`
+	FromAccountID int64                 `json:"from_account_id"`
+	Currency      string                `json:"currency"`
+	Rows          []PayrollJobRowParams `json:"rows"`
+}
+
+func (store *SQLStore) CreatePayrollJobTx(ctx context.Context, arg CreatePayrollJobTxParams) (PayrollJob, error) {
+	/** Stores an uploaded payroll file as a pending job. No money moves until a worker processes it. */
+	var result PayrollJob
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.CreatePayrollJob(ctx, CreatePayrollJobParams{
+			Owner:         arg.Owner,
+			FromAccountID: arg.FromAccountID,
+			Currency:      arg.Currency,
+			TotalRows:     int32(len(arg.Rows)),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range arg.Rows {
+			_, err = q.CreatePayrollJobRow(ctx, CreatePayrollJobRowParams{
+				JobID:     result.ID,
+				RowNumber: row.RowNumber,
+				ToAccountID: sql.NullInt64{
+					Int64: row.ToAccountID,
+					Valid: row.ToAccountID != 0,
+				},
+				Username: sql.NullString{
+					String: row.Username,
+					Valid:  row.Username != "",
+				},
+				Amount:   row.Amount,
+				Currency: row.Currency,
+				Memo:     row.Memo,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err := store.execTx(ctx, "CreatePayrollJobTx", transaction)
+	return result, err
+}
+
+type ProcessPayrollJobTxParams struct {
+	ChunkSize int32 `json:"chunk_size"`
+}
+
+func (store *SQLStore) ProcessPayrollJobTx(ctx context.Context, arg ProcessPayrollJobTxParams) (PayrollJob, error) {
+	/** Advances the oldest unfinished payroll job by one chunk of rows, skipping jobs
+	other workers are busy with. A job first validates every row; only when all rows are
+	valid and the source account can cover the total does it reserve the total with a hold
+	and start paying, one all-or-nothing batch per chunk drawn from that hold.
+	Returns ErrNoPayrollJobPending when there is nothing to do, and the job is returned
+	even on failure so the caller can record it. A *PayrollRowError names a row that cannot
+	be paid; any other error leaves the job as it was, to be retried. */
+	var result PayrollJob
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.GetNextPayrollJobForUpdate(ctx)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoPayrollJobPending
+			}
+			return err
+		}
+
+		switch result.Status {
+		case PayrollJobStatusPending, PayrollJobStatusValidating:
+			result, err = validatePayrollChunk(ctx, q, result, arg.ChunkSize)
+		case PayrollJobStatusProcessing:
+			result, err = payPayrollChunk(ctx, q, result, arg.ChunkSize)
+		}
+		return err
+	}
+	err := store.execTx(ctx, "ProcessPayrollJobTx", transaction)
+	return result, err
+}
+
+func validatePayrollChunk(ctx context.Context, q *Queries, job PayrollJob, chunkSize int32) (PayrollJob, error) {
+	rows, err := q.ListPayrollJobRowsByStatus(ctx, ListPayrollJobRowsByStatusParams{
+		JobID:  job.ID,
+		Status: PayrollRowStatusPending,
+		Limit:  chunkSize,
+	})
+	if err != nil {
+		return job, err
+	}
+
+	for _, row := range rows {
+		toAccount, rowErr := resolvePayrollRecipient(ctx, q, job, row)
+		if rowErr != nil && !isPayrollRowProblem(rowErr) {
+			return job, rowErr
+		}
+
+		arg := ValidatePayrollJobRowParams{
+			ID:     row.ID,
+			Status: PayrollRowStatusValid,
+			ToAccountID: sql.NullInt64{
+				Int64: toAccount.ID,
+				Valid: rowErr == nil,
+			},
+		}
+		if rowErr != nil {
+			arg.Status = PayrollRowStatusInvalid
+			arg.Error = sql.NullString{
+				String: rowErr.Error(),
+				Valid:  true,
+			}
+		}
+
+		_, err = q.ValidatePayrollJobRow(ctx, arg)
+		if err != nil {
+			return job, err
+		}
+	}
+
+	if int32(len(rows)) == chunkSize {
+		return q.UpdatePayrollJobStatus(ctx, UpdatePayrollJobStatusParams{
+			ID:     job.ID,
+			Status: PayrollJobStatusValidating,
+		})
+	}
+	return finishPayrollValidation(ctx, q, job)
+}
+
+// payrollRowProblem is a validation failure caused by the row itself rather than by the database
+type payrollRowProblem struct {
+	msg string
+}
+
+func (e payrollRowProblem) Error() string {
+	return e.msg
+}
+
+func isPayrollRowProblem(err error) bool {
+	var problem payrollRowProblem
+	return errors.As(err, &problem)
+}
+
+func resolvePayrollRecipient(ctx context.Context, q *Queries, job PayrollJob, row PayrollJobRow) (Account, error) {
+	/** Finds the account a row pays into and checks it can receive the job's currency. */
+	if row.Currency != job.Currency {
+		return Account{}, payrollRowProblem{fmt.Sprintf("currency: must be %s like the source account", job.Currency)}
+	}
+
+	var account Account
+	var err error
+	if row.ToAccountID.Valid {
+		account, err = q.GetAccount(ctx, row.ToAccountID.Int64)
+		if err == sql.ErrNoRows {
+			return account, payrollRowProblem{fmt.Sprintf("to_account_id: account %d not found", row.ToAccountID.Int64)}
+		}
+	} else {
+		account, err = q.GetAccountForOwnerAndCurrency(ctx, GetAccountForOwnerAndCurrencyParams{
+			Owner:    row.Username.String,
+			Currency: job.Currency,
+			Type:     util.CheckingAccount,
+		})
+		if err == sql.ErrNoRows {
+			return account, payrollRowProblem{fmt.Sprintf("username: %s has no %s checking account", row.Username.String, job.Currency)}
+		}
+	}
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != job.Currency {
+		return account, payrollRowProblem{fmt.Sprintf("to_account_id: account %d holds %s, not %s", account.ID, account.Currency, job.Currency)}
+	}
+	if account.ID == job.FromAccountID {
+		return account, payrollRowProblem{"to_account_id: cannot pay the source account"}
+	}
+	return account, nil
+}
+
+func finishPayrollValidation(ctx context.Context, q *Queries, job PayrollJob) (PayrollJob, error) {
+	/** Decides whether a fully validated job may start paying, and reserves its total
+	so that nothing else can spend the money between chunks. */
+	invalid, err := q.CountPayrollJobRowsByStatus(ctx, CountPayrollJobRowsByStatusParams{
+		JobID:  job.ID,
+		Status: PayrollRowStatusInvalid,
+	})
+	if err != nil {
+		return job, err
+	}
+	if invalid > 0 {
+		return failPayrollJob(ctx, q, job, PayrollJobStatusInvalid, fmt.Sprintf("%d rows are invalid", invalid))
+	}
+
+	total, err := q.GetPayrollJobTotalAmount(ctx, job.ID)
+	if err != nil {
+		return job, err
+	}
+
+	// Locked like AuthorizeTransferTx does, so concurrent holds cannot both fit
+	fromAccount, err := q.GetAccountForUpdate(ctx, job.FromAccountID)
+	if err != nil {
+		return job, err
+	}
+	if err := checkDebit(fromAccount); err != nil {
+		return failPayrollJob(ctx, q, job, PayrollJobStatusInvalid, err.Error())
+	}
+
+	held, err := q.GetHeldAmount(ctx, job.FromAccountID)
+	if err != nil {
+		return job, err
+	}
+
+	if available := fromAccount.Balance - held; available < total {
+		msg := fmt.Sprintf("%s: the job needs %d but only %d is available", ErrInsufficientFunds, total, available)
+		return failPayrollJob(ctx, q, job, PayrollJobStatusInvalid, msg)
+	}
+
+	var holdID sql.NullInt64
+	if total > 0 {
+		hold, err := q.CreateAccountHold(ctx, CreateAccountHoldParams{
+			FromAccountID: job.FromAccountID,
+			Amount:        total,
+			ExpiresAt:     time.Now().Add(payrollHoldTTL),
+		})
+		if err != nil {
+			return job, err
+		}
+		holdID = sql.NullInt64{
+			Int64: hold.ID,
+			Valid: true,
+		}
+	}
+
+	return q.StartPayrollJobPayment(ctx, StartPayrollJobPaymentParams{
+		ID:     job.ID,
+		HoldID: holdID,
+	})
+}
+
+func payPayrollChunk(ctx context.Context, q *Queries, job PayrollJob, chunkSize int32) (PayrollJob, error) {
+	rows, err := q.ListPayrollJobRowsByStatus(ctx, ListPayrollJobRowsByStatusParams{
+		JobID:  job.ID,
+		Status: PayrollRowStatusValid,
+		Limit:  chunkSize,
+	})
+	if err != nil {
+		return job, err
+	}
+
+	if len(rows) > 0 {
+		fromAccount, err := q.GetAccount(ctx, job.FromAccountID)
+		if err != nil {
+			return job, err
+		}
+		// No row can be paid from a frozen or closed account, so give the money back
+		if debitErr := checkDebit(fromAccount); debitErr != nil {
+			err = releasePayrollFunds(ctx, q, job, -1, HoldStatusVoided)
+			if err != nil {
+				return job, err
+			}
+			return failPayrollJob(ctx, q, job, PayrollJobStatusFailed, debitErr.Error())
+		}
+
+		var amount int64
+		legs := make([]BatchTransferLeg, len(rows))
+		for i, row := range rows {
+			amount += row.Amount
+			legs[i] = BatchTransferLeg{
+				FromAccountID: job.FromAccountID,
+				ToAccountID:   row.ToAccountID.Int64,
+				Amount:        row.Amount,
+				Memo:          row.Memo,
+			}
+		}
+
+		// Taken off the hold first, the transfers' funds check must not count it
+		err = releasePayrollFunds(ctx, q, job, amount, HoldStatusCaptured)
+		if err != nil {
+			return job, err
+		}
+
+		batch, err := batchTransfer(ctx, q, BatchTransferTxParams{
+			Currency: job.Currency,
+			Legs:     legs,
+		})
+		if err != nil {
+			var legErr *BatchLegError
+			if errors.As(err, &legErr) && isPayrollRowFailure(legErr.Err) {
+				return job, &PayrollRowError{RowNumber: rows[legErr.Index].RowNumber, Err: legErr.Err}
+			}
+			return job, err
+		}
+
+		for i, row := range rows {
+			_, err = q.PayPayrollJobRow(ctx, PayPayrollJobRowParams{
+				ID: row.ID,
+				TransferID: sql.NullInt64{
+					Int64: batch.Legs[i].Transfer.ID,
+					Valid: true,
+				},
+			})
+			if err != nil {
+				return job, err
+			}
+		}
+
+		job, err = q.AddPayrollJobProcessedRows(ctx, AddPayrollJobProcessedRowsParams{
+			ID:   job.ID,
+			Rows: int32(len(rows)),
+		})
+		if err != nil {
+			return job, err
+		}
+	}
+
+	if int32(len(rows)) == chunkSize {
+		return job, nil
+	}
+
+	failed, err := q.CountPayrollJobRowsByStatus(ctx, CountPayrollJobRowsByStatusParams{
+		JobID:  job.ID,
+		Status: PayrollRowStatusFailed,
+	})
+	if err != nil {
+		return job, err
+	}
+
+	arg := UpdatePayrollJobStatusParams{
+		ID:     job.ID,
+		Status: PayrollJobStatusCompleted,
+	}
+	if failed > 0 {
+		arg.Error = sql.NullString{
+			String: fmt.Sprintf("%d rows could not be paid", failed),
+			Valid:  true,
+		}
+	}
+	return q.UpdatePayrollJobStatus(ctx, arg)
+}
+
+func isPayrollRowFailure(err error) bool {
+	/** The row itself cannot be paid, as opposed to a database error worth retrying. */
+	return IsTransferRejection(err) || errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrInvalidLeg)
+}
+
+func releasePayrollFunds(ctx context.Context, q *Queries, job PayrollJob, amount int64, status string) error {
+	/** Takes amount off the job's hold, or all of it when amount is negative. The hold is
+	settled with status once nothing is left on it. Jobs that reserved nothing, or whose
+	hold has run out, have nothing to release. */
+	if !job.HoldID.Valid {
+		return nil
+	}
+
+	hold, err := q.GetAccountHoldForUpdate(ctx, job.HoldID.Int64)
+	if err != nil {
+		return err
+	}
+	if hold.Status != HoldStatusActive {
+		return nil
+	}
+
+	if amount < 0 || amount >= hold.Amount {
+		_, err = q.ReleaseAccountHold(ctx, ReleaseAccountHoldParams{
+			ID:     hold.ID,
+			Status: status,
+		})
+		return err
+	}
+
+	_, err = q.ReduceAccountHold(ctx, ReduceAccountHoldParams{
+		ID:     hold.ID,
+		Amount: amount,
+	})
+	return err
+}
+
+type FailPayrollJobTxParams struct {
+	JobID int64 `json:"job_id"`
+	// Zero when the failure is not caused by a single row
+	RowNumber int32  `json:"row_number"`
+	Error     string `json:"error"`
+}
+
+func (store *SQLStore) FailPayrollJobTx(ctx context.Context, arg FailPayrollJobTxParams) (PayrollJob, error) {
+	/** Records why a job could not go on. A failed row is skipped and its share of the
+	hold is released, so the job resumes with the other rows on the next pass. Without
+	a row the whole job fails and whatever is still reserved goes back to the account.
+	Rows that were already paid stay paid either way. */
+	var result PayrollJob
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.GetPayrollJobForUpdate(ctx, arg.JobID)
+		if err != nil {
+			return err
+		}
+
+		switch result.Status {
+		case PayrollJobStatusInvalid, PayrollJobStatusCompleted, PayrollJobStatusFailed:
+			// Already finished, possibly by another worker
+			return nil
+		}
+
+		if arg.RowNumber > 0 {
+			row, err := q.FailPayrollJobRow(ctx, FailPayrollJobRowParams{
+				JobID:     arg.JobID,
+				RowNumber: arg.RowNumber,
+				Error:     arg.Error,
+			})
+			if err == sql.ErrNoRows {
+				// Already recorded
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return releasePayrollFunds(ctx, q, result, row.Amount, HoldStatusVoided)
+		}
+
+		err = releasePayrollFunds(ctx, q, result, -1, HoldStatusVoided)
+		if err != nil {
+			return err
+		}
+
+		result, err = failPayrollJob(ctx, q, result, PayrollJobStatusFailed, arg.Error)
+		return err
+	}
+	err := store.execTx(ctx, "FailPayrollJobTx", transaction)
+	return result, err
+}
+
+func failPayrollJob(ctx context.Context, q *Queries, job PayrollJob, status string, msg string) (PayrollJob, error) {
+	return q.UpdatePayrollJobStatus(ctx, UpdatePayrollJobStatusParams{
+		ID:     job.ID,
+		Status: status,
+		Error: sql.NullString{
+			String: msg,
+			Valid:  true,
+		},
+	})
+}
@@ -0,0 +1,312 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func processPayrollJobs(t *testing.T, store Store, chunkSize int32) {
+	// Other tests may leave unfinished jobs behind, so drain everything
+	for {
+		job, err := store.ProcessPayrollJobTx(context.Background(), ProcessPayrollJobTxParams{
+			ChunkSize: chunkSize,
+		})
+		if err == ErrNoPayrollJobPending {
+			return
+		}
+		if err != nil {
+			// Anything but a row that cannot be paid would be retried by the worker
+			var rowErr *PayrollRowError
+			require.ErrorAs(t, err, &rowErr)
+
+			_, err = store.FailPayrollJobTx(context.Background(), FailPayrollJobTxParams{
+				JobID:     job.ID,
+				RowNumber: rowErr.RowNumber,
+				Error:     rowErr.Err.Error(),
+			})
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestPayrollJob(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+
+	var rows []PayrollJobRowParams
+	var employees []Account
+	for i := 0; i < 5; i++ {
+		employee := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+		employees = append(employees, employee)
+
+		row := PayrollJobRowParams{
+			RowNumber: int32(i + 2),
+			Amount:    100,
+			Currency:  util.USD,
+			Memo:      "salary",
+		}
+		// Mix both ways of naming the recipient
+		if i%2 == 0 {
+			row.ToAccountID = employee.ID
+		} else {
+			row.Username = employee.Owner
+		}
+		rows = append(rows, row)
+	}
+
+	job, err := store.CreatePayrollJobTx(context.Background(), CreatePayrollJobTxParams{
+		Owner:         employer.Owner,
+		FromAccountID: employer.ID,
+		Currency:      util.USD,
+		Rows:          rows,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusPending, job.Status)
+	require.Equal(t, int32(5), job.TotalRows)
+
+	// A chunk size of 2 takes several passes through validation and payment
+	processPayrollJobs(t, store, 2)
+
+	job, err = testQueries.GetPayrollJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusCompleted, job.Status)
+	require.Equal(t, int32(5), job.ProcessedRows)
+	require.False(t, job.Error.Valid)
+
+	for _, employee := range employees {
+		account, err := testQueries.GetAccount(context.Background(), employee.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), account.Balance)
+	}
+
+	account, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), account.Balance)
+
+	paid, err := testQueries.ListPayrollJobRowsByStatus(context.Background(), ListPayrollJobRowsByStatusParams{
+		JobID:  job.ID,
+		Status: PayrollRowStatusPaid,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, paid, 5)
+
+	// Each row's memo ends up on its transfer
+	for _, row := range paid {
+		transfer, err := testQueries.GetTransfer(context.Background(), row.TransferID.Int64)
+		require.NoError(t, err)
+		require.Equal(t, "salary", transfer.Memo.String)
+	}
+
+	// The reservation was used up
+	require.True(t, job.HoldID.Valid)
+	hold, err := testQueries.GetAccountHold(context.Background(), job.HoldID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, hold.Status)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+}
+
+func TestPayrollJobInvalidRows(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	employee := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	foreign := createTestAccount(t, 0, util.EUR, util.CheckingAccount)
+
+	job, err := store.CreatePayrollJobTx(context.Background(), CreatePayrollJobTxParams{
+		Owner:         employer.Owner,
+		FromAccountID: employer.ID,
+		Currency:      util.USD,
+		Rows: []PayrollJobRowParams{
+			{RowNumber: 2, ToAccountID: employee.ID, Amount: 100, Currency: util.USD},
+			{RowNumber: 3, ToAccountID: foreign.ID, Amount: 100, Currency: util.USD},
+			{RowNumber: 4, Username: util.RandomOwner(), Amount: 100, Currency: util.USD},
+			{RowNumber: 5, ToAccountID: employee.ID, Amount: 100, Currency: util.EUR},
+		},
+	})
+	require.NoError(t, err)
+
+	processPayrollJobs(t, store, 10)
+
+	job, err = testQueries.GetPayrollJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusInvalid, job.Status)
+	require.Equal(t, "3 rows are invalid", job.Error.String)
+	require.Zero(t, job.ProcessedRows)
+
+	rowErrors, err := testQueries.ListPayrollJobRowErrors(context.Background(), ListPayrollJobRowErrorsParams{
+		JobID: job.ID,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rowErrors, 3)
+	require.Equal(t, []int32{3, 4, 5}, []int32{rowErrors[0].RowNumber, rowErrors[1].RowNumber, rowErrors[2].RowNumber})
+	require.Contains(t, rowErrors[0].Error.String, "to_account_id")
+	require.Contains(t, rowErrors[1].Error.String, "username")
+	require.Contains(t, rowErrors[2].Error.String, "currency")
+
+	// Nothing moved
+	account, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, employer.Balance, account.Balance)
+}
+
+func TestPayrollJobInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 150, util.USD, util.CheckingAccount)
+	employee1 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	employee2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+
+	job, err := store.CreatePayrollJobTx(context.Background(), CreatePayrollJobTxParams{
+		Owner:         employer.Owner,
+		FromAccountID: employer.ID,
+		Currency:      util.USD,
+		Rows: []PayrollJobRowParams{
+			{RowNumber: 2, ToAccountID: employee1.ID, Amount: 100, Currency: util.USD},
+			{RowNumber: 3, ToAccountID: employee2.ID, Amount: 100, Currency: util.USD},
+		},
+	})
+	require.NoError(t, err)
+
+	processPayrollJobs(t, store, 10)
+
+	// The total is checked before anything is paid
+	job, err = testQueries.GetPayrollJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusInvalid, job.Status)
+	require.Contains(t, job.Error.String, ErrInsufficientFunds.Error())
+
+	account, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, employer.Balance, account.Balance)
+}
+
+func createTestPayrollJob(t *testing.T, store Store, employer Account, employees []Account, amount int64) PayrollJob {
+	rows := make([]PayrollJobRowParams, len(employees))
+	for i, employee := range employees {
+		rows[i] = PayrollJobRowParams{
+			RowNumber:   int32(i + 2),
+			ToAccountID: employee.ID,
+			Amount:      amount,
+			Currency:    employer.Currency,
+		}
+	}
+
+	job, err := store.CreatePayrollJobTx(context.Background(), CreatePayrollJobTxParams{
+		Owner:         employer.Owner,
+		FromAccountID: employer.ID,
+		Currency:      employer.Currency,
+		Rows:          rows,
+	})
+	require.NoError(t, err)
+	return job
+}
+
+func payFirstPayrollChunk(t *testing.T, store Store, job PayrollJob, chunkSize int32) PayrollJob {
+	// Validate everything and pay the first chunk, finishing jobs left behind by other tests
+	for {
+		processed, err := store.ProcessPayrollJobTx(context.Background(), ProcessPayrollJobTxParams{ChunkSize: chunkSize})
+		require.NoError(t, err)
+		if processed.ID == job.ID && processed.ProcessedRows > 0 {
+			return processed
+		}
+	}
+}
+
+func TestPayrollJobReservesFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 300, util.USD, util.CheckingAccount)
+	employees := make([]Account, 3)
+	for i := range employees {
+		employees[i] = createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	}
+	job := createTestPayrollJob(t, store, employer, employees, 100)
+
+	job = payFirstPayrollChunk(t, store, job, 2)
+	require.Equal(t, PayrollJobStatusProcessing, job.Status)
+	require.Equal(t, int32(2), job.ProcessedRows)
+
+	// Only the unpaid row is still reserved, and nothing else may spend it
+	held, err := testQueries.GetHeldAmount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), held)
+
+	_, err = store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: employer.ID,
+		ToAccountID:   employees[0].ID,
+		Amount:        1,
+		TTL:           time.Minute,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// Nor can it be captured or voided like a customer hold
+	_, err = store.VoidTransferTx(context.Background(), VoidTransferTxParams{HoldID: job.HoldID.Int64})
+	require.ErrorIs(t, err, ErrPayrollHold)
+
+	processPayrollJobs(t, store, 2)
+
+	job, err = testQueries.GetPayrollJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusCompleted, job.Status)
+	require.Equal(t, int32(3), job.ProcessedRows)
+
+	account, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.Balance)
+}
+
+func TestPayrollJobFailedRow(t *testing.T) {
+	store := NewStore(testDB)
+
+	employer := createTestAccount(t, 300, util.USD, util.CheckingAccount)
+	employees := make([]Account, 3)
+	for i := range employees {
+		employees[i] = createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	}
+	job := createTestPayrollJob(t, store, employer, employees, 100)
+
+	job = payFirstPayrollChunk(t, store, job, 2)
+	require.Equal(t, int32(2), job.ProcessedRows)
+
+	// The last employee's account stops taking money before the last chunk runs
+	_, err := changeAccountStatus(store, employees[2], AccountStatusFrozen, true, 0)
+	require.NoError(t, err)
+
+	processPayrollJobs(t, store, 2)
+
+	// The job goes on without the row instead of stopping halfway
+	job, err = testQueries.GetPayrollJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayrollJobStatusCompleted, job.Status)
+	require.Equal(t, int32(2), job.ProcessedRows)
+	require.Equal(t, "1 rows could not be paid", job.Error.String)
+
+	rowErrors, err := testQueries.ListPayrollJobRowErrors(context.Background(), ListPayrollJobRowErrorsParams{
+		JobID: job.ID,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rowErrors, 1)
+	require.Equal(t, int32(4), rowErrors[0].RowNumber)
+	require.Equal(t, PayrollRowStatusFailed, rowErrors[0].Status)
+	require.Contains(t, rowErrors[0].Error.String, ErrAccountFrozen.Error())
+
+	// Only the paid rows left the account, and the rest of the reservation went back
+	account, err := testQueries.GetAccount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), employer.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+}
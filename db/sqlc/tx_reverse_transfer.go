@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrTransferIsReversal      = errors.New("cannot reverse a reversal transfer")
+	ErrReversalExceedsTransfer = errors.New("reversal amount exceeds the remaining transfer amount")
+)
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Zero reverses whatever is left of the original transfer
+	Amount int64 `json:"amount"`
+}
+
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	OriginalTransfer Transfer `json:"original_transfer"`
+	RemainingAmount  int64    `json:"remaining_amount"`
+}
+
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	/** Creates a compensating transfer that moves money back from the recipient of
+	the original transfer to its sender. Partial reversals are allowed as long as the
+	sum of all reversals never exceeds the original amount. */
+	var result ReverseTransferTxResult
+
+	transaction := func(q *Queries) error {
+		var err error
+		// Lock the original transfer so concurrent reversals of it are serialized
+		result.OriginalTransfer, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		original := result.OriginalTransfer
+		if original.ReversalOf.Valid {
+			return ErrTransferIsReversal
+		}
+
+		reversed, err := q.GetReversedAmount(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := original.Amount - reversed
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount <= 0 || amount > remaining {
+			return ErrReversalExceedsTransfer
+		}
+
+		// Money flows in the opposite direction of the original transfer
+		result.Transfer, err = q.CreateReversalTransfer(ctx, CreateReversalTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        amount,
+			ReversalOf: sql.NullInt64{
+				Int64: original.ID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordTransferCreated(ctx, q, result.Transfer)
+		if err != nil {
+			return err
+		}
+
+		err = auditTransferCreated(ctx, q, result.Transfer)
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: original.ToAccountID,
+			Amount:    -amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: original.FromAccountID,
+			Amount:    amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromAccount, result.ToAccount, err = transferBalances(
+			ctx,
+			q,
+			original.ToAccountID,
+			original.FromAccountID,
+			amount,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = checkTransferAccounts(result.FromAccount, result.ToAccount)
+		if err != nil {
+			return err
+		}
+
+		// The recipient may have spent or reserved the money in the meantime
+		err = checkAvailableFunds(ctx, q, result.FromAccount)
+		if err != nil {
+			return err
+		}
+
+		observeTransfer(q, result.FromAccount.Currency, amount)
+		result.RemainingAmount = remaining - amount
+		return nil
+	}
+	err := store.execTx(ctx, "ReverseTransferTx", transaction)
+	return result, err
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+	amount := int64(30)
+
+	transfer, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	// Partial reversal
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, transfer.Transfer.ID, result.OriginalTransfer.ID)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.True(t, result.Transfer.ReversalOf.Valid)
+	require.Equal(t, transfer.Transfer.ID, result.Transfer.ReversalOf.Int64)
+	require.Equal(t, int64(-10), result.FromEntry.Amount)
+	require.Equal(t, int64(10), result.ToEntry.Amount)
+	require.Equal(t, int64(20), result.RemainingAmount)
+
+	// Cannot reverse more than what is left
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     21,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// Zero amount reverses the remainder
+	result2, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(20), result2.Transfer.Amount)
+	require.Zero(t, result2.RemainingAmount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// A reversal itself cannot be reversed
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: result.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferIsReversal)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestReverseTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	currency := util.RandomCurrency()
+
+	account1 := createTestAccount(t, 100, currency, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, currency, util.CheckingAccount)
+	account3 := createTestAccount(t, 0, currency, util.CheckingAccount)
+
+	transfer, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+	})
+	require.NoError(t, err)
+
+	// The recipient spends the money before the transfer is reversed
+	_, err = store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account3.ID,
+		Amount:        25,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// What is left can still be sent back
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     5,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.FromAccount.Balance)
+	require.Equal(t, int64(25), result.RemainingAmount)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	transfer, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+
+	// Only five of the ten concurrent reversals fit into the original amount
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: transfer.Transfer.ID,
+				Amount:     10,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+	}
+	require.Equal(t, 5, succeeded)
+
+	reversed, err := testQueries.GetReversedAmount(context.Background(), transfer.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), reversed)
+}
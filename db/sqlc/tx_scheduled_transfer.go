@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+const (
+	ScheduledTransferStatusActive    = "active"
+	ScheduledTransferStatusPaused    = "paused"
+	ScheduledTransferStatusCancelled = "cancelled"
+	ScheduledTransferStatusCompleted = "completed"
+
+	// Skip moves on to the next occurrence, pause stops the schedule until it is resumed
+	FailurePolicySkip  = "skip"
+	FailurePolicyPause = "pause"
+
+	ScheduledTransferRunSucceeded = "succeeded"
+	ScheduledTransferRunFailed    = "failed"
+)
+
+var ErrNoScheduledTransferDue = errors.New("no scheduled transfer is due")
+
+func ScheduledTransferIdempotencyKey(scheduledTransferID int64, scheduledFor time.Time) string {
+	/** Identifies a single occurrence of a schedule, so it can never be paid twice. */
+	return fmt.Sprintf("scheduled-transfer:%d:%d", scheduledTransferID, scheduledFor.Unix())
+}
+
+type ExecuteScheduledTransferTxResult struct {
+	TransferTxResult
+	// Set even when the transfer fails, so the caller can record the failure
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+}
+
+func (store *SQLStore) ExecuteScheduledTransferTx(ctx context.Context) (ExecuteScheduledTransferTxResult, error) {
+	/** Picks one due scheduled transfer, skipping rows other workers have locked,
+	performs the transfer, records the run and moves the schedule to its next occurrence.
+	The transfer goes through the same limit, fee and funds checks as TransferMoneyTx.
+	Returns ErrNoScheduledTransferDue when there is nothing to do. */
+	var result ExecuteScheduledTransferTxResult
+
+	transaction := func(q *Queries) error {
+		due, err := q.GetDueScheduledTransferForUpdate(ctx)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoScheduledTransferDue
+			}
+			return err
+		}
+		result.ScheduledTransfer = due
+
+		idempotencyKey := ScheduledTransferIdempotencyKey(due.ID, due.NextRunAt)
+		// A standing order is an ordinary transfer of the owner's, made on their behalf
+		result.TransferTxResult, err = checkedTransfer(ctx, q, TransferTxParams{
+			FromAccountID:  due.FromAccountID,
+			ToAccountID:    due.ToAccountID,
+			Amount:         due.Amount,
+			IdempotencyKey: idempotencyKey,
+		}, true)
+		if err != nil {
+			return err
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: due.ID,
+			ScheduledFor:        due.NextRunAt,
+			IdempotencyKey:      idempotencyKey,
+			Status:              ScheduledTransferRunSucceeded,
+			TransferID: sql.NullInt64{
+				Int64: result.Transfer.ID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ScheduledTransfer, err = advanceSchedule(ctx, q, due, ScheduledTransferStatusActive)
+		return err
+	}
+	err := store.execTx(ctx, "ExecuteScheduledTransferTx", transaction)
+	return result, err
+}
+
+type FailScheduledTransferTxParams struct {
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	Error               string    `json:"error"`
+}
+
+func (store *SQLStore) FailScheduledTransferTx(ctx context.Context, arg FailScheduledTransferTxParams) (ScheduledTransfer, error) {
+	/** Records a failed occurrence and applies the schedule's failure policy.
+	Either way the failed occurrence is consumed and will not be retried, so it is
+	only meant for transfers that were refused, see IsTransferRejection. */
+	var result ScheduledTransfer
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.GetScheduledTransferForUpdate(ctx, arg.ScheduledTransferID)
+		if err != nil {
+			return err
+		}
+
+		// Another worker already dealt with this occurrence, or the schedule was paused or cancelled
+		if result.Status != ScheduledTransferStatusActive || !result.NextRunAt.Equal(arg.ScheduledFor) {
+			return nil
+		}
+
+		_, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: result.ID,
+			ScheduledFor:        result.NextRunAt,
+			IdempotencyKey:      ScheduledTransferIdempotencyKey(result.ID, result.NextRunAt),
+			Status:              ScheduledTransferRunFailed,
+			Error: sql.NullString{
+				String: arg.Error,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		status := ScheduledTransferStatusActive
+		if result.FailurePolicy == FailurePolicyPause {
+			status = ScheduledTransferStatusPaused
+		}
+
+		result, err = advanceSchedule(ctx, q, result, status)
+		return err
+	}
+	err := store.execTx(ctx, "FailScheduledTransferTx", transaction)
+	return result, err
+}
+
+func advanceSchedule(ctx context.Context, q *Queries, scheduled ScheduledTransfer, status string) (ScheduledTransfer, error) {
+	/** Moves the schedule to its next occurrence after now. Occurrences missed while the
+	worker was down are not caught up, and the schedule completes once it passes its end date. */
+	after := scheduled.NextRunAt
+	if now := time.Now(); now.After(after) {
+		after = now
+	}
+
+	nextRunAt, err := util.NextScheduledRun(scheduled.Schedule, after)
+	if err != nil {
+		return scheduled, err
+	}
+
+	if scheduled.EndAt.Valid && nextRunAt.After(scheduled.EndAt.Time) {
+		status = ScheduledTransferStatusCompleted
+	}
+
+	return q.AdvanceScheduledTransfer(ctx, AdvanceScheduledTransferParams{
+		ID:        scheduled.ID,
+		NextRunAt: nextRunAt,
+		Status:    status,
+	})
+}
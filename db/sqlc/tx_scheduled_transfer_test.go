@@ -0,0 +1,282 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createDueScheduledTransfer(t *testing.T, fromAccount, toAccount Account, amount int64, failurePolicy string) ScheduledTransfer {
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+		Schedule:      "@daily",
+		NextRunAt:     time.Now().Add(-time.Minute).Truncate(time.Second),
+		FailurePolicy: failurePolicy,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, scheduled.Status)
+	return scheduled
+}
+
+func runDueScheduledTransfers(t *testing.T, store Store) {
+	// Other tests may leave due schedules behind, so drain everything
+	for {
+		result, err := store.ExecuteScheduledTransferTx(context.Background())
+		if err == ErrNoScheduledTransferDue {
+			return
+		}
+		if err != nil {
+			_, err = store.FailScheduledTransferTx(context.Background(), FailScheduledTransferTxParams{
+				ScheduledTransferID: result.ScheduledTransfer.ID,
+				ScheduledFor:        result.ScheduledTransfer.NextRunAt,
+				Error:               err.Error(),
+			})
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestTransferMoneyTxIdempotencyKey(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         10,
+		IdempotencyKey: util.RandomString(20),
+	}
+
+	result1, err := store.TransferMoneyTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// Retrying returns the original transfer without moving money again
+	result2, err := store.TransferMoneyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result1.Transfer, result2.Transfer)
+	require.Equal(t, account1.Balance-10, result2.FromAccount.Balance)
+	require.Equal(t, account2.Balance+10, result2.ToAccount.Balance)
+}
+
+func TestExecuteScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, 10, FailurePolicySkip)
+
+	runDueScheduledTransfers(t, store)
+
+	updated, err := testQueries.GetScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, updated.Status)
+	require.True(t, updated.NextRunAt.After(time.Now()))
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               5,
+		Offset:              0,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, ScheduledTransferRunSucceeded, runs[0].Status)
+	require.WithinDuration(t, scheduled.NextRunAt, runs[0].ScheduledFor, 0)
+	require.True(t, runs[0].TransferID.Valid)
+
+	transfer, err := testQueries.GetTransfer(context.Background(), runs[0].TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), transfer.Amount)
+	require.Equal(t, runs[0].IdempotencyKey, transfer.IdempotencyKey.String)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), account.Balance)
+
+	// Not due again until tomorrow
+	runDueScheduledTransfers(t, store)
+	account, err = testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), account.Balance)
+}
+
+func TestExecuteScheduledTransferTxChecks(t *testing.T) {
+	store := NewStore(testDB)
+
+	revenue := setFeeSchedule(t, util.CAD, 5, 0, 0)
+	account1 := createTestAccount(t, 100, util.CAD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.CAD, util.CheckingAccount)
+	setTransferLimitOverride(t, account1, 30, 1000, 1000)
+
+	// Standing orders pay fees and stay within limits like any other transfer
+	paid := createDueScheduledTransfer(t, account1, account2, 30, FailurePolicySkip)
+	tooLarge := createDueScheduledTransfer(t, account1, account2, 31, FailurePolicySkip)
+	runDueScheduledTransfers(t, store)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100-30-5), account.Balance)
+
+	revenueAccount, err := testQueries.GetAccount(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), revenueAccount.Balance)
+
+	for scheduled, status := range map[int64]string{
+		paid.ID:     ScheduledTransferRunSucceeded,
+		tooLarge.ID: ScheduledTransferRunFailed,
+	} {
+		runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+			ScheduledTransferID: scheduled,
+			Limit:               5,
+		})
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		require.Equal(t, status, runs[0].Status)
+	}
+}
+
+func TestExecuteScheduledTransferTxEndDate(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Schedule:      "@daily",
+		NextRunAt:     time.Now().Add(-time.Minute),
+		EndAt: sql.NullTime{
+			Time:  time.Now().Add(time.Hour),
+			Valid: true,
+		},
+		FailurePolicy: FailurePolicySkip,
+	})
+	require.NoError(t, err)
+
+	runDueScheduledTransfers(t, store)
+
+	// The next occurrence falls after the end date
+	updated, err := testQueries.GetScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusCompleted, updated.Status)
+}
+
+func TestFailScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	testCases := []struct {
+		failurePolicy string
+		status        string
+	}{
+		{FailurePolicySkip, ScheduledTransferStatusActive},
+		{FailurePolicyPause, ScheduledTransferStatusPaused},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.failurePolicy, func(t *testing.T) {
+			account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+			account2 := CreateRandomAccount(t)
+
+			// Everything is held, so the standing order cannot be paid
+			_, err := store.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        100,
+				TTL:           time.Minute,
+			})
+			require.NoError(t, err)
+
+			scheduled := createDueScheduledTransfer(t, account1, account2, 10, tc.failurePolicy)
+			runDueScheduledTransfers(t, store)
+
+			updated, err := testQueries.GetScheduledTransfer(context.Background(), scheduled.ID)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, updated.Status)
+			require.True(t, updated.NextRunAt.After(time.Now()))
+
+			runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+				ScheduledTransferID: scheduled.ID,
+				Limit:               5,
+				Offset:              0,
+			})
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
+			require.Equal(t, ScheduledTransferRunFailed, runs[0].Status)
+			require.False(t, runs[0].TransferID.Valid)
+			require.Equal(t, ErrInsufficientFunds.Error(), runs[0].Error.String)
+
+			account, err := testQueries.GetAccount(context.Background(), account1.ID)
+			require.NoError(t, err)
+			require.Equal(t, int64(100), account.Balance)
+
+			// Recording the same failure twice is a no-op
+			again, err := store.FailScheduledTransferTx(context.Background(), FailScheduledTransferTxParams{
+				ScheduledTransferID: scheduled.ID,
+				ScheduledFor:        scheduled.NextRunAt,
+				Error:               "retry",
+			})
+			require.NoError(t, err)
+			require.Equal(t, updated.NextRunAt, again.NextRunAt)
+		})
+	}
+}
+
+func TestExecuteScheduledTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 100, util.RandomCurrency(), util.CheckingAccount)
+	account2 := CreateRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, 10, FailurePolicySkip)
+
+	// Several workers racing for due rows must pay each occurrence exactly once
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			for {
+				result, err := store.ExecuteScheduledTransferTx(context.Background())
+				if err == ErrNoScheduledTransferDue {
+					errs <- nil
+					return
+				}
+				if err != nil {
+					_, err = store.FailScheduledTransferTx(context.Background(), FailScheduledTransferTxParams{
+						ScheduledTransferID: result.ScheduledTransfer.ID,
+						ScheduledFor:        result.ScheduledTransfer.NextRunAt,
+						Error:               err.Error(),
+					})
+					if err != nil {
+						errs <- err
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               5,
+		Offset:              0,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), account.Balance)
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+// Snapshot of a session for the audit log, without the refresh token
+type sessionAudit struct {
+	SessionID uuid.UUID `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (store *SQLStore) CreateSessionTx(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	/** Starts the session of a successful login and audits the login. */
+	var result Session
+
+	transaction := func(q *Queries) error {
+		var err error
+		result, err = q.CreateSession(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, AuditRecord{
+			Action:     util.AuditUserLogin,
+			TargetType: util.AuditTargetUser,
+			TargetID:   result.Username,
+			Actor:      result.Username,
+			After: sessionAudit{
+				SessionID: result.ID,
+				ExpiresAt: result.ExpiresAt,
+			},
+		}, util.AuditSuccess)
+	}
+	err := store.execTx(ctx, "CreateSessionTx", transaction)
+	return result, err
+}
+
+// RecordSuspiciousSessionTxParams describes a session renewed from another client
+// than the one that started it, and what the risk policy does about it.
+type RecordSuspiciousSessionTxParams struct {
+	Session   Session
+	ClientIP  string
+	UserAgent string
+	Signals   []string
+	Policy    string
+}
+
+// Payload of a session.suspicious event, and its snapshot in the audit log
+type suspiciousSessionEvent struct {
+	SessionID uuid.UUID `json:"session_id"`
+	Username  string    `json:"username"`
+	ClientIP  string    `json:"client_ip"`
+	UserAgent string    `json:"user_agent"`
+	Signals   []string  `json:"signals,omitempty"`
+	Policy    string    `json:"policy,omitempty"`
+	IsBlocked bool      `json:"is_blocked"`
+}
+
+func (store *SQLStore) RecordSuspiciousSessionTx(ctx context.Context, arg RecordSuspiciousSessionTxParams) (Session, error) {
+	/** Applies the risk policy to a suspicious renewal: the session is blocked under
+	the block policy. Every policy audits the renewal and announces it with a
+	session.suspicious event. The renewal is audited as a failure unless the policy
+	lets it through. */
+	result := arg.Session
+
+	transaction := func(q *Queries) error {
+		var err error
+		if arg.Policy == util.SessionRiskBlock {
+			result, err = q.BlockSession(ctx, arg.Session.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		before := suspiciousSessionEvent{
+			SessionID: arg.Session.ID,
+			Username:  arg.Session.Username,
+			ClientIP:  arg.Session.ClientIp,
+			UserAgent: arg.Session.UserAgent,
+			IsBlocked: arg.Session.IsBlocked,
+		}
+		after := suspiciousSessionEvent{
+			SessionID: result.ID,
+			Username:  result.Username,
+			ClientIP:  arg.ClientIP,
+			UserAgent: arg.UserAgent,
+			Signals:   arg.Signals,
+			Policy:    arg.Policy,
+			IsBlocked: result.IsBlocked,
+		}
+
+		outcome := util.AuditFailure
+		if arg.Policy == util.SessionRiskNotify {
+			outcome = util.AuditSuccess
+		}
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     util.AuditSessionSuspicious,
+			TargetType: util.AuditTargetSession,
+			TargetID:   result.ID.String(),
+			Actor:      result.Username,
+			Reason:     strings.Join(arg.Signals, ","),
+			Before:     before,
+			After:      after,
+		}, outcome)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, util.EventSessionSuspicious, after)
+	}
+	err := store.execTx(ctx, "RecordSuspiciousSessionTx", transaction)
+	return result, err
+}
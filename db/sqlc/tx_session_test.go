@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T) Session {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	session, err := store.CreateSessionTx(context.Background(), CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "Mozilla/5.0",
+		ClientIp:     "203.0.113.7",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	events := findAuditEvents(t, util.AuditUserLogin, user.Username)
+	require.Len(t, events, 1)
+	require.Equal(t, util.AuditSuccess, events[0].Outcome)
+	return session
+}
+
+func TestRecordSuspiciousSessionTx(t *testing.T) {
+	testCases := []struct {
+		policy  string
+		blocked bool
+		outcome string
+	}{
+		{policy: util.SessionRiskNotify, blocked: false, outcome: util.AuditSuccess},
+		{policy: util.SessionRiskReauth, blocked: false, outcome: util.AuditFailure},
+		{policy: util.SessionRiskBlock, blocked: true, outcome: util.AuditFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			store := NewStore(testDB)
+			session := createRandomSession(t)
+
+			result, err := store.RecordSuspiciousSessionTx(context.Background(), RecordSuspiciousSessionTxParams{
+				Session:   session,
+				ClientIP:  "198.51.100.2",
+				UserAgent: session.UserAgent,
+				Signals:   []string{util.SessionClientIPChanged},
+				Policy:    tc.policy,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.blocked, result.IsBlocked)
+
+			stored, err := testQueries.GetSession(context.Background(), session.ID)
+			require.NoError(t, err)
+			require.Equal(t, tc.blocked, stored.IsBlocked)
+
+			events := findAuditEvents(t, util.AuditSessionSuspicious, session.ID.String())
+			require.Len(t, events, 1)
+			require.Equal(t, tc.outcome, events[0].Outcome)
+			require.Equal(t, session.Username, events[0].Actor)
+			require.Equal(t, util.SessionClientIPChanged, events[0].Reason)
+
+			event := findOutboxEvent(t, util.EventSessionSuspicious, "session_id", session.ID.String())
+			var payload suspiciousSessionEvent
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			require.Equal(t, "198.51.100.2", payload.ClientIP)
+			require.Equal(t, tc.policy, payload.Policy)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type GetStatementTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// The statement covers entries made at or after From and before To
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type StatementLine struct {
+	Entry Entry `json:"entry"`
+	// Account balance right after the entry
+	Balance int64 `json:"balance"`
+}
+
+type Statement struct {
+	Account        Account   `json:"account"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	TotalCredits   int64     `json:"total_credits"`
+	// Sum of the money that left the account, as a positive number
+	TotalDebits int64           `json:"total_debits"`
+	Lines       []StatementLine `json:"lines"`
+}
+
+func (store *SQLStore) GetStatementTx(ctx context.Context, arg GetStatementTxParams) (Statement, error) {
+	/** Builds the statement of an account for any period from its entries.
+	The opening balance is the current balance with every entry made since the period
+	started taken back out. Everything is read from one snapshot so a transfer committing
+	halfway through cannot make the numbers disagree. */
+	var result Statement
+
+	transaction := func(q *Queries) error {
+		// Totals are summed up below, start from scratch if the transaction is retried
+		result = Statement{}
+
+		var err error
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		since, err := q.GetEntriesTotalSince(ctx, GetEntriesTotalSinceParams{
+			AccountID: arg.AccountID,
+			CreatedAt: arg.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		entries, err := q.ListEntriesBetween(ctx, ListEntriesBetweenParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.From = arg.From
+		result.To = arg.To
+		result.OpeningBalance = result.Account.Balance - since
+		result.Lines = make([]StatementLine, len(entries))
+
+		balance := result.OpeningBalance
+		for i, entry := range entries {
+			balance += entry.Amount
+			if entry.Amount > 0 {
+				result.TotalCredits += entry.Amount
+			} else {
+				result.TotalDebits -= entry.Amount
+			}
+			result.Lines[i] = StatementLine{
+				Entry:   entry,
+				Balance: balance,
+			}
+		}
+		result.ClosingBalance = balance
+		return nil
+	}
+
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	}
+	err := store.execTxWithOptions(ctx, "GetStatementTx", opts, transaction)
+	return result, err
+}
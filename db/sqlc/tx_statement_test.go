@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 1000, util.USD, util.CheckingAccount)
+
+	amounts := []int64{100, -30, 250}
+	for _, amount := range amounts {
+		arg := TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		}
+		if amount < 0 {
+			arg.FromAccountID, arg.ToAccountID, arg.Amount = account2.ID, account1.ID, -amount
+		}
+		_, err := store.TransferMoneyTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	from := account1.CreatedAt.Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+	statement, err := store.GetStatementTx(context.Background(), GetStatementTxParams{
+		AccountID: account1.ID,
+		From:      from,
+		To:        to,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, account1.ID, statement.Account.ID)
+	require.Equal(t, int64(1000), statement.OpeningBalance)
+	require.Equal(t, int64(1000-100+30-250), statement.ClosingBalance)
+	require.Equal(t, int64(30), statement.TotalCredits)
+	require.Equal(t, int64(350), statement.TotalDebits)
+	require.Len(t, statement.Lines, 3)
+
+	balance := statement.OpeningBalance
+	for i, line := range statement.Lines {
+		require.Equal(t, -amounts[i], line.Entry.Amount)
+		balance += line.Entry.Amount
+		require.Equal(t, balance, line.Balance)
+	}
+
+	// A period that ended before the account was used shows its old balance
+	statement, err = store.GetStatementTx(context.Background(), GetStatementTxParams{
+		AccountID: account1.ID,
+		From:      from.Add(-time.Hour),
+		To:        from,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), statement.OpeningBalance)
+	require.Equal(t, int64(1000), statement.ClosingBalance)
+	require.Empty(t, statement.Lines)
+}
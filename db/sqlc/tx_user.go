@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserParams) (User, error) {
+	/** Updates a user's profile and announces it with a user.updated event. The audit
+	log gets the fields that changed, the password only as its change time and the
+	KYC details only as the names of those that changed. */
+	var result User
+
+	transaction := func(q *Queries) error {
+		before, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.UpdateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     util.AuditUserUpdated,
+			TargetType: util.AuditTargetUser,
+			TargetID:   result.Username,
+			Before:     newUserEvent(before),
+			After:      newUserUpdatedEvent(before, result),
+		}, util.AuditSuccess)
+		if err != nil {
+			return err
+		}
+
+		return recordUserUpdated(ctx, q, before, result)
+	}
+	err := store.execTx(ctx, "UpdateUserTx", transaction)
+	if err != nil {
+		err = store.auditFailure(ctx, AuditRecord{
+			Action:     util.AuditUserUpdated,
+			TargetType: util.AuditTargetUser,
+			TargetID:   arg.Username,
+		}, err)
+	}
+	return result, err
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	// Gave up after too many failed attempts, only a replay sends it again
+	WebhookDead = "dead"
+)
+
+var (
+	ErrNoEventToPublish   = errors.New("no outbox event is waiting to be published")
+	ErrNoWebhookToDeliver = errors.New("no webhook delivery is due")
+)
+
+type PublishOutboxEventTxResult struct {
+	Event OutboxEvent `json:"event"`
+	// Number of endpoints the event was queued for
+	Deliveries int64 `json:"deliveries"`
+}
+
+func (store *SQLStore) PublishOutboxEventTx(ctx context.Context) (PublishOutboxEventTxResult, error) {
+	/** Queues the oldest unpublished outbox event for every webhook endpoint that subscribed
+	to its type, skipping events other workers have locked. Returns ErrNoEventToPublish
+	when the outbox is drained. */
+	var result PublishOutboxEventTxResult
+
+	transaction := func(q *Queries) error {
+		var err error
+		result.Event, err = q.GetNextOutboxEventToPublish(ctx)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoEventToPublish
+			}
+			return err
+		}
+
+		result.Deliveries, err = q.CreateWebhookDeliveries(ctx, CreateWebhookDeliveriesParams{
+			EventID:   result.Event.ID,
+			EventType: result.Event.EventType,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.SetOutboxEventPublished(ctx, result.Event.ID)
+	}
+	err := store.execTx(ctx, "PublishOutboxEventTx", transaction)
+	return result, err
+}
+
+type ClaimWebhookDeliveryTxParams struct {
+	// How long the caller has to record the outcome before another worker may retry
+	Lease time.Duration `json:"lease"`
+}
+
+type ClaimWebhookDeliveryTxResult struct {
+	Delivery WebhookDelivery `json:"delivery"`
+	Endpoint WebhookEndpoint `json:"endpoint"`
+	Event    OutboxEvent     `json:"event"`
+}
+
+func (store *SQLStore) ClaimWebhookDeliveryTx(ctx context.Context, arg ClaimWebhookDeliveryTxParams) (ClaimWebhookDeliveryTxResult, error) {
+	/** Picks the next due delivery and counts the attempt. Instead of holding a lock while
+	the request is sent, the delivery is pushed back by the lease; if the worker dies
+	before recording the outcome it simply becomes due again. Returns ErrNoWebhookToDeliver
+	when nothing is due. */
+	var result ClaimWebhookDeliveryTxResult
+
+	transaction := func(q *Queries) error {
+		delivery, err := q.GetNextWebhookDelivery(ctx)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoWebhookToDeliver
+			}
+			return err
+		}
+
+		result.Delivery, err = q.StartWebhookAttempt(ctx, StartWebhookAttemptParams{
+			ID:            delivery.ID,
+			NextAttemptAt: time.Now().Add(arg.Lease),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Endpoint, err = q.GetWebhookEndpoint(ctx, delivery.EndpointID)
+		if err != nil {
+			return err
+		}
+
+		result.Event, err = q.GetOutboxEvent(ctx, delivery.EventID)
+		return err
+	}
+	err := store.execTx(ctx, "ClaimWebhookDeliveryTx", transaction)
+	return result, err
+}
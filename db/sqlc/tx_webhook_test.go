@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func findOutboxEvent(t *testing.T, eventType string, key string, value string) OutboxEvent {
+	var id int64
+	err := testDB.QueryRow(
+		"SELECT id FROM outbox_events WHERE event_type = $1 AND payload->>$2 = $3",
+		eventType, key, value,
+	).Scan(&id)
+	require.NoError(t, err)
+
+	event, err := testQueries.GetOutboxEvent(context.Background(), id)
+	require.NoError(t, err)
+	return event
+}
+
+func TestCreateAccountTxEvent(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+		Type:     util.CheckingAccount,
+	})
+	require.NoError(t, err)
+
+	event := findOutboxEvent(t, util.EventAccountCreated, "owner", user.Username)
+	var payload Account
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, account.ID, payload.ID)
+	require.False(t, event.PublishedAt.Valid)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	store := NewStore(testDB)
+	banker := CreateRandomUser(t)
+
+	endpoint, err := store.CreateWebhookEndpoint(context.Background(), CreateWebhookEndpointParams{
+		Owner:      banker.Username,
+		Url:        "https://example.com/webhook",
+		Secret:     util.RandomString(32),
+		EventTypes: []string{util.EventTransferCreated},
+	})
+	require.NoError(t, err)
+
+	account1 := createTestAccount(t, 100, util.USD, util.CheckingAccount)
+	account2 := createTestAccount(t, 0, util.USD, util.CheckingAccount)
+	transfer, err := store.TransferMoneyTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	event := findOutboxEvent(t, util.EventTransferCreated, "from_account_id", strconv.FormatInt(account1.ID, 10))
+	var payload transferEvent
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, transfer.Transfer.ID, payload.ID)
+	require.Equal(t, int64(10), payload.Amount)
+
+	for {
+		_, err := store.PublishOutboxEventTx(context.Background())
+		if err == ErrNoEventToPublish {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	// Only the transfer matched the endpoint's subscription
+	var deliveries int
+	err = testDB.QueryRow("SELECT count(*) FROM webhook_deliveries WHERE endpoint_id = $1", endpoint.ID).Scan(&deliveries)
+	require.NoError(t, err)
+	require.Equal(t, 1, deliveries)
+
+	var claim ClaimWebhookDeliveryTxResult
+	for claim.Delivery.EndpointID != endpoint.ID {
+		claim, err = store.ClaimWebhookDeliveryTx(context.Background(), ClaimWebhookDeliveryTxParams{
+			Lease: time.Minute,
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, event.ID, claim.Event.ID)
+	require.Equal(t, endpoint.Secret, claim.Endpoint.Secret)
+	require.Equal(t, int32(1), claim.Delivery.Attempts)
+	require.WithinDuration(t, time.Now().Add(time.Minute), claim.Delivery.NextAttemptAt, 5*time.Second)
+
+	dead, err := store.RecordWebhookAttempt(context.Background(), RecordWebhookAttemptParams{
+		ID:             claim.Delivery.ID,
+		Status:         WebhookDead,
+		NextAttemptAt:  time.Now(),
+		LastStatusCode: sql.NullInt32{Int32: 500, Valid: true},
+		LastError:      sql.NullString{String: "endpoint responded with 500", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, WebhookDead, dead.Status)
+
+	// Replaying gives the dead delivery a fresh start
+	replayed, err := store.ReplayWebhookEvents(context.Background(), ReplayWebhookEventsParams{
+		EndpointID: endpoint.ID,
+		FromTime:   event.CreatedAt.Add(-time.Second),
+		ToTime:     event.CreatedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, replayed, int64(1))
+
+	var status string
+	var attempts int32
+	err = testDB.QueryRow(
+		"SELECT status, attempts FROM webhook_deliveries WHERE id = $1", claim.Delivery.ID,
+	).Scan(&status, &attempts)
+	require.NoError(t, err)
+	require.Equal(t, WebhookPending, status)
+	require.Zero(t, attempts)
+}
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -19,7 +20,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, phone, address_line1, address_line2, address_city, address_postal_code, address_country, date_of_birth, nationality
 `
 
 type CreateUserParams struct {
@@ -44,12 +45,21 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.Phone,
+		&i.AddressLine1,
+		&i.AddressLine2,
+		&i.AddressCity,
+		&i.AddressPostalCode,
+		&i.AddressCountry,
+		&i.DateOfBirth,
+		&i.Nationality,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, phone, address_line1, address_line2, address_city, address_postal_code, address_country, date_of_birth, nationality FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -63,36 +73,134 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.Phone,
+		&i.AddressLine1,
+		&i.AddressLine2,
+		&i.AddressCity,
+		&i.AddressPostalCode,
+		&i.AddressCountry,
+		&i.DateOfBirth,
+		&i.Nationality,
 	)
 	return i, err
 }
 
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, phone, address_line1, address_line2, address_city, address_postal_code, address_country, date_of_birth, nationality FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.Phone,
+		&i.AddressLine1,
+		&i.AddressLine2,
+		&i.AddressCity,
+		&i.AddressPostalCode,
+		&i.AddressCountry,
+		&i.DateOfBirth,
+		&i.Nationality,
+	)
+	return i, err
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :execrows
+UPDATE users
+SET hashed_password = $1
+WHERE
+  username = $2 AND
+  hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	NewHashedPassword string `json:"new_hashed_password"`
+	Username          string `json:"username"`
+	OldHashedPassword string `json:"old_hashed_password"`
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rehashUserPassword, arg.NewHashedPassword, arg.Username, arg.OldHashedPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
-  hashed_password = COALESCE($1, hashed_password),
-  password_changed_at = COALESCE($2, password_changed_at),
-  full_name = COALESCE($3, full_name),
-  email = COALESCE($4, email)
+  hashed_password = CASE WHEN $1::boolean THEN $2::varchar ELSE hashed_password END,
+  password_changed_at = CASE WHEN $1::boolean THEN $3::timestamptz ELSE password_changed_at END,
+  full_name = CASE WHEN $4::boolean THEN $5::varchar ELSE full_name END,
+  email = CASE WHEN $6::boolean THEN $7::varchar ELSE email END,
+  phone = CASE WHEN $8::boolean THEN $9::varchar ELSE phone END,
+  address_line1 = CASE WHEN $10::boolean THEN $11::varchar ELSE address_line1 END,
+  address_line2 = CASE WHEN $10::boolean THEN $12::varchar ELSE address_line2 END,
+  address_city = CASE WHEN $10::boolean THEN $13::varchar ELSE address_city END,
+  address_postal_code = CASE WHEN $10::boolean THEN $14::varchar ELSE address_postal_code END,
+  address_country = CASE WHEN $10::boolean THEN $15::varchar ELSE address_country END,
+  date_of_birth = CASE WHEN $16::boolean THEN $17::date ELSE date_of_birth END,
+  nationality = CASE WHEN $18::boolean THEN $19::varchar ELSE nationality END
 WHERE
-  username = $5
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+  username = $20
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, phone, address_line1, address_line2, address_city, address_postal_code, address_country, date_of_birth, nationality
 `
 
 type UpdateUserParams struct {
-	HashedPassword    sql.NullString `json:"hashed_password"`
-	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
-	FullName          sql.NullString `json:"full_name"`
-	Email             sql.NullString `json:"email"`
+	SetHashedPassword bool           `json:"set_hashed_password"`
+	HashedPassword    string         `json:"hashed_password"`
+	PasswordChangedAt time.Time      `json:"password_changed_at"`
+	SetFullName       bool           `json:"set_full_name"`
+	FullName          string         `json:"full_name"`
+	SetEmail          bool           `json:"set_email"`
+	Email             string         `json:"email"`
+	SetPhone          bool           `json:"set_phone"`
+	Phone             sql.NullString `json:"phone"`
+	SetAddress        bool           `json:"set_address"`
+	AddressLine1      sql.NullString `json:"address_line1"`
+	AddressLine2      sql.NullString `json:"address_line2"`
+	AddressCity       sql.NullString `json:"address_city"`
+	AddressPostalCode sql.NullString `json:"address_postal_code"`
+	AddressCountry    sql.NullString `json:"address_country"`
+	SetDateOfBirth    bool           `json:"set_date_of_birth"`
+	DateOfBirth       sql.NullTime   `json:"date_of_birth"`
+	SetNationality    bool           `json:"set_nationality"`
+	Nationality       sql.NullString `json:"nationality"`
 	Username          string         `json:"username"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.SetHashedPassword,
 		arg.HashedPassword,
 		arg.PasswordChangedAt,
+		arg.SetFullName,
 		arg.FullName,
+		arg.SetEmail,
 		arg.Email,
+		arg.SetPhone,
+		arg.Phone,
+		arg.SetAddress,
+		arg.AddressLine1,
+		arg.AddressLine2,
+		arg.AddressCity,
+		arg.AddressPostalCode,
+		arg.AddressCountry,
+		arg.SetDateOfBirth,
+		arg.DateOfBirth,
+		arg.SetNationality,
+		arg.Nationality,
 		arg.Username,
 	)
 	var i User
@@ -103,6 +211,15 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.Phone,
+		&i.AddressLine1,
+		&i.AddressLine2,
+		&i.AddressCity,
+		&i.AddressPostalCode,
+		&i.AddressCountry,
+		&i.DateOfBirth,
+		&i.Nationality,
 	)
 	return i, err
 }
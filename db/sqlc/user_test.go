@@ -6,13 +6,22 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
-func CreateRandomUser(t *testing.T) User {
-	hashedPassword, err := util.HashPassword(util.RandomString(6))
+func hashPassword(t *testing.T, plain string) string {
+	passwords, err := password.New(util.Config{})
+	require.NoError(t, err)
+
+	hashedPassword, err := passwords.Hash(plain)
 	require.NoError(t, err)
+	return hashedPassword
+}
+
+func CreateRandomUser(t *testing.T) User {
+	hashedPassword := hashPassword(t, util.RandomString(6))
 	arg := CreateUserParams{
 		Username:       util.RandomOwner(),
 		HashedPassword: hashedPassword,
@@ -58,11 +67,9 @@ func TestUpdateUserOnlyFullName(t *testing.T) {
 	oldUser := CreateRandomUser(t)
 	newFullName := util.RandomOwner()
 	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
-		Username: oldUser.Username,
-		FullName: sql.NullString{
-			String: newFullName,
-			Valid:  true,
-		},
+		Username:    oldUser.Username,
+		SetFullName: true,
+		FullName:    newFullName,
 	})
 
 	require.NoError(t, err)
@@ -77,10 +84,8 @@ func TestUpdateUserOnlyEmail(t *testing.T) {
 	newEmail := util.RandomOwner()
 	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
 		Username: oldUser.Username,
-		Email: sql.NullString{
-			String: newEmail,
-			Valid:  true,
-		},
+		SetEmail: true,
+		Email:    newEmail,
 	})
 
 	require.NoError(t, err)
@@ -93,19 +98,102 @@ func TestUpdateUserOnlyEmail(t *testing.T) {
 func TestUpdateUserOnlyHashedPassword(t *testing.T) {
 	oldUser := CreateRandomUser(t)
 	newPassword := util.RandomString(10)
-	newhashedPassword, err := util.HashPassword(newPassword)
-	require.NoError(t, err)
+	newhashedPassword := hashPassword(t, newPassword)
 	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
-		Username: oldUser.Username,
-		HashedPassword: sql.NullString{
-			String: newhashedPassword,
-			Valid:  true,
-		},
+		Username:          oldUser.Username,
+		SetHashedPassword: true,
+		HashedPassword:    newhashedPassword,
+		PasswordChangedAt: time.Now(),
 	})
 
 	require.NoError(t, err)
 	require.NotEqual(t, oldUser.HashedPassword, updatedUser.HashedPassword)
 	require.Equal(t, newhashedPassword, updatedUser.HashedPassword)
+	require.True(t, updatedUser.PasswordChangedAt.After(oldUser.PasswordChangedAt))
 	require.Equal(t, oldUser.Email, updatedUser.Email)
 	require.Equal(t, oldUser.FullName, updatedUser.FullName)
 }
+
+func TestUpdateUserProfile(t *testing.T) {
+	oldUser := CreateRandomUser(t)
+	dateOfBirth := time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC)
+	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:          oldUser.Username,
+		SetPhone:          true,
+		Phone:             sql.NullString{String: "+14155552671", Valid: true},
+		SetAddress:        true,
+		AddressLine1:      sql.NullString{String: "1 Main Street", Valid: true},
+		AddressCity:       sql.NullString{String: "Springfield", Valid: true},
+		AddressPostalCode: sql.NullString{String: "12345", Valid: true},
+		AddressCountry:    sql.NullString{String: "US", Valid: true},
+		SetDateOfBirth:    true,
+		DateOfBirth:       sql.NullTime{Time: dateOfBirth, Valid: true},
+		SetNationality:    true,
+		Nationality:       sql.NullString{String: "CA", Valid: true},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "+14155552671", updatedUser.Phone.String)
+	require.Equal(t, "1 Main Street", updatedUser.AddressLine1.String)
+	require.False(t, updatedUser.AddressLine2.Valid)
+	require.Equal(t, "Springfield", updatedUser.AddressCity.String)
+	require.Equal(t, "12345", updatedUser.AddressPostalCode.String)
+	require.Equal(t, "US", updatedUser.AddressCountry.String)
+	require.True(t, dateOfBirth.Equal(updatedUser.DateOfBirth.Time))
+	require.Equal(t, "CA", updatedUser.Nationality.String)
+	require.Equal(t, oldUser.FullName, updatedUser.FullName)
+	require.Equal(t, oldUser.Email, updatedUser.Email)
+
+	// Fields that are set without a value are cleared, the others are left alone
+	clearedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:   oldUser.Username,
+		SetPhone:   true,
+		SetAddress: true,
+	})
+
+	require.NoError(t, err)
+	require.False(t, clearedUser.Phone.Valid)
+	require.False(t, clearedUser.AddressLine1.Valid)
+	require.False(t, clearedUser.AddressCity.Valid)
+	require.False(t, clearedUser.AddressPostalCode.Valid)
+	require.False(t, clearedUser.AddressCountry.Valid)
+	require.Equal(t, updatedUser.DateOfBirth, clearedUser.DateOfBirth)
+	require.Equal(t, updatedUser.Nationality, clearedUser.Nationality)
+}
+
+func TestUpdateUserIncompleteAddress(t *testing.T) {
+	user := CreateRandomUser(t)
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:     user.Username,
+		SetAddress:   true,
+		AddressLine1: sql.NullString{String: "1 Main Street", Valid: true},
+	})
+	require.Error(t, err)
+}
+
+func TestRehashUserPassword(t *testing.T) {
+	user := CreateRandomUser(t)
+	newHashedPassword := hashPassword(t, util.RandomString(6))
+
+	rows, err := testQueries.RehashUserPassword(context.Background(), RehashUserPasswordParams{
+		NewHashedPassword: newHashedPassword,
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	updatedUser, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, newHashedPassword, updatedUser.HashedPassword)
+	require.Equal(t, user.PasswordChangedAt, updatedUser.PasswordChangedAt)
+
+	// A password changed since the login is not overwritten
+	rows, err = testQueries.RehashUserPassword(context.Background(), RehashUserPasswordParams{
+		NewHashedPassword: hashPassword(t, util.RandomString(6)),
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
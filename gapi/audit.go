@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"google.golang.org/grpc"
+)
+
+// AuditContext is a unary interceptor telling the store who makes the call and from
+// where, so the changes it makes are audited with them. Callers without a valid
+// token are audited as anonymous.
+func (server *Server) AuditContext(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	mtdt := server.extractMetadata(ctx)
+	audit := db.AuditContext{
+		ClientIP:  mtdt.ClientIP,
+		UserAgent: mtdt.UserAgent,
+	}
+	if payload, err := server.authenticateUser(ctx); err == nil {
+		audit.Actor = payload.Username
+	}
+
+	return handler(db.WithAuditContext(ctx, audit), req)
+}
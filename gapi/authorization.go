@@ -14,7 +14,21 @@ const (
 	authorizationBearer = "bearer"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+func (server *Server) authorizeUser(ctx context.Context, accessibleRoles []string) (*token.Payload, error) {
+	payload, err := server.authenticateUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPermission(payload.Role, accessibleRoles) {
+		return nil, errPermissionDenied
+	}
+
+	return payload, nil
+}
+
+// authenticateUser verifies the access token without checking the user's role
+func (server *Server) authenticateUser(ctx context.Context) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("missing metadata")
@@ -40,8 +54,17 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 	accessToken := fields[1]
 	payload, err := server.tokenMaker.VerifyToken(accessToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid access token: %s", err)
+		return nil, fmt.Errorf("invalid access token: %w", err)
 	}
 
-	return payload, err
+	return payload, nil
+}
+
+func hasPermission(userRole string, accessibleRoles []string) bool {
+	for _, role := range accessibleRoles {
+		if userRole == role {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,67 @@
+package gapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(certificate *x509.Certificate) context.Context {
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}}
+	if certificate != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+		}
+	}
+	return peer.NewContext(context.Background(), p)
+}
+
+func TestClientIdentity(t *testing.T) {
+	testCases := []struct {
+		name        string
+		certificate *x509.Certificate
+		identity    string
+	}{
+		{"NoCertificate", nil, ""},
+		{"DNSName", &x509.Certificate{
+			Subject:  pkix.Name{CommonName: "ignored"},
+			DNSNames: []string{"gateway.simplebank.internal", "gateway"},
+		}, "gateway.simplebank.internal"},
+		{"CommonName", &x509.Certificate{
+			Subject: pkix.Name{CommonName: "reporting"},
+		}, "reporting"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.identity, clientIdentity(peerContext(tc.certificate)))
+		})
+	}
+}
+
+func TestAuthorizeClient(t *testing.T) {
+	gateway := &x509.Certificate{DNSNames: []string{"gateway"}}
+	reporting := &x509.Certificate{Subject: pkix.Name{CommonName: "reporting"}}
+
+	// Without names every caller that passed the handshake is let in
+	server := &Server{clientNames: parseClientNames("")}
+	require.NoError(t, server.authorizeClient(peerContext(reporting)))
+	require.NoError(t, server.authorizeClient(peerContext(nil)))
+
+	server = &Server{clientNames: parseClientNames(" gateway, ,backoffice ")}
+	require.NoError(t, server.authorizeClient(peerContext(gateway)))
+
+	for _, ctx := range []context.Context{peerContext(reporting), peerContext(nil)} {
+		err := server.authorizeClient(ctx)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+}
@@ -1,17 +1,250 @@
 package gapi
 
 import (
+	"encoding/json"
+	"time"
+
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func convertUser(user db.User) *pb.User {
-	return &pb.User{
+	rsp := &pb.User{
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
+		Phone:             user.Phone.String,
+		Nationality:       user.Nationality.String,
+	}
+	if user.AddressLine1.Valid {
+		rsp.Address = &pb.Address{
+			Line1:      user.AddressLine1.String,
+			Line2:      user.AddressLine2.String,
+			City:       user.AddressCity.String,
+			PostalCode: user.AddressPostalCode.String,
+			Country:    user.AddressCountry.String,
+		}
+	}
+	if user.DateOfBirth.Valid {
+		rsp.DateOfBirth = user.DateOfBirth.Time.Format(time.DateOnly)
+	}
+	return rsp
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	rsp := &pb.Transfer{
+		Id:            transfer.ID,
+		FromAccountId: transfer.FromAccountID,
+		ToAccountId:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+	}
+	if transfer.ReversalOf.Valid {
+		rsp.ReversalOf = &transfer.ReversalOf.Int64
+	}
+	if transfer.Memo.Valid {
+		rsp.Memo = &transfer.Memo.String
+	}
+	return rsp
+}
+
+func convertAccountHold(hold db.AccountHold) *pb.AccountHold {
+	rsp := &pb.AccountHold{
+		Id:            hold.ID,
+		FromAccountId: hold.FromAccountID,
+		ToAccountId:   hold.ToAccountID.Int64,
+		Amount:        hold.Amount,
+		Status:        hold.Status,
+		ExpiresAt:     timestamppb.New(hold.ExpiresAt),
+		CreatedAt:     timestamppb.New(hold.CreatedAt),
+	}
+	if hold.TransferID.Valid {
+		rsp.TransferId = &hold.TransferID.Int64
+	}
+	if hold.ReleasedAt.Valid {
+		rsp.ReleasedAt = timestamppb.New(hold.ReleasedAt.Time)
+	}
+	return rsp
+}
+
+func convertScheduledTransfer(scheduled db.ScheduledTransfer) *pb.ScheduledTransfer {
+	rsp := &pb.ScheduledTransfer{
+		Id:            scheduled.ID,
+		FromAccountId: scheduled.FromAccountID,
+		ToAccountId:   scheduled.ToAccountID,
+		Amount:        scheduled.Amount,
+		Schedule:      scheduled.Schedule,
+		NextRunAt:     timestamppb.New(scheduled.NextRunAt),
+		FailurePolicy: scheduled.FailurePolicy,
+		Status:        scheduled.Status,
+		CreatedAt:     timestamppb.New(scheduled.CreatedAt),
+	}
+	if scheduled.EndAt.Valid {
+		rsp.EndAt = timestamppb.New(scheduled.EndAt.Time)
+	}
+	return rsp
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	rsp := &pb.ScheduledTransferRun{
+		Id:                  run.ID,
+		ScheduledTransferId: run.ScheduledTransferID,
+		ScheduledFor:        timestamppb.New(run.ScheduledFor),
+		Status:              run.Status,
+		CreatedAt:           timestamppb.New(run.CreatedAt),
+	}
+	if run.TransferID.Valid {
+		rsp.TransferId = &run.TransferID.Int64
+	}
+	if run.Error.Valid {
+		rsp.Error = &run.Error.String
+	}
+	return rsp
+}
+
+func convertPayrollJob(job db.PayrollJob) *pb.PayrollJob {
+	rsp := &pb.PayrollJob{
+		Id:            job.ID,
+		Owner:         job.Owner,
+		FromAccountId: job.FromAccountID,
+		Currency:      job.Currency,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		UpdatedAt:     timestamppb.New(job.UpdatedAt),
+		CreatedAt:     timestamppb.New(job.CreatedAt),
+	}
+	if job.Error.Valid {
+		rsp.Error = &job.Error.String
+	}
+	return rsp
+}
+
+func convertTransferLimit(limit db.TransferLimit) *pb.TransferLimit {
+	return &pb.TransferLimit{
+		Username:    limit.Username.String,
+		Currency:    limit.Currency,
+		PerTransfer: limit.PerTransfer,
+		Daily:       limit.Daily,
+		Monthly:     limit.Monthly,
+	}
+}
+
+func convertFeeSchedule(schedule db.FeeSchedule) *pb.FeeSchedule {
+	return &pb.FeeSchedule{
+		Id:               schedule.ID,
+		Currency:         schedule.Currency,
+		Operation:        schedule.Operation,
+		FlatFee:          schedule.FlatFee,
+		PercentageBps:    schedule.PercentageBps,
+		FreePerMonth:     schedule.FreePerMonth,
+		RevenueAccountId: schedule.RevenueAccountID,
+		CreatedAt:        timestamppb.New(schedule.CreatedAt),
+	}
+}
+
+func convertInterestRate(rate db.InterestRate) *pb.InterestRate {
+	return &pb.InterestRate{
+		Id:               rate.ID,
+		Currency:         rate.Currency,
+		AccountType:      rate.AccountType,
+		AnnualRate:       rate.AnnualRate,
+		DayCount:         rate.DayCount,
+		ExpenseAccountId: rate.ExpenseAccountID,
+		CreatedAt:        timestamppb.New(rate.CreatedAt),
+	}
+}
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:            account.ID,
+		Owner:         account.Owner,
+		Balance:       account.Balance,
+		Currency:      account.Currency,
+		Type:          account.Type,
+		Status:        account.Status,
+		CreditsFrozen: account.CreditsFrozen,
+		CreatedAt:     timestamppb.New(account.CreatedAt),
+	}
+}
+
+func convertAccountStatusChange(change db.AccountStatusChange) *pb.AccountStatusChange {
+	rsp := &pb.AccountStatusChange{
+		Id:         change.ID,
+		AccountId:  change.AccountID,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Reason:     change.Reason,
+		ChangedBy:  change.ChangedBy,
+		CreatedAt:  timestamppb.New(change.CreatedAt),
+	}
+	if change.SweepTransferID.Valid {
+		rsp.SweepTransferId = &change.SweepTransferID.Int64
+	}
+	return rsp
+}
+
+func convertDailyBalance(balance db.DailyBalance) *pb.DailyBalance {
+	return &pb.DailyBalance{
+		Date:    timestamppb.New(balance.Date),
+		Balance: balance.Balance,
+	}
+}
+
+func convertWebhookEndpoint(endpoint db.WebhookEndpoint) *pb.WebhookEndpoint {
+	return &pb.WebhookEndpoint{
+		Id:         endpoint.ID,
+		Owner:      endpoint.Owner,
+		Url:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		CreatedAt:  timestamppb.New(endpoint.CreatedAt),
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:        entry.ID,
+		AccountId: entry.AccountID,
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertAuditEvent(event db.AuditEvent) (*pb.AuditEvent, error) {
+	before, err := convertAuditFields(event.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := convertAuditFields(event.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AuditEvent{
+		Id:         event.ID,
+		Actor:      event.Actor,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetId:   event.TargetID,
+		Outcome:    event.Outcome,
+		Reason:     event.Reason,
+		ClientIp:   event.ClientIp,
+		UserAgent:  event.UserAgent,
+		Before:     before,
+		After:      after,
+		CreatedAt:  timestamppb.New(event.CreatedAt),
+	}, nil
+}
+
+func convertAuditFields(data json.RawMessage) (*structpb.Struct, error) {
+	fields := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, fields); err != nil {
+		return nil, err
 	}
+	return fields, nil
 }
@@ -1,11 +1,22 @@
 package gapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jasonwebb3152/simplebank/apperr"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
-	"google.golang.org/grpc/codes"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
+var errPermissionDenied = errors.New("permission denied")
+
 func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
 	return &errdetails.BadRequest_FieldViolation{
 		Field:       field,
@@ -14,17 +25,97 @@ func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolati
 }
 
 func InvalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) error {
-	badRequest := &errdetails.BadRequest{FieldViolations: violations}
-	statusInvalid := status.New(codes.InvalidArgument, "invalid parameters")
+	appViolations := make([]apperr.Violation, len(violations))
+	for i, violation := range violations {
+		appViolations[i] = apperr.Violation{
+			Field:       violation.GetField(),
+			Description: violation.GetDescription(),
+		}
+	}
+	return apperr.InvalidArgument(appViolations...)
+}
+
+// unauthenticatedError reports a failed authorizeUser: a missing role is
+// PERMISSION_DENIED, an expired or invalid token gets its own code.
+func unauthenticatedError(err error) error {
+	if errors.Is(err, errPermissionDenied) {
+		return apperr.New(apperr.CodePermissionDenied, "permission denied")
+	}
+
+	appErr := apperr.From(err)
+	if appErr.Code == apperr.CodeInternal {
+		return apperr.Wrap(apperr.CodeUnauthenticated, err, fmt.Sprintf("unauthorized: %s", err))
+	}
+	appErr.Message = fmt.Sprintf("unauthorized: %s", err)
+	return appErr
+}
+
+func invalidCredentialsError() error {
+	return apperr.New(apperr.CodeInvalidCredentials, "incorrect username or password")
+}
 
-	statusDetails, err := statusInvalid.WithDetails(badRequest)
+// GrpcErrors is a unary interceptor giving every error returned by the handlers its
+// stable code. It must come after GrpcLogger so the logs still show the cause of
+// the errors whose details are hidden from clients, and after GrpcMetrics so the
+// metrics count the codes clients get.
+func GrpcErrors(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	result, err := handler(ctx, req)
 	if err != nil {
-		return statusInvalid.Err()
+		return result, apperr.From(err)
 	}
+	return result, nil
+}
+
+// GrpcStreamErrors is GrpcErrors for streaming RPCs.
+func GrpcStreamErrors(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	err := handler(srv, stream)
+	if err != nil {
+		return apperr.From(err)
+	}
+	return nil
+}
 
-	return statusDetails.Err()
+// HttpErrorHandler writes the errors of the gateway as problem details (RFC 9457).
+// The default handler still does the rest, such as forwarding the rate limit headers.
+func HttpErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	marshaler runtime.Marshaler,
+	res http.ResponseWriter,
+	req *http.Request,
+	err error,
+) {
+	problem := problemMarshaler{Marshaler: marshaler, instance: req.URL.Path}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, problem, res, req, err)
 }
 
-func unauthenticatedError(err error) error {
-	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+// problemMarshaler turns the status the default handler writes into problem details
+type problemMarshaler struct {
+	runtime.Marshaler
+	instance string
+}
+
+func (marshaler problemMarshaler) ContentType(v any) string {
+	if _, ok := v.(*spb.Status); ok {
+		return apperr.ContentTypeProblem
+	}
+	return marshaler.Marshaler.ContentType(v)
+}
+
+func (marshaler problemMarshaler) Marshal(v any) ([]byte, error) {
+	st, ok := v.(*spb.Status)
+	if !ok {
+		return marshaler.Marshaler.Marshal(v)
+	}
+	return json.Marshal(apperr.FromStatus(status.FromProto(st)).Problem(marshaler.instance))
 }
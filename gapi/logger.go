@@ -2,10 +2,14 @@ package gapi
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/jasonwebb3152/simplebank/apperr"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -30,6 +34,11 @@ func GrpcLogger(
 	logger := log.Info()
 	if err != nil {
 		logger = log.Error().Err(err)
+
+		var appErr *apperr.Error
+		if errors.As(err, &appErr) {
+			logger = logger.Str("error_code", string(appErr.Code))
+		}
 	}
 
 	logger.
@@ -38,10 +47,23 @@ func GrpcLogger(
 		Str("protocol", "grpc").
 		Str("method", info.FullMethod).
 		Dur("duration", duration).
+		Func(withTraceID(ctx)).
 		Msg("received a gRPC request")
 	return result, err
 }
 
+// withTraceID adds the IDs of the request's span so log lines can be matched to traces
+func withTraceID(ctx context.Context) func(*zerolog.Event) {
+	return func(event *zerolog.Event) {
+		spanContext := trace.SpanContextFromContext(ctx)
+		if spanContext.IsValid() {
+			event.
+				Str("trace_id", spanContext.TraceID().String()).
+				Str("span_id", spanContext.SpanID().String())
+		}
+	}
+}
+
 type ResponseRecorder struct {
 	http.ResponseWriter
 	StatusCode int
@@ -58,6 +80,13 @@ func (rec *ResponseRecorder) Write(body []byte) (int, error) {
 	return rec.ResponseWriter.Write(body)
 }
 
+// Flush lets streaming responses reach the client as they are written
+func (rec *ResponseRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func HttpLogger(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		startTime := time.Now()
@@ -80,6 +109,7 @@ func HttpLogger(handler http.Handler) http.Handler {
 			Dur("duration", duration).
 			Int("status_code", rec.StatusCode).
 			Str("status_text", http.StatusText(rec.StatusCode)).
+			Func(withTraceID(req.Context())).
 			Msg("received a HTTP request")
 	})
 }
@@ -2,6 +2,9 @@ package gapi
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -11,34 +14,114 @@ const (
 	grpcGatewayUserAgent = "grpcgateway-user-agent"
 	grpcGatewayIp        = "x-forwarded-for"
 	grpcCallUserAgent    = "user-agent"
-	grpcCallIp           = "authority"
 )
 
 type Metadata struct {
 	UserAgent string
-	ClientIP  string
+	// Without the port
+	ClientIP string
 }
 
 func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
 
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if agents := md.Get(grpcGatewayUserAgent); len(agents) > 0 {
-			mtdt.UserAgent = agents[0]
+	var peerIP net.IP
+	if p, ok := peer.FromContext(ctx); ok {
+		mtdt.ClientIP = addrHost(p.Addr.String())
+		peerIP = net.ParseIP(mtdt.ClientIP)
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return mtdt
+	}
+
+	// The gateway calls with its own user agent and passes on the client's
+	mtdt.UserAgent = firstValue(md, grpcGatewayUserAgent)
+	if mtdt.UserAgent == "" {
+		mtdt.UserAgent = firstValue(md, grpcCallUserAgent)
+	}
+
+	// Only the configured proxies, such as the gateway, are believed. Each one appends
+	// the address it saw to X-Forwarded-For, so the client is the last entry that
+	// isn't a trusted proxy itself: anything before it was sent by the client and
+	// can't be trusted. Other callers could send the header too, it is ignored.
+	if !server.isTrustedProxy(peerIP) {
+		return mtdt
+	}
+
+	var entries []string
+	for _, value := range md.Get(grpcGatewayIp) {
+		entries = append(entries, strings.Split(value, ",")...)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := net.ParseIP(addrHost(strings.TrimSpace(entries[i])))
+		if ip == nil {
+			break
+		}
+		mtdt.ClientIP = ip.String()
+		if !server.isTrustedProxy(ip) {
+			break
 		}
+	}
 
-		if agents := md.Get(grpcCallUserAgent); len(agents) > 0 {
-			mtdt.UserAgent = agents[0]
+	return mtdt
+}
+
+func (server *Server) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range server.trustedProxies {
+		if network.Contains(ip) {
+			return true
 		}
+	}
+	return false
+}
 
-		if ips := md.Get("x-forwarded-for"); len(ips) > 0 {
-			mtdt.ClientIP = ips[0]
+// parseTrustedProxies parses TRUSTED_PROXIES, a list of addresses and CIDR ranges
+// separated by commas, the same way the HTTP server reads it.
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
 		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
 	}
+	return networks, nil
+}
 
-	if p, ok := peer.FromContext(ctx); ok {
-		mtdt.ClientIP = p.Addr.String()
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
 	}
+	return ""
+}
 
-	return mtdt
+func addrHost(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
 }
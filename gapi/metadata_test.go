@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadataClientIP(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies string
+		peer           string
+		forwarded      []string
+		clientIP       string
+	}{
+		{"NoHeader", "127.0.0.1", "127.0.0.1", nil, "127.0.0.1"},
+		{"TrustedGateway", "127.0.0.1,::1", "127.0.0.1", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"TrustedRange", "10.0.0.0/8", "10.1.2.3", []string{"203.0.113.7"}, "203.0.113.7"},
+		// The client prepended a fake address, the gateway appended the real one
+		{"SpoofedByClient", "127.0.0.1", "127.0.0.1", []string{"198.51.100.1, 203.0.113.7"}, "203.0.113.7"},
+		// A proxy in front of the gateway appended the client before the gateway appended it
+		{"ProxyChain", "127.0.0.1,10.0.0.0/8", "127.0.0.1", []string{"198.51.100.1, 203.0.113.7, 10.0.0.5"}, "203.0.113.7"},
+		// Being local is not enough, the peer must be configured
+		{"UntrustedLoopback", "", "127.0.0.1", []string{"203.0.113.7"}, "127.0.0.1"},
+		{"UntrustedPeer", "127.0.0.1", "198.51.100.9", []string{"203.0.113.7"}, "198.51.100.9"},
+		{"Garbage", "127.0.0.1", "127.0.0.1", []string{"not-an-address"}, "127.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trustedProxies, err := parseTrustedProxies(tc.trustedProxies)
+			require.NoError(t, err)
+			server := &Server{trustedProxies: trustedProxies}
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.ParseIP(tc.peer), Port: 50000},
+			})
+			ctx = metadata.NewIncomingContext(ctx, metadata.MD{grpcGatewayIp: tc.forwarded})
+
+			require.Equal(t, tc.clientIP, server.extractMetadata(ctx).ClientIP)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	networks, err := parseTrustedProxies(" 127.0.0.1, ,::1,10.0.0.0/8 ")
+	require.NoError(t, err)
+	require.Len(t, networks, 3)
+
+	for _, value := range []string{"not-an-address", "10.0.0.0/33"} {
+		_, err := parseTrustedProxies(value)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,107 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "grpc_requests_total",
+		Help:      "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "simplebank",
+		Name:      "grpc_request_duration_seconds",
+		Help:      "How long gRPC calls took, streams included.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "http_requests_total",
+		Help:      "HTTP gateway requests by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "simplebank",
+		Name:      "http_request_duration_seconds",
+		Help:      "How long HTTP gateway requests took.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	loginFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "login_failures_total",
+		Help:      "Rejected logins by reason.",
+	}, []string{"reason"})
+
+	rateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "simplebank",
+		Name:      "rate_limited_total",
+		Help:      "Calls rejected by the rate limiter by method.",
+	}, []string{"method"})
+)
+
+func observeGrpcRequest(method string, err error, duration time.Duration) {
+	code := status.Code(err).String()
+	grpcRequestsTotal.WithLabelValues(method, code).Inc()
+	grpcRequestDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+func GrpcMetrics(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	startTime := time.Now()
+	result, err := handler(ctx, req)
+	observeGrpcRequest(info.FullMethod, err, time.Since(startTime))
+	return result, err
+}
+
+func GrpcStreamMetrics(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	err := handler(srv, stream)
+	observeGrpcRequest(info.FullMethod, err, time.Since(startTime))
+	return err
+}
+
+// HttpMetrics is a gateway middleware, so requests are labelled by the route they
+// matched instead of their path with IDs in it.
+func HttpMetrics(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		startTime := time.Now()
+		rec := &ResponseRecorder{
+			ResponseWriter: res,
+			StatusCode:     http.StatusOK,
+		}
+		next(rec, req, pathParams)
+		duration := time.Since(startTime)
+
+		route := "unknown"
+		if pattern, ok := runtime.HTTPPattern(req.Context()); ok {
+			route = pattern.String()
+		}
+
+		code := strconv.Itoa(rec.StatusCode)
+		httpRequestsTotal.WithLabelValues(req.Method, route, code).Inc()
+		httpRequestDuration.WithLabelValues(req.Method, route, code).Observe(duration.Seconds())
+	}
+}
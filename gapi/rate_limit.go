@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimit is a unary interceptor applying the limit configured for each method of
+// the SimpleBank service. Signed in users are limited by username, everyone else by
+// client IP. The rate limit headers are sent with every limited response.
+func (server *Server) RateLimit(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, "/"+pb.SimpleBank_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	method := path.Base(info.FullMethod)
+	result, limited := server.limiter.Allow(ctx, method, server.rateLimitKey(ctx))
+	if !limited {
+		return handler(ctx, req)
+	}
+
+	md := metadata.MD{}
+	for key, value := range result.Headers() {
+		md.Set(key, value)
+	}
+	grpc.SetHeader(ctx, md)
+
+	if !result.Allowed {
+		rateLimitedTotal.WithLabelValues(method).Inc()
+		return nil, rateLimitedError(result.RetryAfter.Round(time.Second))
+	}
+	return handler(ctx, req)
+}
+
+func (server *Server) rateLimitKey(ctx context.Context) string {
+	// An invalid token is not an error here, the handler rejects it afterwards
+	if payload, err := server.authenticateUser(ctx); err == nil {
+		return "user:" + payload.Username
+	}
+
+	return "ip:" + server.extractMetadata(ctx).ClientIP
+}
+
+func rateLimitedError(retryAfter time.Duration) error {
+	statusLimited := status.Newf(codes.ResourceExhausted, "too many requests, retry in %s", retryAfter)
+
+	statusDetails, err := statusLimited.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if err != nil {
+		return statusLimited.Err()
+	}
+
+	return statusDetails.Err()
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) AuthorizeTransfer(ctx context.Context, req *pb.AuthorizeTransferRequest) (*pb.AuthorizeTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAuthorizeTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the owner of the money (or a banker) may reserve it
+	if authPayload.Role != util.BankerRole && fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	if _, err := server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency()); err != nil {
+		return nil, err
+	}
+
+	arg := db.AuthorizeTransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		TTL:           server.config.HoldTTL,
+	}
+
+	result, err := server.store.AuthorizeTransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || isAccountUnavailable(err) {
+			return nil, apperr.From(fmt.Errorf("cannot authorize transfer: %w", err))
+		}
+		return nil, fmt.Errorf("failed to authorize transfer: %w", err)
+	}
+
+	rsp := &pb.AuthorizeTransferResponse{
+		Hold:             convertAccountHold(result.Hold),
+		AvailableBalance: result.AvailableBalance,
+	}
+	return rsp, nil
+}
+
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account %d not found", accountID)
+		}
+		return account, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if account.Currency != currency {
+		return account, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch %s vs %s", account.ID, account.Currency, currency)
+	}
+	return account, nil
+}
+
+func validateAuthorizeTransferRequest(req *pb.AuthorizeTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must differ from from_account_id")))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return
+}
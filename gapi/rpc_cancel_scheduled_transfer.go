@@ -0,0 +1,49 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CancelScheduledTransfer(ctx context.Context, req *pb.CancelScheduledTransferRequest) (*pb.CancelScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateScheduledTransferID(req.GetId())
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeScheduledTransferOwner(ctx, authPayload, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := server.store.UpdateScheduledTransferStatus(ctx, db.UpdateScheduledTransferStatusParams{
+		ID:     req.GetId(),
+		Status: db.ScheduledTransferStatusCancelled,
+		FromStatuses: []string{
+			db.ScheduledTransferStatusActive,
+			db.ScheduledTransferStatusPaused,
+		},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer has already finished")
+		}
+		return nil, fmt.Errorf("failed to cancel scheduled transfer: %w", err)
+	}
+
+	rsp := &pb.CancelScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
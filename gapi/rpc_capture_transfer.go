@@ -0,0 +1,105 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CaptureTransfer(ctx context.Context, req *pb.CaptureTransferRequest) (*pb.CaptureTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCaptureTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeHoldRecipient(ctx, authPayload, req.GetHoldId()); err != nil {
+		return nil, err
+	}
+
+	arg := db.CaptureTransferTxParams{
+		HoldID: req.GetHoldId(),
+		Amount: req.GetAmount(),
+	}
+
+	result, err := server.store.CaptureTransferTx(ctx, arg)
+	if err != nil {
+		if isHoldStateError(err) || errors.Is(err, db.ErrCaptureExceedsHold) || isTransferRejected(err) {
+			return nil, apperr.From(fmt.Errorf("cannot capture transfer: %w", err))
+		}
+		return nil, fmt.Errorf("failed to capture transfer: %w", err)
+	}
+
+	rsp := &pb.CaptureTransferResponse{
+		Hold:     convertAccountHold(result.Hold),
+		Transfer: convertTransfer(result.Transfer),
+	}
+	return rsp, nil
+}
+
+func (server *Server) authorizeHoldRecipient(ctx context.Context, authPayload *token.Payload, holdID int64) error {
+	/** Only the account the money is reserved for (or a banker) may settle a hold. */
+	hold, err := server.store.GetAccountHold(ctx, holdID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "hold %d not found", holdID)
+		}
+		return fmt.Errorf("failed to find hold: %w", err)
+	}
+
+	if authPayload.Role == util.BankerRole {
+		return nil
+	}
+
+	// Payroll holds have no single recipient and are settled by their job
+	if !hold.ToAccountID.Valid {
+		return status.Errorf(codes.PermissionDenied, "only the recipient can settle this hold")
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, hold.ToAccountID.Int64)
+	if err != nil {
+		return fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if toAccount.Owner != authPayload.Username {
+		return status.Errorf(codes.PermissionDenied, "only the recipient can settle this hold")
+	}
+	return nil
+}
+
+func isTransferRejected(err error) bool {
+	/** The money of the hold could not move, as any transfer of the account's owner could fail. */
+	return errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrTransferLimitExceeded) || isAccountUnavailable(err)
+}
+
+func isHoldStateError(err error) bool {
+	return errors.Is(err, db.ErrHoldNotActive) || errors.Is(err, db.ErrHoldExpired) || errors.Is(err, db.ErrPayrollHold)
+}
+
+func validateCaptureTransferRequest(req *pb.CaptureTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetHoldId()); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreatePayrollJob(ctx context.Context, req *pb.CreatePayrollJobRequest) (*pb.CreatePayrollJobResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		return nil, InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("from_account_id", err)})
+	}
+
+	// Only the file's shape is checked here, the worker resolves the recipients
+	rows, fieldErrors, err := util.ParsePayrollCSV(strings.NewReader(req.GetCsvData()))
+	if err != nil {
+		return nil, InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("csv_data", err)})
+	}
+	if len(fieldErrors) > 0 {
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, fieldError := range fieldErrors {
+			field := fmt.Sprintf("csv_data[%d].%s", fieldError.Line, fieldError.Field)
+			violations = append(violations, fieldViolation(field, fieldError.Err))
+		}
+		return nil, InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, req.GetFromAccountId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account %d not found", req.GetFromAccountId())
+		}
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if authPayload.Role != util.BankerRole && fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	arg := db.CreatePayrollJobTxParams{
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		Currency:      fromAccount.Currency,
+		Rows:          make([]db.PayrollJobRowParams, len(rows)),
+	}
+	for i, row := range rows {
+		arg.Rows[i] = db.PayrollJobRowParams{
+			RowNumber:   int32(row.Line),
+			ToAccountID: row.ToAccountID,
+			Username:    row.Username,
+			Amount:      row.Amount,
+			Currency:    row.Currency,
+			Memo:        row.Memo,
+		}
+	}
+
+	job, err := server.store.CreatePayrollJobTx(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payroll job: %w", err)
+	}
+
+	rsp := &pb.CreatePayrollJobResponse{
+		Job: convertPayrollJob(job),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,118 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if authPayload.Role != util.BankerRole && fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	if _, err := server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency()); err != nil {
+		return nil, err
+	}
+
+	startAt := time.Now()
+	if req.StartAt != nil && req.GetStartAt().AsTime().After(startAt) {
+		startAt = req.GetStartAt().AsTime()
+	}
+
+	// The schedule was only validated against now: it may never fire after start_at
+	nextRunAt, err := util.NextScheduledRun(req.GetSchedule(), startAt)
+	if err != nil {
+		return nil, InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("start_at", err)})
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		Schedule:      req.GetSchedule(),
+		NextRunAt:     nextRunAt,
+		FailurePolicy: db.FailurePolicySkip,
+	}
+
+	if req.EndAt != nil {
+		endAt := req.GetEndAt().AsTime()
+		if endAt.Before(nextRunAt) {
+			return nil, status.Errorf(codes.InvalidArgument, "end_at is before the first occurrence at %s", nextRunAt)
+		}
+		arg.EndAt = sql.NullTime{
+			Time:  endAt,
+			Valid: true,
+		}
+	}
+
+	if req.FailurePolicy != nil {
+		arg.FailurePolicy = req.GetFailurePolicy()
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduled transfer: %w", err)
+	}
+
+	rsp := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateSchedule(req.GetSchedule()); err != nil {
+		violations = append(violations, fieldViolation("schedule", err))
+	}
+
+	if req.FailurePolicy != nil {
+		switch req.GetFailurePolicy() {
+		case db.FailurePolicySkip, db.FailurePolicyPause:
+		default:
+			err := fmt.Errorf("must be %s or %s", db.FailurePolicySkip, db.FailurePolicyPause)
+			violations = append(violations, fieldViolation("failure_policy", err))
+		}
+	}
+	return
+}
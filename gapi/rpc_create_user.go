@@ -2,27 +2,36 @@ package gapi
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/pb"
-	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/jasonwebb3152/simplebank/val"
 	"github.com/lib/pq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	violations := validateCreateUserRequest(req)
+	passwordViolations, err := server.checkPasswordPolicy(req.GetPassword(), password.PolicyUser{
+		Username: req.GetUsername(),
+		Email:    req.GetEmail(),
+		FullName: req.GetFullName(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check password: %w", err)
+	}
+	violations = append(violations, passwordViolations...)
 	if violations != nil {
 		return nil, InvalidArgumentError(violations)
 	}
 
 	// using getters is safer
-	hashedPassword, err := util.HashPassword(req.GetPassword())
+	hashedPassword, err := server.passwords.Hash(req.GetPassword())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	arg := db.CreateUserParams{
@@ -37,10 +46,10 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation": // unique username and email stuff
-				return nil, status.Errorf(codes.AlreadyExists, "username already exists: %s", err)
+				return nil, apperr.Wrap(apperr.CodeAlreadyExists, err, "username or email already exists")
 			}
 		}
-		return nil, status.Errorf(codes.Internal, "failed to create user: %s", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	rsp := &pb.CreateUserResponse{
@@ -59,12 +68,20 @@ func validateCreateUserRequest(req *pb.CreateUserRequest) (violations []*errdeta
 		violations = append(violations, fieldViolation("email", err))
 	}
 
-	if err := val.ValidatePassword(req.GetPassword()); err != nil {
-		violations = append(violations, fieldViolation("password", err))
-	}
-
 	if err := val.ValidateFullName(req.GetFullName()); err != nil {
 		violations = append(violations, fieldViolation("full_name", err))
 	}
 	return
 }
+
+func (server *Server) checkPasswordPolicy(plain string, user password.PolicyUser) (violations []*errdetails.BadRequest_FieldViolation, err error) {
+	/** Reports every rule of the password policy plain breaks as its own violation. */
+	errs, err := server.policy.Check(plain, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, violation := range errs {
+		violations = append(violations, fieldViolation("password", violation))
+	}
+	return violations, nil
+}
@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"github.com/jasonwebb3152/simplebank/webhook"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) CreateWebhookEndpoint(ctx context.Context, req *pb.CreateWebhookEndpointRequest) (*pb.CreateWebhookEndpointResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateWebhookEndpointRequest(req, server.config.WebhookAllowInsecure)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	arg := db.CreateWebhookEndpointParams{
+		Owner:      authPayload.Username,
+		Url:        req.GetUrl(),
+		Secret:     secret,
+		EventTypes: req.GetEventTypes(),
+	}
+	if arg.EventTypes == nil {
+		arg.EventTypes = []string{}
+	}
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	rsp := &pb.CreateWebhookEndpointResponse{
+		Endpoint: convertWebhookEndpoint(endpoint),
+		Secret:   endpoint.Secret,
+	}
+	return rsp, nil
+}
+
+func validateCreateWebhookEndpointRequest(req *pb.CreateWebhookEndpointRequest, allowHTTP bool) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateWebhookURL(req.GetUrl(), allowHTTP); err != nil {
+		violations = append(violations, fieldViolation("url", err))
+	}
+
+	for i, eventType := range req.GetEventTypes() {
+		if err := val.ValidateEventType(eventType); err != nil {
+			violations = append(violations, fieldViolation(fmt.Sprintf("event_types[%d]", i), err))
+		}
+	}
+	return
+}
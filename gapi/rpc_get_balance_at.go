@@ -0,0 +1,56 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) GetBalanceAt(ctx context.Context, req *pb.GetBalanceAtRequest) (*pb.GetBalanceAtResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetBalanceAtRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeAccountOwner(ctx, authPayload, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.GetBalanceAtTx(ctx, db.GetBalanceAtTxParams{
+		AccountID: req.GetAccountId(),
+		At:        req.GetAt().AsTime(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	rsp := &pb.GetBalanceAtResponse{
+		AccountId: result.Account.ID,
+		Currency:  result.Account.Currency,
+		At:        req.GetAt(),
+		Balance:   result.Balance,
+	}
+	return rsp, nil
+}
+
+func validateGetBalanceAtRequest(req *pb.GetBalanceAtRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := req.GetAt().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("at", errors.New("is required")))
+	}
+	return
+}
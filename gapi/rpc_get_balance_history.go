@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) GetBalanceHistory(ctx context.Context, req *pb.GetBalanceHistoryRequest) (*pb.GetBalanceHistoryResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetBalanceHistoryRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeAccountOwner(ctx, authPayload, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.GetBalanceHistoryTx(ctx, db.GetBalanceHistoryTxParams{
+		AccountID: req.GetAccountId(),
+		From:      req.GetFrom().AsTime(),
+		To:        req.GetTo().AsTime(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance history: %w", err)
+	}
+
+	rsp := &pb.GetBalanceHistoryResponse{
+		AccountId: result.Account.ID,
+		Currency:  result.Account.Currency,
+		Balances:  make([]*pb.DailyBalance, len(result.Balances)),
+	}
+	for i, balance := range result.Balances {
+		rsp.Balances[i] = convertDailyBalance(balance)
+	}
+	return rsp, nil
+}
+
+func validateGetBalanceHistoryRequest(req *pb.GetBalanceHistoryRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := req.GetFrom().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("from", errors.New("is required")))
+	}
+
+	if err := req.GetTo().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("to", errors.New("is required")))
+	} else if err := val.ValidateBalanceHistoryPeriod(req.GetFrom().AsTime(), req.GetTo().AsTime()); err != nil {
+		violations = append(violations, fieldViolation("to", err))
+	}
+	return
+}
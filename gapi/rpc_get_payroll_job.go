@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Keeps the polling response small for files where most rows are broken
+const maxPayrollRowErrors = 100
+
+func (server *Server) GetPayrollJob(ctx context.Context, req *pb.GetPayrollJobRequest) (*pb.GetPayrollJobResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetPayrollJobRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	job, err := server.store.GetPayrollJob(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "payroll job %d not found", req.GetId())
+		}
+		return nil, fmt.Errorf("failed to find payroll job: %w", err)
+	}
+
+	if authPayload.Role != util.BankerRole && job.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "payroll job doesn't belong to the authenticated user")
+	}
+
+	rowErrors, err := server.store.ListPayrollJobRowErrors(ctx, db.ListPayrollJobRowErrorsParams{
+		JobID: job.ID,
+		Limit: maxPayrollRowErrors,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll row errors: %w", err)
+	}
+
+	rsp := &pb.GetPayrollJobResponse{
+		Job: convertPayrollJob(job),
+	}
+	for _, row := range rowErrors {
+		rsp.RowErrors = append(rsp.RowErrors, &pb.PayrollRowError{
+			RowNumber: row.RowNumber,
+			Error:     row.Error.String,
+		})
+	}
+	return rsp, nil
+}
+
+func validateGetPayrollJobRequest(req *pb.GetPayrollJobRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/statement"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*httpbody.HttpBody, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetStatementRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeAccountOwner(ctx, authPayload, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.GetStatementTx(ctx, db.GetStatementTxParams{
+		AccountID: req.GetAccountId(),
+		From:      req.GetFrom().AsTime(),
+		To:        req.GetTo().AsTime(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := statement.Render(&buf, result, req.GetFormat()); err != nil {
+		return nil, fmt.Errorf("failed to render statement: %w", err)
+	}
+
+	// The gateway turns this into a Content-Disposition header so browsers save the file
+	disposition := fmt.Sprintf("attachment; filename=%q", statement.Filename(result, req.GetFormat()))
+	if err := grpc.SetHeader(ctx, metadata.Pairs("content-disposition", disposition)); err != nil {
+		return nil, fmt.Errorf("failed to set header: %w", err)
+	}
+
+	rsp := &httpbody.HttpBody{
+		ContentType: statement.ContentType(req.GetFormat()),
+		Data:        buf.Bytes(),
+	}
+	return rsp, nil
+}
+
+func (server *Server) authorizeAccountOwner(ctx context.Context, authPayload *token.Payload, accountID int64) error {
+	/** Only the owner of an account (or a banker) may look into it. */
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "account %d not found", accountID)
+		}
+		return fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if authPayload.Role != util.BankerRole && account.Owner != authPayload.Username {
+		return status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	return nil
+}
+
+func validateGetStatementRequest(req *pb.GetStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := req.GetFrom().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("from", errors.New("is required")))
+	}
+
+	if err := req.GetTo().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("to", errors.New("is required")))
+	} else if err := val.ValidateStatementPeriod(req.GetFrom().AsTime(), req.GetTo().AsTime()); err != nil {
+		violations = append(violations, fieldViolation("to", err))
+	}
+
+	if !statement.IsSupportedFormat(req.GetFormat()) {
+		violations = append(violations, fieldViolation("format", fmt.Errorf("unsupported format %q", req.GetFormat())))
+	}
+	return
+}
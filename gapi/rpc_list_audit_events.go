@@ -0,0 +1,119 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+const defaultAuditPageSize = 50
+
+func (server *Server) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAuditEventsRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = defaultAuditPageSize
+	}
+
+	// Pages are keyed on the id of the last event seen, so events appended while
+	// paging don't shift the pages that follow
+	arg := auditEventFilterParams(req.GetFilter())
+	arg.PageSize = pageSize
+	if req.GetPageToken() != "" {
+		beforeID, _ := strconv.ParseInt(req.GetPageToken(), 10, 64)
+		arg.BeforeID = sql.NullInt64{Int64: beforeID, Valid: true}
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	rsp := &pb.ListAuditEventsResponse{}
+	for _, event := range events {
+		auditEvent, err := convertAuditEvent(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert audit event: %w", err)
+		}
+		rsp.AuditEvents = append(rsp.AuditEvents, auditEvent)
+	}
+	if len(events) == int(pageSize) {
+		rsp.NextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+	return rsp, nil
+}
+
+func auditEventFilterParams(filter *pb.AuditEventFilter) db.ListAuditEventsParams {
+	/** Empty fields of the filter match every event. */
+	arg := db.ListAuditEventsParams{
+		Actor:      sql.NullString{String: filter.GetActor(), Valid: filter.GetActor() != ""},
+		Action:     sql.NullString{String: filter.GetAction(), Valid: filter.GetAction() != ""},
+		TargetType: sql.NullString{String: filter.GetTargetType(), Valid: filter.GetTargetType() != ""},
+		TargetID:   sql.NullString{String: filter.GetTargetId(), Valid: filter.GetTargetId() != ""},
+		Outcome:    sql.NullString{String: filter.GetOutcome(), Valid: filter.GetOutcome() != ""},
+	}
+	if filter.GetFrom() != nil {
+		arg.FromTime = sql.NullTime{Time: filter.GetFrom().AsTime(), Valid: true}
+	}
+	if filter.GetTo() != nil {
+		arg.ToTime = sql.NullTime{Time: filter.GetTo().AsTime(), Valid: true}
+	}
+	return arg
+}
+
+func validateListAuditEventsRequest(req *pb.ListAuditEventsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateAuditEventFilter(req.GetFilter())
+
+	if req.GetPageSize() != 0 {
+		if err := val.ValidateAuditPageSize(req.GetPageSize()); err != nil {
+			violations = append(violations, fieldViolation("page_size", err))
+		}
+	}
+
+	if req.GetPageToken() != "" {
+		if beforeID, err := strconv.ParseInt(req.GetPageToken(), 10, 64); err != nil || beforeID < 1 {
+			violations = append(violations, fieldViolation("page_token", errors.New("invalid page token")))
+		}
+	}
+	return
+}
+
+func validateAuditEventFilter(filter *pb.AuditEventFilter) (violations []*errdetails.BadRequest_FieldViolation) {
+	if filter.GetOutcome() != "" {
+		if err := val.ValidateAuditOutcome(filter.GetOutcome()); err != nil {
+			violations = append(violations, fieldViolation("filter.outcome", err))
+		}
+	}
+
+	if filter.GetFrom() != nil {
+		if err := filter.GetFrom().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("filter.from", errors.New("invalid timestamp")))
+		}
+	}
+
+	if filter.GetTo() != nil {
+		if err := filter.GetTo().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("filter.to", errors.New("invalid timestamp")))
+		} else if filter.GetFrom() != nil && filter.GetTo().AsTime().Before(filter.GetFrom().AsTime()) {
+			violations = append(violations, fieldViolation("filter.to", errors.New("must not be before from")))
+		}
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) ListScheduledTransferRuns(ctx context.Context, req *pb.ListScheduledTransferRunsRequest) (*pb.ListScheduledTransferRunsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransferRunsRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeScheduledTransferOwner(ctx, authPayload, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: req.GetId(),
+		Limit:               req.GetPageSize(),
+		Offset:              (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transfer runs: %w", err)
+	}
+
+	rsp := &pb.ListScheduledTransferRunsResponse{}
+	for _, run := range runs {
+		rsp.Runs = append(rsp.Runs, convertScheduledTransferRun(run))
+	}
+	return rsp, nil
+}
+
+func validateListScheduledTransferRunsRequest(req *pb.ListScheduledTransferRunsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateScheduledTransferID(req.GetId())
+
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
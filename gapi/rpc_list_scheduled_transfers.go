@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransfersRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	owner := authPayload.Username
+	if req.GetOwner() != "" && req.GetOwner() != authPayload.Username {
+		if authPayload.Role != util.BankerRole {
+			return nil, status.Errorf(codes.PermissionDenied, "cannot list other user's scheduled transfers")
+		}
+		owner = req.GetOwner()
+	}
+
+	arg := db.ListScheduledTransfersParams{
+		Owner:  owner,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transfers: %w", err)
+	}
+
+	rsp := &pb.ListScheduledTransfersResponse{}
+	for _, scheduled := range scheduledTransfers {
+		rsp.ScheduledTransfers = append(rsp.ScheduledTransfers, convertScheduledTransfer(scheduled))
+	}
+	return rsp, nil
+}
+
+func validateListScheduledTransfersRequest(req *pb.ListScheduledTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	if owner := req.GetOwner(); owner != "" {
+		if err := val.ValidateUsername(owner); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+	return
+}
@@ -3,14 +3,16 @@ package gapi
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/pb"
 	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/jasonwebb3152/simplebank/val"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -20,39 +22,60 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, InvalidArgumentError(violations)
 	}
 
+	// An unknown username and a wrong password get the same answer, in the same
+	// time, so logins can't be used to find out which usernames exist
 	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "user with username %s not found", req.GetUsername())
+			server.passwords.CheckUnknownUser(req.GetPassword())
+			loginFailuresTotal.WithLabelValues(util.AuditReasonUnknownUser).Inc()
+			return nil, server.loginFailed(ctx, req.GetUsername(), util.AuditReasonUnknownUser)
 		}
-		return nil, status.Errorf(codes.Internal, "failed to find user: %v", err)
+		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	err = util.CheckPassword(req.GetPassword(), user.HashedPassword)
+	// System users own the bank's own accounts, nobody can log in as them
+	if user.Role == util.SystemRole {
+		server.passwords.CheckUnknownUser(req.GetPassword())
+		loginFailuresTotal.WithLabelValues(util.AuditReasonUnknownUser).Inc()
+		return nil, server.loginFailed(ctx, req.GetUsername(), util.AuditReasonUnknownUser)
+	}
+
+	needsRehash, err := server.passwords.Check(req.GetPassword(), user.HashedPassword)
 	if err != nil {
-		return nil, status.Errorf(codes.PermissionDenied, "error checking password: %v", err)
+		if errors.Is(err, password.ErrMismatchedPassword) {
+			loginFailuresTotal.WithLabelValues(util.AuditReasonWrongPassword).Inc()
+			return nil, server.loginFailed(ctx, user.Username, util.AuditReasonWrongPassword)
+		}
+		return nil, fmt.Errorf("failed to check password: %w", err)
+	}
+
+	if needsRehash {
+		server.rehashPassword(ctx, user, req.GetPassword())
 	}
 
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create access token: %v", err)
+		return nil, fmt.Errorf("failed to create access token: %w", err)
 	}
 
 	// Create refresh token in the same way
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %v", err)
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
 	}
 
 	mtdt := server.extractMetadata(ctx)
 
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+	session, err := server.store.CreateSessionTx(ctx, db.CreateSessionParams{
 		ID:           refreshPayload.ID,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
@@ -62,7 +85,7 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		ExpiresAt:    refreshPayload.ExpiredAt,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create session db entry: %v", err)
+		return nil, fmt.Errorf("failed to create session db entry: %w", err)
 	}
 
 	rsp := &pb.LoginUserResponse{
@@ -77,6 +100,38 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 	return rsp, nil
 }
 
+func (server *Server) rehashPassword(ctx context.Context, user db.User, plain string) {
+	/** Replaces a hash made with an outdated algorithm or costs, now that the
+	password is known. The login goes on if it fails, the next one tries again. */
+	hashedPassword, err := server.passwords.Hash(plain)
+	if err == nil {
+		_, err = server.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+			NewHashedPassword: hashedPassword,
+			Username:          user.Username,
+			OldHashedPassword: user.HashedPassword,
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("username", user.Username).Msg("failed to rehash password")
+	}
+}
+
+func (server *Server) loginFailed(ctx context.Context, username string, reason string) error {
+	/** Audits a failed login. The audit log keeps the reason, the client only learns
+	that the credentials are wrong. The caller stays anonymous: nobody proved to be
+	username, which is only the target. */
+	err := server.store.RecordAuditFailure(ctx, db.AuditRecord{
+		Action:     util.AuditUserLogin,
+		TargetType: util.AuditTargetUser,
+		TargetID:   username,
+		Reason:     reason,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to audit login: %w", err)
+	}
+	return invalidCredentialsError()
+}
+
 func validateLoginUserRequest(req *pb.LoginUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
 	if err := val.ValidateUsername(req.GetUsername()); err != nil {
 		violations = append(violations, fieldViolation("username", err))
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) PauseScheduledTransfer(ctx context.Context, req *pb.PauseScheduledTransferRequest) (*pb.PauseScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateScheduledTransferID(req.GetId())
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeScheduledTransferOwner(ctx, authPayload, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := server.store.UpdateScheduledTransferStatus(ctx, db.UpdateScheduledTransferStatusParams{
+		ID:           req.GetId(),
+		Status:       db.ScheduledTransferStatusPaused,
+		FromStatuses: []string{db.ScheduledTransferStatusActive},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "only active scheduled transfers can be paused")
+		}
+		return nil, fmt.Errorf("failed to pause scheduled transfer: %w", err)
+	}
+
+	rsp := &pb.PauseScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
+
+func (server *Server) authorizeScheduledTransferOwner(ctx context.Context, authPayload *token.Payload, id int64) error {
+	/** Only the owner of the paying account (or a banker) may manage a schedule. */
+	scheduled, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "scheduled transfer %d not found", id)
+		}
+		return fmt.Errorf("failed to find scheduled transfer: %w", err)
+	}
+
+	if authPayload.Role == util.BankerRole {
+		return nil
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to find account: %w", err)
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+	return nil
+}
+
+func validateScheduledTransferID(id int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(id); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	if session.IsBlocked {
+		return nil, apperr.New(apperr.CodeUnauthenticated, "blocked session")
+	}
+
+	if session.Username != refreshPayload.Username {
+		return nil, apperr.New(apperr.CodeUnauthenticated, "incorrect session user")
+	}
+
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, apperr.New(apperr.CodeUnauthenticated, "incorrect refresh token")
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, apperr.New(apperr.CodeUnauthenticated, "expired session")
+	}
+
+	if err := server.checkSessionRisk(ctx, session); err != nil {
+		return nil, err
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		refreshPayload.Username,
+		refreshPayload.Role,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	rsp := &pb.RenewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: timestamppb.New(accessPayload.ExpiredAt),
+	}
+	return rsp, nil
+}
+
+func (server *Server) checkSessionRisk(ctx context.Context, session db.Session) error {
+	/** Applies the session risk policy when the session is renewed from another
+	client than the one that logged in. */
+	mtdt := server.extractMetadata(ctx)
+	signals := util.SessionRiskSignals(session.ClientIp, session.UserAgent, mtdt.ClientIP, mtdt.UserAgent)
+	if len(signals) == 0 {
+		return nil
+	}
+
+	_, err := server.store.RecordSuspiciousSessionTx(ctx, db.RecordSuspiciousSessionTxParams{
+		Session:   session,
+		ClientIP:  mtdt.ClientIP,
+		UserAgent: mtdt.UserAgent,
+		Signals:   signals,
+		Policy:    server.config.SessionRiskPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record suspicious session: %w", err)
+	}
+
+	switch server.config.SessionRiskPolicy {
+	case util.SessionRiskBlock:
+		return apperr.New(apperr.CodeUnauthenticated, "blocked session")
+	case util.SessionRiskReauth:
+		return apperr.New(apperr.CodeUnauthenticated, "session used from another client, log in again")
+	}
+	return nil
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violations = append(violations, fieldViolation("refresh_token", errors.New("is required")))
+	}
+	return
+}
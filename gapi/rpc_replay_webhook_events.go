@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReplayWebhookEvents(ctx context.Context, req *pb.ReplayWebhookEventsRequest) (*pb.ReplayWebhookEventsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReplayWebhookEventsRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	_, err = server.store.GetWebhookEndpoint(ctx, req.GetEndpointId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "webhook endpoint %d not found", req.GetEndpointId())
+		}
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
+	}
+
+	// Deliveries that already exist are reset, so dead-lettered events get a fresh set of attempts
+	replayed, err := server.store.ReplayWebhookEvents(ctx, db.ReplayWebhookEventsParams{
+		EndpointID: req.GetEndpointId(),
+		FromTime:   req.GetFrom().AsTime(),
+		ToTime:     req.GetTo().AsTime(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay webhook events: %w", err)
+	}
+
+	rsp := &pb.ReplayWebhookEventsResponse{
+		ReplayedEvents: replayed,
+	}
+	return rsp, nil
+}
+
+func validateReplayWebhookEventsRequest(req *pb.ReplayWebhookEventsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetEndpointId()); err != nil {
+		violations = append(violations, fieldViolation("endpoint_id", err))
+	}
+
+	if err := req.GetFrom().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("from", errors.New("is required")))
+	}
+
+	if err := req.GetTo().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("to", errors.New("is required")))
+	} else if err := val.ValidateStatementPeriod(req.GetFrom().AsTime(), req.GetTo().AsTime()); err != nil {
+		violations = append(violations, fieldViolation("to", err))
+	}
+	return
+}
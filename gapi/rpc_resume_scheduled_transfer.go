@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ResumeScheduledTransfer(ctx context.Context, req *pb.ResumeScheduledTransferRequest) (*pb.ResumeScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateScheduledTransferID(req.GetId())
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeScheduledTransferOwner(ctx, authPayload, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := server.store.GetScheduledTransfer(ctx, req.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scheduled transfer: %w", err)
+	}
+
+	// Occurrences missed while paused are not paid afterwards
+	nextRunAt, err := util.NextScheduledRun(scheduled.Schedule, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute next run: %w", err)
+	}
+
+	scheduled, err = server.store.ResumeScheduledTransfer(ctx, db.ResumeScheduledTransferParams{
+		ID:        req.GetId(),
+		NextRunAt: nextRunAt,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "only paused scheduled transfers can be resumed")
+		}
+		return nil, fmt.Errorf("failed to resume scheduled transfer: %w", err)
+	}
+
+	rsp := &pb.ResumeScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer %d not found", req.GetTransferId())
+		}
+		return nil, fmt.Errorf("failed to find transfer: %w", err)
+	}
+
+	// Only the recipient of the money (or a banker) may send it back
+	if authPayload.Role != util.BankerRole {
+		toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find account: %w", err)
+		}
+
+		if toAccount.Owner != authPayload.Username {
+			return nil, status.Errorf(codes.PermissionDenied, "only the recipient can reverse this transfer")
+		}
+	}
+
+	arg := db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.GetAmount(),
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrTransferIsReversal) || errors.Is(err, db.ErrReversalExceedsTransfer) || isAccountUnavailable(err) {
+			return nil, apperr.From(fmt.Errorf("cannot reverse transfer: %w", err))
+		}
+		return nil, fmt.Errorf("failed to reverse transfer: %w", err)
+	}
+
+	rsp := &pb.ReverseTransferResponse{
+		Transfer:        convertTransfer(result.Transfer),
+		RemainingAmount: result.RemainingAmount,
+	}
+	return rsp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolation("transfer_id", err))
+	}
+
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+	return
+}
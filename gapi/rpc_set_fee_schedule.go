@@ -0,0 +1,82 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetFeeSchedule(ctx context.Context, req *pb.SetFeeScheduleRequest) (*pb.SetFeeScheduleResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetFeeScheduleRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	revenueAccount, err := server.validAccount(ctx, req.GetRevenueAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	// Fees are bank revenue, never paid into a customer's account
+	if revenueAccount.Owner != util.BankOwner || revenueAccount.Type != util.RevenueAccount {
+		return nil, status.Errorf(codes.FailedPrecondition, "account %d is not a bank revenue account", revenueAccount.ID)
+	}
+
+	arg := db.UpsertFeeScheduleParams{
+		Currency:         req.GetCurrency(),
+		Operation:        req.GetOperation(),
+		FlatFee:          req.GetFlatFee(),
+		PercentageBps:    req.GetPercentageBps(),
+		FreePerMonth:     req.GetFreePerMonth(),
+		RevenueAccountID: revenueAccount.ID,
+	}
+
+	schedule, err := server.store.UpsertFeeSchedule(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set fee schedule: %w", err)
+	}
+
+	rsp := &pb.SetFeeScheduleResponse{
+		FeeSchedule: convertFeeSchedule(schedule),
+	}
+	return rsp, nil
+}
+
+func validateSetFeeScheduleRequest(req *pb.SetFeeScheduleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateFeeOperation(req.GetOperation()); err != nil {
+		violations = append(violations, fieldViolation("operation", err))
+	}
+
+	if err := val.ValidateNonNegative(req.GetFlatFee()); err != nil {
+		violations = append(violations, fieldViolation("flat_fee", err))
+	}
+
+	if err := val.ValidateBasisPoints(req.GetPercentageBps()); err != nil {
+		violations = append(violations, fieldViolation("percentage_bps", err))
+	}
+
+	if err := val.ValidateNonNegative(int64(req.GetFreePerMonth())); err != nil {
+		violations = append(violations, fieldViolation("free_per_month", err))
+	}
+
+	if err := val.ValidateID(req.GetRevenueAccountId()); err != nil {
+		violations = append(violations, fieldViolation("revenue_account_id", err))
+	}
+	return
+}
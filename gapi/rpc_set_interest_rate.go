@@ -0,0 +1,76 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetInterestRate(ctx context.Context, req *pb.SetInterestRateRequest) (*pb.SetInterestRateResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetInterestRateRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	expenseAccount, err := server.validAccount(ctx, req.GetExpenseAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if expenseAccount.Owner != util.BankOwner || expenseAccount.Type != util.ExpenseAccount {
+		return nil, status.Errorf(codes.FailedPrecondition, "account %d is not a bank expense account", expenseAccount.ID)
+	}
+
+	arg := db.UpsertInterestRateParams{
+		Currency:         req.GetCurrency(),
+		AccountType:      req.GetAccountType(),
+		AnnualRate:       req.GetAnnualRate(),
+		DayCount:         req.GetDayCount(),
+		ExpenseAccountID: expenseAccount.ID,
+	}
+
+	rate, err := server.store.UpsertInterestRate(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set interest rate: %w", err)
+	}
+
+	rsp := &pb.SetInterestRateResponse{
+		InterestRate: convertInterestRate(rate),
+	}
+	return rsp, nil
+}
+
+func validateSetInterestRateRequest(req *pb.SetInterestRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateAccountType(req.GetAccountType()); err != nil {
+		violations = append(violations, fieldViolation("account_type", err))
+	}
+
+	if err := val.ValidateInterestRate(req.GetAnnualRate()); err != nil {
+		violations = append(violations, fieldViolation("annual_rate", err))
+	}
+
+	if err := val.ValidateDayCount(req.GetDayCount()); err != nil {
+		violations = append(violations, fieldViolation("day_count", err))
+	}
+
+	if err := val.ValidateID(req.GetExpenseAccountId()); err != nil {
+		violations = append(violations, fieldViolation("expense_account_id", err))
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetTransferLimit(ctx context.Context, req *pb.SetTransferLimitRequest) (*pb.SetTransferLimitResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetTransferLimitRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	arg := db.UpsertTransferLimitOverrideParams{
+		Username: sql.NullString{
+			String: req.GetUsername(),
+			Valid:  true,
+		},
+		Currency:    req.GetCurrency(),
+		PerTransfer: req.GetPerTransfer(),
+		Daily:       req.GetDaily(),
+		Monthly:     req.GetMonthly(),
+	}
+
+	limit, err := server.store.UpsertTransferLimitOverride(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "user %s not found", req.GetUsername())
+			}
+		}
+		return nil, fmt.Errorf("failed to set transfer limit: %w", err)
+	}
+
+	rsp := &pb.SetTransferLimitResponse{
+		Limit: convertTransferLimit(limit),
+	}
+	return rsp, nil
+}
+
+func validateSetTransferLimitRequest(req *pb.SetTransferLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateAmount(req.GetPerTransfer()); err != nil {
+		violations = append(violations, fieldViolation("per_transfer", err))
+	}
+
+	if err := val.ValidateAmount(req.GetDaily()); err != nil {
+		violations = append(violations, fieldViolation("daily", err))
+	}
+
+	if err := val.ValidateAmount(req.GetMonthly()); err != nil {
+		violations = append(violations, fieldViolation("monthly", err))
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateAccountStatus(ctx context.Context, req *pb.UpdateAccountStatusRequest) (*pb.UpdateAccountStatusResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateAccountStatusRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account %d not found", req.GetAccountId())
+		}
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	// Freezing and reopening are for bankers, owners may only close their own accounts
+	if authPayload.Role != util.BankerRole {
+		if account.Owner != authPayload.Username {
+			return nil, status.Errorf(codes.PermissionDenied, "cannot change other user's account")
+		}
+		if req.GetStatus() != db.AccountStatusClosed {
+			return nil, status.Errorf(codes.PermissionDenied, "only bankers can set an account %s", req.GetStatus())
+		}
+	}
+
+	result, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID:        account.ID,
+		Status:           req.GetStatus(),
+		Reason:           req.GetReason(),
+		ChangedBy:        authPayload.Username,
+		FreezeCredits:    req.GetFreezeCredits(),
+		SweepToAccountID: req.GetSweepToAccountId(),
+	})
+	if err != nil {
+		if isAccountStatusError(err) {
+			return nil, apperr.From(fmt.Errorf("cannot update account status: %w", err))
+		}
+		return nil, fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	rsp := &pb.UpdateAccountStatusResponse{
+		Account: convertAccount(result.Account),
+		Change:  convertAccountStatusChange(result.Change),
+	}
+	return rsp, nil
+}
+
+func isAccountStatusError(err error) bool {
+	return isAccountUnavailable(err) ||
+		errors.Is(err, db.ErrInvalidStatusTransition) ||
+		errors.Is(err, db.ErrNonZeroBalance) ||
+		errors.Is(err, db.ErrAccountHasHolds) ||
+		errors.Is(err, db.ErrInvalidSweepAccount)
+}
+
+func isAccountUnavailable(err error) bool {
+	/** Frozen or closed accounts refuse to take part in a transfer. */
+	return errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrAccountClosed)
+}
+
+func validateUpdateAccountStatusRequest(req *pb.UpdateAccountStatusRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	switch req.GetStatus() {
+	case db.AccountStatusActive, db.AccountStatusFrozen, db.AccountStatusClosed:
+	default:
+		violations = append(violations, fieldViolation("status", fmt.Errorf("unsupported status %q", req.GetStatus())))
+	}
+
+	if err := val.ValidateString(req.GetReason(), 3, 200); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+
+	if req.GetFreezeCredits() && req.GetStatus() != db.AccountStatusFrozen {
+		violations = append(violations, fieldViolation("freeze_credits", errors.New("only allowed when freezing")))
+	}
+
+	if req.GetSweepToAccountId() != 0 {
+		if err := val.ValidateID(req.GetSweepToAccountId()); err != nil {
+			violations = append(violations, fieldViolation("sweep_to_account_id", err))
+		} else if req.GetStatus() != db.AccountStatusClosed {
+			violations = append(violations, fieldViolation("sweep_to_account_id", errors.New("only allowed when closing")))
+		}
+	}
+	return
+}
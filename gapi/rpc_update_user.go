@@ -3,9 +3,12 @@ package gapi
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/jasonwebb3152/simplebank/apperr"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/pb"
 	"github.com/jasonwebb3152/simplebank/util"
 	"github.com/jasonwebb3152/simplebank/val"
@@ -15,14 +18,26 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Paths UpdateUser accepts in update_mask; the address is always replaced as a whole
+var updatableUserFields = map[string]bool{
+	"password":      true,
+	"full_name":     true,
+	"email":         true,
+	"phone":         true,
+	"address":       true,
+	"date_of_birth": true,
+	"nationality":   true,
+}
+
 func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	// TODO: add authorization layer
-	authPayload, err := server.authorizeUser(ctx)
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
 
-	violations := validateUpdateUserRequest(req)
+	fields := updateUserFields(req)
+	violations := validateUpdateUserRequest(req, fields)
 	if violations != nil {
 		return nil, InvalidArgumentError(violations)
 	}
@@ -32,44 +47,85 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	}
 
 	arg := db.UpdateUserParams{
-		Username: req.GetUsername(),
-		FullName: sql.NullString{
-			Valid:  req.FullName != nil,
-			String: req.GetFullName(),
-		},
-		Email: sql.NullString{
-			Valid:  req.Email != nil,
-			String: req.GetEmail(),
-		},
-	}
-
-	if req.Password != nil {
-		hashedPassword, err := util.HashPassword(req.GetPassword())
+		Username:       req.GetUsername(),
+		SetFullName:    fields["full_name"],
+		FullName:       req.GetFullName(),
+		SetEmail:       fields["email"],
+		Email:          req.GetEmail(),
+		SetPhone:       fields["phone"],
+		Phone:          nullString(req.GetPhone()),
+		SetAddress:     fields["address"],
+		SetDateOfBirth: fields["date_of_birth"],
+		SetNationality: fields["nationality"],
+		Nationality:    nullString(req.GetNationality()),
+	}
+
+	if address := req.GetAddress(); address != nil {
+		arg.AddressLine1 = nullString(address.GetLine1())
+		arg.AddressLine2 = nullString(address.GetLine2())
+		arg.AddressCity = nullString(address.GetCity())
+		arg.AddressPostalCode = nullString(address.GetPostalCode())
+		arg.AddressCountry = nullString(address.GetCountry())
+	}
+
+	if dateOfBirth := req.GetDateOfBirth(); dateOfBirth != "" {
+		// already validated
+		date, _ := time.Parse(time.DateOnly, dateOfBirth)
+		arg.DateOfBirth = sql.NullTime{
+			Valid: true,
+			Time:  date,
+		}
+	}
+
+	if fields["password"] {
+		// The new password is checked against the details the user has after the update
+		user, err := server.store.GetUser(ctx, req.GetUsername())
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+			if err == sql.ErrNoRows {
+				return nil, status.Errorf(codes.NotFound, "username not found")
+			}
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if arg.SetEmail {
+			user.Email = arg.Email
+		}
+		if arg.SetFullName {
+			user.FullName = arg.FullName
 		}
 
-		arg.HashedPassword = sql.NullString{
-			Valid:  true,
-			String: hashedPassword,
+		violations, err := server.checkPasswordPolicy(req.GetPassword(), password.PolicyUser{
+			Username: user.Username,
+			Email:    user.Email,
+			FullName: user.FullName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check password: %w", err)
 		}
-		arg.PasswordChangedAt = sql.NullTime{
-			Valid: true,
-			Time:  time.Now(),
+		if violations != nil {
+			return nil, InvalidArgumentError(violations)
+		}
+
+		hashedPassword, err := server.passwords.Hash(req.GetPassword())
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
 		}
+
+		arg.SetHashedPassword = true
+		arg.HashedPassword = hashedPassword
+		arg.PasswordChangedAt = time.Now()
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	user, err := server.store.UpdateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "case_not_found":
-				return nil, status.Errorf(codes.NotFound, "username not found: %s", err)
+				return nil, apperr.Wrap(apperr.CodeNotFound, err, "username not found")
 			case "unique_violation": // unique username and email stuff
-				return nil, status.Errorf(codes.AlreadyExists, "email already exists: %s", err)
+				return nil, apperr.Wrap(apperr.CodeAlreadyExists, err, "email already exists")
 			}
 		}
-		return nil, status.Errorf(codes.Internal, "failed to update user: %s", err)
+		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
 	rsp := &pb.UpdateUserResponse{
@@ -78,28 +134,113 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	return rsp, nil
 }
 
-func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+func updateUserFields(req *pb.UpdateUserRequest) map[string]bool {
+	/**
+	Returns the fields the request updates: the paths of its update mask, or
+	without one, the fields it sets.
+	*/
+	fields := make(map[string]bool)
+	if mask := req.GetUpdateMask(); mask != nil {
+		for _, path := range mask.GetPaths() {
+			fields[path] = true
+		}
+		return fields
+	}
+
+	fields["password"] = req.Password != nil
+	fields["full_name"] = req.FullName != nil
+	fields["email"] = req.Email != nil
+	fields["phone"] = req.GetPhone() != ""
+	fields["address"] = req.GetAddress() != nil
+	fields["date_of_birth"] = req.GetDateOfBirth() != ""
+	fields["nationality"] = req.GetNationality() != ""
+	return fields
+}
+
+func validateUpdateUserRequest(req *pb.UpdateUserRequest, fields map[string]bool) (violations []*errdetails.BadRequest_FieldViolation) {
 
 	if err := val.ValidateUsername(req.GetUsername()); err != nil {
 		violations = append(violations, fieldViolation("username", err))
 	}
 
-	if email := req.GetEmail(); email != "" {
-		if err := val.ValidateEmail(email); err != nil {
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		if !updatableUserFields[path] {
+			violations = append(violations, fieldViolation("update_mask", fmt.Errorf("unsupported path %q", path)))
+		}
+	}
+
+	if fields["email"] {
+		if err := val.ValidateEmail(req.GetEmail()); err != nil {
 			violations = append(violations, fieldViolation("email", err))
 		}
 	}
 
-	if password := req.GetPassword(); password != "" {
-		if err := val.ValidatePassword(password); err != nil {
-			violations = append(violations, fieldViolation("password", err))
+	if fields["full_name"] {
+		if err := val.ValidateFullName(req.GetFullName()); err != nil {
+			violations = append(violations, fieldViolation("full_name", err))
 		}
 	}
 
-	if fullName := req.GetFullName(); fullName != "" {
-		if err := val.ValidateFullName(fullName); err != nil {
-			violations = append(violations, fieldViolation("fullName", err))
+	if fields["password"] && req.GetPassword() == "" {
+		violations = append(violations, fieldViolation("password", fmt.Errorf("cannot be cleared")))
+	}
+
+	if phone := req.GetPhone(); fields["phone"] && phone != "" {
+		if err := val.ValidatePhone(phone); err != nil {
+			violations = append(violations, fieldViolation("phone", err))
+		}
+	}
+
+	if address := req.GetAddress(); fields["address"] && address != nil {
+		violations = append(violations, validateAddress(address)...)
+	}
+
+	if dateOfBirth := req.GetDateOfBirth(); fields["date_of_birth"] && dateOfBirth != "" {
+		if err := val.ValidateDateOfBirth(dateOfBirth); err != nil {
+			violations = append(violations, fieldViolation("date_of_birth", err))
+		}
+	}
+
+	if nationality := req.GetNationality(); fields["nationality"] && nationality != "" {
+		if err := val.ValidateCountry(nationality); err != nil {
+			violations = append(violations, fieldViolation("nationality", err))
 		}
 	}
 	return
 }
+
+func validateAddress(address *pb.Address) (violations []*errdetails.BadRequest_FieldViolation) {
+	/** Line 1, city and country are required; line 2 and the postal code are optional. */
+	if err := val.ValidateAddressLine(address.GetLine1()); err != nil {
+		violations = append(violations, fieldViolation("address.line1", err))
+	}
+
+	if line2 := address.GetLine2(); line2 != "" {
+		if err := val.ValidateAddressLine(line2); err != nil {
+			violations = append(violations, fieldViolation("address.line2", err))
+		}
+	}
+
+	if err := val.ValidateCity(address.GetCity()); err != nil {
+		violations = append(violations, fieldViolation("address.city", err))
+	}
+
+	if postalCode := address.GetPostalCode(); postalCode != "" {
+		if err := val.ValidatePostalCode(postalCode); err != nil {
+			violations = append(violations, fieldViolation("address.postal_code", err))
+		}
+	}
+
+	if err := val.ValidateCountry(address.GetCountry()); err != nil {
+		violations = append(violations, fieldViolation("address.country", err))
+	}
+	return
+}
+
+func nullString(value string) sql.NullString {
+	/** Maps the empty string, which proto3 can't tell apart from unset, to NULL. */
+	return sql.NullString{
+		Valid:  value != "",
+		String: value,
+	}
+}
@@ -0,0 +1,134 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func violatedFields(violations []*errdetails.BadRequest_FieldViolation) []string {
+	fields := []string{}
+	for _, violation := range violations {
+		fields = append(fields, violation.GetField())
+	}
+	return fields
+}
+
+func TestUpdateUserFields(t *testing.T) {
+	testCases := []struct {
+		name   string
+		req    *pb.UpdateUserRequest
+		fields []string
+	}{
+		{
+			name: "NoMaskSetFields",
+			req: &pb.UpdateUserRequest{
+				Username:    "alice",
+				Email:       proto.String("alice@example.com"),
+				Phone:       "+14155552671",
+				DateOfBirth: "1990-04-21",
+			},
+			fields: []string{"email", "phone", "date_of_birth"},
+		},
+		{
+			// Without a mask an empty value means unset, so nothing gets cleared
+			name: "NoMaskEmptyValues",
+			req: &pb.UpdateUserRequest{
+				Username: "alice",
+				FullName: proto.String(""),
+			},
+			fields: []string{"full_name"},
+		},
+		{
+			name: "MaskOnly",
+			req: &pb.UpdateUserRequest{
+				Username:    "alice",
+				Email:       proto.String("alice@example.com"),
+				Phone:       "+14155552671",
+				Nationality: "CA",
+				UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"phone", "address"}},
+			},
+			fields: []string{"phone", "address"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := updateUserFields(tc.req)
+
+			var updated []string
+			for field, update := range fields {
+				if update {
+					updated = append(updated, field)
+				}
+			}
+			require.ElementsMatch(t, tc.fields, updated)
+		})
+	}
+}
+
+func TestValidateUpdateUserRequest(t *testing.T) {
+	testCases := []struct {
+		name       string
+		req        *pb.UpdateUserRequest
+		violations []string
+	}{
+		{
+			// Details in the mask that are empty in the request are cleared
+			name: "ClearDetails",
+			req: &pb.UpdateUserRequest{
+				Username:   "alice",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"phone", "address", "date_of_birth", "nationality"}},
+			},
+			violations: []string{},
+		},
+		{
+			name: "ClearPassword",
+			req: &pb.UpdateUserRequest{
+				Username:   "alice",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}},
+			},
+			violations: []string{"password"},
+		},
+		{
+			name: "UnsupportedPath",
+			req: &pb.UpdateUserRequest{
+				Username:   "alice",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}},
+			},
+			violations: []string{"update_mask"},
+		},
+		{
+			name: "InvalidDetails",
+			req: &pb.UpdateUserRequest{
+				Username:    "alice",
+				Phone:       "4155552671",
+				Address:     &pb.Address{Line1: "1 Main Street", City: "Springfield"},
+				DateOfBirth: "1990-02-30",
+				Nationality: "XX",
+			},
+			violations: []string{"phone", "address.country", "date_of_birth", "nationality"},
+		},
+		{
+			// Fields left out of the mask are not validated, they are not updated
+			name: "InvalidOutsideMask",
+			req: &pb.UpdateUserRequest{
+				Username:   "alice",
+				Phone:      "4155552671",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"nationality"}},
+			},
+			violations: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := validateUpdateUserRequest(tc.req, updateUserFields(tc.req))
+			require.ElementsMatch(t, tc.violations, violatedFields(violations))
+		})
+	}
+}
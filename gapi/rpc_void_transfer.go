@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jasonwebb3152/simplebank/apperr"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) VoidTransfer(ctx context.Context, req *pb.VoidTransferRequest) (*pb.VoidTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateVoidTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeHoldRecipient(ctx, authPayload, req.GetHoldId()); err != nil {
+		return nil, err
+	}
+
+	hold, err := server.store.VoidTransferTx(ctx, db.VoidTransferTxParams{
+		HoldID: req.GetHoldId(),
+	})
+	if err != nil {
+		if isHoldStateError(err) {
+			return nil, apperr.From(fmt.Errorf("cannot void transfer: %w", err))
+		}
+		return nil, fmt.Errorf("failed to void transfer: %w", err)
+	}
+
+	rsp := &pb.VoidTransferResponse{
+		Hold: convertAccountHold(hold),
+	}
+	return rsp, nil
+}
+
+func validateVoidTransferRequest(req *pb.VoidTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetHoldId()); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package gapi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/activity"
+	"github.com/jasonwebb3152/simplebank/apperr"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) WatchAccount(req *pb.WatchAccountRequest, stream grpc.ServerStreamingServer[pb.WatchAccountResponse]) error {
+	ctx := stream.Context()
+
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return unauthenticatedError(err)
+	}
+
+	violations := validateWatchAccountRequest(req)
+	if violations != nil {
+		return InvalidArgumentError(violations)
+	}
+
+	if err := server.authorizeAccountOwner(ctx, authPayload, req.GetAccountId()); err != nil {
+		return err
+	}
+
+	// Subscribe before reading the account so nothing committed in between is missed.
+	// The client may see a state it already has again, but never misses one.
+	sub := server.activity.Subscribe(req.GetAccountId())
+	defer sub.Close()
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	err = stream.Send(&pb.WatchAccountResponse{
+		Activity: &pb.WatchAccountResponse_Account{Account: convertAccount(account)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// The token is only checked when the stream opens, so the stream must not
+	// outlive it. The client renews its token and watches again.
+	expiry := time.NewTimer(time.Until(authPayload.ExpiredAt))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-expiry.C:
+			return unauthenticatedError(fmt.Errorf("invalid access token: %w", token.ErrExpiredToken))
+		case event, ok := <-sub.Events():
+			if !ok {
+				return watchAccountError(sub.Err())
+			}
+
+			rsp := &pb.WatchAccountResponse{}
+			if event.Entry != nil {
+				rsp.Activity = &pb.WatchAccountResponse_Entry{Entry: convertEntry(*event.Entry)}
+			} else {
+				rsp.Activity = &pb.WatchAccountResponse_Account{Account: convertAccount(*event.Account)}
+			}
+
+			if err := stream.Send(rsp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func watchAccountError(err error) error {
+	/** Either way the client should reconnect, it starts again from the current state. */
+	if errors.Is(err, activity.ErrLagged) {
+		return status.Errorf(codes.ResourceExhausted, "too much activity to keep up with, watch again")
+	}
+	return apperr.Wrap(apperr.CodeUnavailable, err, "account activity was interrupted, watch again")
+}
+
+func validateWatchAccountRequest(req *pb.WatchAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jasonwebb3152/simplebank/activity"
+	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/token"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type watchAccountStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*pb.WatchAccountResponse
+}
+
+func (stream *watchAccountStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *watchAccountStream) Send(rsp *pb.WatchAccountResponse) error {
+	stream.responses = append(stream.responses, rsp)
+	return nil
+}
+
+func TestWatchAccountTokenExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	tokenMaker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	account := db.Account{ID: 1, Owner: util.RandomOwner(), Currency: util.USD}
+	accessToken, _, err := tokenMaker.CreateToken(account.Owner, util.DepositorRole, 200*time.Millisecond)
+	require.NoError(t, err)
+
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		Times(2).
+		Return(account, nil)
+
+	server := &Server{
+		store:      store,
+		tokenMaker: tokenMaker,
+		activity:   activity.NewHub(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	md := metadata.MD{authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, accessToken)}}
+	stream := &watchAccountStream{ctx: metadata.NewIncomingContext(ctx, md)}
+
+	// The stream ends on its own once the token expires, well before the deadline
+	err = server.WatchAccount(&pb.WatchAccountRequest{AccountId: account.ID}, stream)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.NoError(t, ctx.Err())
+	require.Len(t, stream.responses, 1)
+}
@@ -2,9 +2,13 @@ package gapi
 
 import (
 	"fmt"
+	"net"
 
+	"github.com/jasonwebb3152/simplebank/activity"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/password"
 	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/ratelimit"
 	"github.com/jasonwebb3152/simplebank/token"
 	"github.com/jasonwebb3152/simplebank/util"
 )
@@ -14,18 +18,58 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	passwords  *password.Manager
+	policy     *password.Policy
+	activity   *activity.Hub
+	limiter    *ratelimit.Limiter
+	// Names of the client certificates allowed to call, any when empty
+	clientNames map[string]bool
+	// Peers whose X-Forwarded-For is believed, none when empty
+	trustedProxies []*net.IPNet
 }
 
-// NewServer creates a new gRPC server (gRPC doesn't use routing)
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+// NewServer creates a new gRPC server (gRPC doesn't use routing).
+// WatchAccount streams the account activity published to hub.
+func NewServer(config util.Config, store db.Store, hub *activity.Hub) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+
+	limiter, err := ratelimit.New(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rate limiter: %w", err)
+	}
+
+	if !util.IsSupportedSessionRiskPolicy(config.SessionRiskPolicy) {
+		return nil, fmt.Errorf("unsupported session risk policy %q", config.SessionRiskPolicy)
+	}
+
+	passwords, err := password.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password manager: %w", err)
+	}
+
+	policy, err := password.NewPolicyFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password policy: %w", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		passwords:      passwords,
+		policy:         policy,
+		activity:       hub,
+		limiter:        limiter,
+		clientNames:    parseClientNames(config.TLSClientNames),
+		trustedProxies: trustedProxies,
 	}
 
 	return server, nil
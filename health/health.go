@@ -0,0 +1,240 @@
+// Package health reports whether the service is alive and ready to take traffic,
+// over HTTP for the Kubernetes probes and over the standard gRPC health service.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// How long a single dependency may take to answer
+const checkTimeout = 2 * time.Second
+
+// Check returns an error when a dependency cannot be used.
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the body of the probe endpoints.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker runs the readiness checks. It stops being ready for good once shutdown
+// begins, so load balancers take the instance out while it drains.
+type Checker struct {
+	mu           sync.RWMutex
+	names        []string
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+	// Closed by Shutdown, so Serve does not wait for its next tick
+	shutdown chan struct{}
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		checks:   make(map[string]Check),
+		shutdown: make(chan struct{}),
+	}
+}
+
+// Add registers a dependency under name. Servers started later, such as the
+// gateway, can add theirs while the checks already run.
+func (checker *Checker) Add(name string, check Check) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+	checker.names = append(checker.names, name)
+	sort.Strings(checker.names)
+	checker.checks[name] = check
+}
+
+// Shutdown marks the service as not ready, over HTTP and gRPC alike. Safe to call
+// more than once.
+func (checker *Checker) Shutdown() {
+	if checker.shuttingDown.CompareAndSwap(false, true) {
+		close(checker.shutdown)
+	}
+}
+
+// Ready runs every check concurrently and reports each of them.
+func (checker *Checker) Ready(ctx context.Context) Report {
+	checker.mu.RLock()
+	defer checker.mu.RUnlock()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(checker.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range checker.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, checker.checks[name])
+	}
+	wg.Wait()
+
+	if checker.shuttingDown.Load() {
+		report.Status = StatusUnavailable
+		report.Checks["shutdown"] = CheckResult{
+			Status: StatusUnavailable,
+			Error:  "shutting down",
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:   StatusOK,
+		Duration: time.Since(startTime).String(),
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Liveness answers as long as the process can serve HTTP at all. It deliberately
+// checks no dependency: a database outage should not get every pod restarted.
+func (checker *Checker) Liveness() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		writeReport(res, Report{Status: StatusOK})
+	})
+}
+
+// Readiness answers 503 unless every dependency is usable.
+func (checker *Checker) Readiness() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		writeReport(res, checker.Ready(req.Context()))
+	})
+}
+
+func writeReport(res http.ResponseWriter, report Report) {
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusOK {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(res).Encode(report)
+}
+
+// Serve keeps the gRPC health service in line with the readiness checks until
+// Shutdown is called or ctx is cancelled. Every service then reports NOT_SERVING
+// and the readiness endpoint fails.
+func (checker *Checker) Serve(ctx context.Context, server *grpchealth.Server, interval time.Duration) {
+	update := func() {
+		status := healthpb.HealthCheckResponse_SERVING
+		report := checker.Ready(ctx)
+		if report.Status != StatusOK {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			log.Warn().Interface("checks", report.Checks).Msg("service is not ready")
+		}
+		// The empty name stands for the server as a whole
+		server.SetServingStatus("", status)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	update()
+	for {
+		select {
+		case <-checker.shutdown:
+			server.Shutdown()
+			return
+		case <-ctx.Done():
+			checker.Shutdown()
+			server.Shutdown()
+			return
+		case <-ticker.C:
+			update()
+		}
+	}
+}
+
+// Database checks that a connection can be made.
+func Database(conn *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return conn.PingContext(ctx)
+	}
+}
+
+// GRPCConn checks that a client connection, such as the gateway's connection to
+// the gRPC server, is connected or can connect.
+func GRPCConn(conn *grpc.ClientConn) Check {
+	return func(ctx context.Context) error {
+		conn.Connect()
+		for {
+			state := conn.GetState()
+			if state == connectivity.Ready {
+				return nil
+			}
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection is %s", strings.ToLower(state.String()))
+			}
+		}
+	}
+}
+
+// Migrations checks that the schema is at least at version and that no migration
+// failed halfway. A newer version is fine: it belongs to a release being rolled out.
+func Migrations(conn *sql.DB, version uint) Check {
+	return func(ctx context.Context) error {
+		var current uint
+		var dirty bool
+		err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&current, &dirty)
+		if err != nil {
+			return fmt.Errorf("cannot read migration version: %w", err)
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d failed and must be fixed by hand", current)
+		}
+		if current < version {
+			return fmt.Errorf("schema is at version %d, want %d", current, version)
+		}
+		return nil
+	}
+}
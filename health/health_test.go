@@ -0,0 +1,169 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func okCheck(ctx context.Context) error {
+	return nil
+}
+
+func failingCheck(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func getReport(t *testing.T, handler http.Handler, path string) (int, Report) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	return recorder.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", okCheck)
+	checker.Add("migrations", okCheck)
+
+	code, report := getReport(t, checker.Readiness(), "/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+	require.Len(t, report.Checks, 2)
+	require.Equal(t, StatusOK, report.Checks["database"].Status)
+
+	checker.Add("cache", failingCheck)
+	code, report = getReport(t, checker.Readiness(), "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, StatusOK, report.Checks["database"].Status)
+	require.Equal(t, StatusUnavailable, report.Checks["cache"].Status)
+	require.Equal(t, "connection refused", report.Checks["cache"].Error)
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	report := checker.Ready(ctx)
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestShutdown(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", okCheck)
+	checker.Shutdown()
+
+	code, report := getReport(t, checker.Readiness(), "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, StatusUnavailable, report.Checks["shutdown"].Status)
+
+	// Still alive while draining
+	code, report = getReport(t, checker.Liveness(), "/healthz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+}
+
+func TestServe(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", okCheck)
+	server := grpchealth.NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		checker.Serve(ctx, server, time.Hour)
+		close(done)
+	}()
+
+	request := &healthpb.HealthCheckRequest{}
+	require.Eventually(t, func() bool {
+		res, err := server.Check(context.Background(), request)
+		return err == nil && res.Status == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+
+	res, err := server.Check(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+	require.Equal(t, StatusUnavailable, checker.Ready(context.Background()).Status)
+}
+
+func TestServeShutdown(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", okCheck)
+	server := grpchealth.NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		checker.Serve(ctx, server, time.Hour)
+		close(done)
+	}()
+
+	request := &healthpb.HealthCheckRequest{}
+	require.Eventually(t, func() bool {
+		res, err := server.Check(context.Background(), request)
+		return err == nil && res.Status == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 5*time.Millisecond)
+
+	// Draining starts before ctx is cancelled and must not wait for the next tick
+	checker.Shutdown()
+	checker.Shutdown()
+	<-done
+
+	res, err := server.Check(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+}
+
+func TestGRPCConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, GRPCConn(conn)(ctx))
+
+	// Nothing listens there anymore, so the check fails within its deadline
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+	broken, err := grpc.NewClient(closed.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer broken.Close()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.Error(t, GRPCConn(broken)(ctx))
+}
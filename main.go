@@ -2,20 +2,41 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"github.com/jasonwebb3152/simplebank/api"
+	"github.com/jasonwebb3152/simplebank/activity"
+	"github.com/jasonwebb3152/simplebank/certs"
 	db "github.com/jasonwebb3152/simplebank/db/sqlc"
 	"github.com/jasonwebb3152/simplebank/gapi"
+	"github.com/jasonwebb3152/simplebank/health"
 	"github.com/jasonwebb3152/simplebank/pb"
+	"github.com/jasonwebb3152/simplebank/telemetry"
 	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/jasonwebb3152/simplebank/worker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -24,25 +45,98 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// Signals that start a graceful shutdown. Kubernetes sends SIGTERM.
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+}
+
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot connect to db")
+		log.Fatal().Err(err).Msg("cannot load config")
 	}
-	conn, err := sql.Open(config.DBDriver, config.DBSource)
+
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
+	conn, err := db.Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot connect to db")
 	}
 
 	// run db migration
-	runDBMigration(config.MigrationURL, config.DBSource)
+	schemaVersion := runDBMigration(config.MigrationURL, config.DBSource)
+
+	shutdownTracing, err := telemetry.Setup(ctx, telemetry.Config{
+		Exporter: config.TraceExporter,
+		Endpoint: config.OTLPEndpoint,
+		Insecure: config.OTLPInsecure,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot set up tracing")
+	}
 
 	store := db.NewStore(conn)
-	go runGatewayServer(config, store)
-	runGrpcServer(config, store)
+	hub := activity.NewHub()
+
+	checker := health.NewChecker()
+	checker.Add("database", health.Database(conn))
+	checker.Add("migrations", health.Migrations(conn, schemaVersion))
+
+	// The first component to fail cancels ctx, which shuts down all the others. A
+	// signal only cancels it once the load balancers stopped sending traffic.
+	signalCtx := ctx
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+	waitGroup, ctx := errgroup.WithContext(serveCtx)
+	runDrain(ctx, signalCtx, waitGroup, config, checker, stopServing)
+
+	// The servers come first: they fail right away if they cannot start, before any
+	// worker has started. The failure is handed to the group, which then shuts down
+	// whatever already runs the same way as on a signal.
+	startErr := runServers(ctx, waitGroup, config, store, hub, checker)
+	if startErr != nil {
+		waitGroup.Go(func() error {
+			return startErr
+		})
+	} else {
+		runMetricsServer(ctx, waitGroup, config, conn)
+		runWorker(ctx, waitGroup, "hold sweeper", config.HoldSweepInterval,
+			worker.NewHoldSweeper(store, config.HoldSweepInterval))
+		runWorker(ctx, waitGroup, "transfer scheduler", config.SchedulerInterval,
+			worker.NewTransferScheduler(store, config.SchedulerInterval))
+		runWorker(ctx, waitGroup, "payroll processor", config.PayrollInterval,
+			worker.NewPayrollProcessor(store, config.PayrollInterval, config.PayrollChunkSize))
+		runWorker(ctx, waitGroup, "interest accruer", config.InterestInterval,
+			worker.NewInterestAccruer(store, config.InterestInterval, config.InterestLookbackDays))
+		runWorker(ctx, waitGroup, "balance snapshotter", config.SnapshotInterval,
+			worker.NewBalanceSnapshotter(store, config.SnapshotInterval, config.SnapshotLookbackDays))
+		runWorker(ctx, waitGroup, "webhook dispatcher", config.WebhookInterval,
+			worker.NewWebhookDispatcher(store, config.WebhookInterval, config.WebhookTimeout, config.WebhookMaxAttempts, config.WebhookAllowInsecure))
+		runActivityListener(ctx, waitGroup, config, hub)
+	}
+
+	err = waitGroup.Wait()
+
+	// Only closed once nothing is using it anymore
+	if closeErr := conn.Close(); closeErr != nil {
+		log.Error().Err(closeErr).Msg("cannot close db")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if tracingErr := shutdownTracing(shutdownCtx); tracingErr != nil {
+		log.Error().Err(tracingErr).Msg("cannot flush traces")
+	}
+
+	if err != nil {
+		log.Fatal().Err(err).Msg("server stopped with an error")
+	}
+	log.Info().Msg("server stopped")
 }
 
-func runDBMigration(migrationUrl string, dbSource string) {
+func runDBMigration(migrationUrl string, dbSource string) uint {
 	m, err := migrate.New(migrationUrl, dbSource)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create migration object")
@@ -52,40 +146,181 @@ func runDBMigration(migrationUrl string, dbSource string) {
 		log.Fatal().Err(err).Msg("failed to run migrate up")
 	}
 
+	version, _, err := m.Version()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot read migration version")
+	}
+
 	log.Info().Msg("db migrated successfully")
+	return version
+}
+
+type backgroundWorker interface {
+	Start(ctx context.Context)
+}
+
+func runWorker(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	name string,
+	interval time.Duration,
+	w backgroundWorker,
+) {
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start %s every %s", name, interval)
+		w.Start(ctx)
+		log.Info().Msgf("%s stopped", name)
+		return nil
+	})
+}
+
+func runActivityListener(ctx context.Context, waitGroup *errgroup.Group, config util.Config, hub *activity.Hub) {
+	waitGroup.Go(func() error {
+		log.Info().Msg("start listening for account activity")
+		err := hub.Listen(ctx, config.DBSource)
+		if err != nil {
+			return fmt.Errorf("cannot listen for account activity: %w", err)
+		}
+		// Ends the streams watching accounts so their clients reconnect elsewhere
+		hub.Reset(activity.ErrReset)
+		log.Info().Msg("account activity listener stopped")
+		return nil
+	})
+}
+
+func runDrain(
+	ctx context.Context,
+	signalCtx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	checker *health.Checker,
+	stopServing context.CancelFunc,
+) {
+	waitGroup.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signalCtx.Done():
+		}
+
+		// The readiness probe has to fail often enough for the instance to be taken out
+		// before the servers stop taking new requests
+		log.Info().Msgf("not ready anymore, draining for %s", config.ShutdownDrainDelay)
+		checker.Shutdown()
+		select {
+		case <-ctx.Done():
+		case <-time.After(config.ShutdownDrainDelay):
+		}
+		stopServing()
+		return nil
+	})
 }
 
-func runGrpcServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(config, store)
+func runServers(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	store db.Store,
+	hub *activity.Hub,
+	checker *health.Checker,
+) error {
+	reloader, err := runCertificateReloader(ctx, waitGroup, config)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
+		return err
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	err = runGrpcServer(ctx, waitGroup, config, store, hub, checker, reloader)
+	if err != nil {
+		return err
+	}
+	return runGatewayServer(ctx, waitGroup, config, checker, reloader)
+}
+
+func runGrpcServer(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	store db.Store,
+	hub *activity.Hub,
+	checker *health.Checker,
+	reloader *certs.Reloader,
+) error {
+	server, err := gapi.NewServer(config, store, hub)
+	if err != nil {
+		return fmt.Errorf("cannot create server: %w", err)
+	}
+
+	// GrpcErrors comes after the logger, which then still sees the cause of internal
+	// errors that clients only get a generic message for, and after the metrics,
+	// which count the codes clients actually get
+	grpcLogger := grpc.ChainUnaryInterceptor(gapi.GrpcLogger, gapi.GrpcMetrics, gapi.GrpcErrors, server.AuthorizeClient, server.RateLimit, server.AuditContext)
+	grpcStreamMetrics := grpc.ChainStreamInterceptor(gapi.GrpcStreamMetrics, gapi.GrpcStreamErrors, server.AuthorizeClientStream)
+	grpcTracing := grpc.StatsHandler(otelgrpc.NewServerHandler())
+	serverOptions := []grpc.ServerOption{grpcLogger, grpcStreamMetrics, grpcTracing}
+	if reloader != nil {
+		// Internal callers, the gateway included, authenticate with a client
+		// certificate when a client CA bundle is configured
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.ServerConfig(true))))
+	}
+	grpcServer := grpc.NewServer(serverOptions...)
 	pb.RegisterSimpleBankServer(grpcServer, server)
 
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
 	// This allows grpc client to explore what calls are available on the server
 	reflection.Register(grpcServer)
 
 	listener, err := net.Listen("tcp", config.GRPCServerAddress)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create listener")
+		return fmt.Errorf("cannot create gRPC listener: %w", err)
 	}
 
-	log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start gRPC server")
-	}
-}
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			return fmt.Errorf("gRPC server failed: %w", err)
+		}
+		return nil
+	})
 
-func runGatewayServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(config, store)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
-	}
+	waitGroup.Go(func() error {
+		checker.Serve(ctx, healthServer, config.HealthCheckInterval)
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown gRPC server")
 
+		// GracefulStop waits for running calls, including streams that never end on
+		// their own, so they are cut off once the timeout is up
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(config.ShutdownTimeout):
+			log.Error().Msg("gRPC server did not drain in time")
+			grpcServer.Stop()
+		}
+		log.Info().Msg("gRPC server is stopped")
+		return nil
+	})
+	return nil
+}
+
+func runGatewayServer(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	checker *health.Checker,
+	reloader *certs.Reloader,
+) error {
 	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
 		MarshalOptions: protojson.MarshalOptions{
 			UseProtoNames: true,
@@ -95,15 +330,61 @@ func runGatewayServer(config util.Config, store db.Store) {
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	// Downloads such as statements name their file through this header, and the rate
+	// limiter reports through the standard headers
+	headerOption := runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+		switch key {
+		case "content-disposition":
+			return "Content-Disposition", true
+		case "ratelimit-limit", "ratelimit-remaining", "ratelimit-reset", "retry-after":
+			return http.CanonicalHeaderKey(key), true
+		}
+		return runtime.MetadataHeaderPrefix + key, true
+	})
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	metricsOption := runtime.WithMiddlewares(gapi.HttpMetrics)
+
+	errorOption := runtime.WithErrorHandler(gapi.HttpErrorHandler)
+
+	grpcMux := runtime.NewServeMux(jsonOption, headerOption, metricsOption, errorOption)
+
+	// The gateway calls the gRPC server over the network, since calling it in-process
+	// does not support streaming RPCs such as WatchAccount
+	transportCredentials := insecure.NewCredentials()
+	if reloader != nil {
+		// Without a root CA file the gRPC certificate is checked against the system roots
+		var rootCAs *x509.CertPool
+		if config.TLSRootCAFile != "" {
+			var err error
+			rootCAs, err = certs.LoadCertPool(config.TLSRootCAFile)
+			if err != nil {
+				return fmt.Errorf("cannot load root CAs: %w", err)
+			}
+		}
+		transportCredentials = credentials.NewTLS(reloader.ClientConfig(rootCAs, config.TLSServerName))
+	}
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	// The connection outlives ctx so requests still draining can reach the gRPC server
+	conn, err := grpc.NewClient(config.GRPCServerAddress, dialOptions...)
+	if err != nil {
+		return fmt.Errorf("cannot connect to gRPC server: %w", err)
+	}
+	closeConn := func() {
+		if err := conn.Close(); err != nil {
+			log.Error().Err(err).Msg("cannot close gRPC connection")
+		}
+	}
 
-	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	err = pb.RegisterSimpleBankHandler(ctx, grpcMux, conn)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot register handler server")
+		closeConn()
+		return fmt.Errorf("cannot register handler server: %w", err)
 	}
+	// Not ready while the gateway cannot reach the gRPC server
+	checker.Add("grpc", health.GRPCConn(conn))
 
 	// Create server that reroutes to grpcServer
 	mux := http.NewServeMux()
@@ -112,27 +393,102 @@ func runGatewayServer(config util.Config, store db.Store) {
 	fs := http.FileServer(http.Dir("./doc/swagger"))
 	mux.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
 
+	mux.Handle("/healthz", checker.Liveness())
+	mux.Handle("/readyz", checker.Readiness())
+
 	listener, err := net.Listen("tcp", config.HTTPServerAddress)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create listener")
+		closeConn()
+		return fmt.Errorf("cannot create HTTP listener: %w", err)
 	}
 
-	log.Info().Msgf("start HTTP gateway server at %s", listener.Addr().String())
-	handler := gapi.HttpLogger(mux)
-	err = http.Serve(listener, handler)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start HTTP Gateway server")
+	// The tracing handler goes outermost so the logger sees the request's span.
+	// It continues the trace of a caller that sent a traceparent header.
+	httpServer := &http.Server{
+		Handler: otelhttp.NewHandler(gapi.HttpLogger(mux), "gateway"),
 	}
+
+	waitGroup.Go(func() error {
+		var err error
+		if reloader != nil {
+			// Browsers and other public clients are not asked for a certificate
+			httpServer.TLSConfig = reloader.ServerConfig(false)
+			log.Info().Msgf("start HTTPS gateway server at %s", listener.Addr().String())
+			err = httpServer.ServeTLS(listener, "", "")
+		} else {
+			log.Info().Msgf("start HTTP gateway server at %s", listener.Addr().String())
+			err = httpServer.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP gateway server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown HTTP gateway server")
+		shutdownHTTPServer(httpServer, config.ShutdownTimeout)
+		closeConn()
+		log.Info().Msg("HTTP gateway server is stopped")
+		return nil
+	})
+	return nil
 }
 
-func runGinServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
+func runCertificateReloader(ctx context.Context, waitGroup *errgroup.Group, config util.Config) (*certs.Reloader, error) {
+	if config.TLSCertFile == "" {
+		log.Warn().Msg("TLS is disabled, serving plaintext")
+		return nil, nil
+	}
+
+	reloader, err := certs.NewReloader(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
+		return nil, fmt.Errorf("cannot load certificates: %w", err)
 	}
 
-	err = server.Start(config.HTTPServerAddress)
+	waitGroup.Go(func() error {
+		log.Info().Bool("mtls", reloader.MutualTLS()).Msg("start watching certificates")
+		return reloader.Watch(ctx)
+	})
+	return reloader, nil
+}
+
+func shutdownHTTPServer(server *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start server")
+		log.Error().Err(err).Msg("HTTP server did not drain in time")
+		server.Close()
 	}
 }
+
+func runMetricsServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, conn *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(conn, "simple_bank"))
+
+	// Kept off the public listeners so metrics are only reachable from inside
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	httpServer := &http.Server{
+		Addr:    config.MetricsServerAddress,
+		Handler: mux,
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start metrics server at %s", config.MetricsServerAddress)
+		err := httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("metrics server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		shutdownHTTPServer(httpServer, config.ShutdownTimeout)
+		log.Info().Msg("metrics server is stopped")
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idID      = "argon2id"
+	argon2SaltSize  = 16
+	argon2KeyLength = 32
+)
+
+// Argon2idParams are the costs of an argon2id hash
+type Argon2idParams struct {
+	// Number of passes over the memory
+	Time uint32
+	// Memory in KiB
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultArgon2idParams is the minimum OWASP recommends for argon2id
+var DefaultArgon2idParams = Argon2idParams{
+	Time:    2,
+	Memory:  19 * 1024,
+	Threads: 1,
+}
+
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+// Creates a new Argon2idHasher hashing with params
+func NewArgon2idHasher(params Argon2idParams) (Hasher, error) {
+	if params.Time < 1 {
+		return nil, fmt.Errorf("invalid argon2id time: must be at least 1")
+	}
+	if params.Threads < 1 {
+		return nil, fmt.Errorf("invalid argon2id threads: must be at least 1")
+	}
+	if params.Memory < 8*uint32(params.Threads) {
+		return nil, fmt.Errorf("invalid argon2id memory: must be at least %d KiB", 8*uint32(params.Threads))
+	}
+	return &Argon2idHasher{params: params}, nil
+}
+
+func (hasher *Argon2idHasher) IDs() []string {
+	return []string{argon2idID}
+}
+
+// Hashes password into $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func (hasher *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, hasher.params.Time, hasher.params.Memory, hasher.params.Threads, argon2KeyLength)
+	return encodeArgon2id(hasher.params, salt, key), nil
+}
+
+// Checks if password is the one encoded was made from, with the parameters of encoded.
+func (hasher *Argon2idHasher) Verify(password string, encoded string) error {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+func (hasher *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+	return err != nil || params != hasher.params || len(key) != argon2KeyLength
+}
+
+func encodeArgon2id(params Argon2idParams, salt []byte, key []byte) string {
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idID,
+		argon2.Version,
+		params.Memory,
+		params.Time,
+		params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2id(encoded string) (params Argon2idParams, salt []byte, key []byte, err error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 6 || fields[1] != argon2idID {
+		err = ErrInvalidHash
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(fields[2], "v=%d", &version); err != nil || version != argon2.Version {
+		err = ErrInvalidHash
+		return
+	}
+
+	if _, err = fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		err = ErrInvalidHash
+		return
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil {
+		err = ErrInvalidHash
+		return
+	}
+	key, err = base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil || len(key) == 0 || params.Time < 1 || params.Threads < 1 {
+		err = ErrInvalidHash
+	}
+	return
+}
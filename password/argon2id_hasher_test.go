@@ -0,0 +1,70 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// Cheap costs, the tests don't need slow hashes
+var testArgon2idParams = Argon2idParams{Time: 1, Memory: 1024, Threads: 1}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher, err := NewArgon2idHasher(testArgon2idParams)
+	require.NoError(t, err)
+
+	password := util.RandomString(8)
+	encoded, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	require.False(t, hasher.NeedsRehash(encoded))
+
+	require.NoError(t, hasher.Verify(password, encoded))
+	require.ErrorIs(t, hasher.Verify(util.RandomString(8), encoded), ErrMismatchedPassword)
+
+	// Salted, the same password never hashes the same
+	other, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.NotEqual(t, encoded, other)
+}
+
+func TestArgon2idHasherNeedsRehash(t *testing.T) {
+	weaker, err := NewArgon2idHasher(testArgon2idParams)
+	require.NoError(t, err)
+
+	password := util.RandomString(8)
+	encoded, err := weaker.Hash(password)
+	require.NoError(t, err)
+
+	stronger, err := NewArgon2idHasher(Argon2idParams{Time: 2, Memory: 1024, Threads: 1})
+	require.NoError(t, err)
+	require.True(t, stronger.NeedsRehash(encoded))
+
+	// Old hashes are still checked with their own costs
+	require.NoError(t, stronger.Verify(password, encoded))
+}
+
+func TestArgon2idHasherInvalidHash(t *testing.T) {
+	hasher, err := NewArgon2idHasher(testArgon2idParams)
+	require.NoError(t, err)
+
+	for _, encoded := range []string{
+		"",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+	} {
+		require.ErrorIs(t, hasher.Verify("secret", encoded), ErrInvalidHash, encoded)
+	}
+}
+
+func TestNewArgon2idHasherInvalidParams(t *testing.T) {
+	_, err := NewArgon2idHasher(Argon2idParams{Time: 0, Memory: 1024, Threads: 1})
+	require.Error(t, err)
+
+	_, err = NewArgon2idHasher(Argon2idParams{Time: 1, Memory: 4, Threads: 1})
+	require.Error(t, err)
+}
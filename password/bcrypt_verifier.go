@@ -0,0 +1,36 @@
+package password
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptVerifier checks the bcrypt hashes of passwords set before argon2id was used.
+// It doesn't hash new passwords, these hashes are replaced on the next login.
+type BcryptVerifier struct{}
+
+func NewBcryptVerifier() Verifier {
+	return BcryptVerifier{}
+}
+
+func (verifier BcryptVerifier) IDs() []string {
+	return []string{"2a", "2b", "2y"}
+}
+
+// Hashes with the cost the old hashes were made with.
+func (verifier BcryptVerifier) decoyHash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hashed), err
+}
+
+func (verifier BcryptVerifier) Verify(password string, encoded string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatchedPassword
+	}
+	if err != nil {
+		return ErrInvalidHash
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedList tells whether a password has appeared in a data breach
+type BreachedList interface {
+	Contains(plain string) (bool, error)
+}
+
+// RangeDir is a local copy of a k-anonymity breached password list, in the format
+// of the Have I Been Pwned range API: the SHA-1 hashes of the passwords are split
+// by their first 5 hex digits into <PREFIX>.txt files, whose lines hold the rest of
+// the hash and how often it was seen, like 0018A45C4D1DEF81644B54AB7F969B88D65:10.
+// Checking a password reads a single small file.
+type RangeDir struct {
+	dir string
+}
+
+const rangePrefixLength = 5
+
+func NewRangeDir(dir string) (BreachedList, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open breached password list: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached password list %s is not a directory", dir)
+	}
+	return &RangeDir{dir: dir}, nil
+}
+
+func (list *RangeDir) Contains(plain string) (bool, error) {
+	sum := sha1.Sum([]byte(plain))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:rangePrefixLength], hash[rangePrefixLength:]
+
+	file, err := os.Open(filepath.Join(list.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		// No breached password starts with this prefix
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
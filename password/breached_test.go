@@ -0,0 +1,50 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeRangeDir writes the passwords as range files, the way the HIBP downloader does
+func writeRangeDir(t *testing.T, passwords ...string) string {
+	dir := t.TempDir()
+	for i, plain := range passwords {
+		sum := sha1.Sum([]byte(plain))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+		file, err := os.OpenFile(filepath.Join(dir, hash[:5]+".txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		require.NoError(t, err)
+		_, err = fmt.Fprintf(file, "%s:%d\r\n", hash[5:], i+1)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+	}
+	return dir
+}
+
+func TestRangeDir(t *testing.T) {
+	list, err := NewRangeDir(writeRangeDir(t, "password", "P@ssw0rd"))
+	require.NoError(t, err)
+
+	for plain, breached := range map[string]bool{
+		"password":           true,
+		"P@ssw0rd":           true,
+		"Password":           false,
+		"correct horse 1A!x": false,
+	} {
+		got, err := list.Contains(plain)
+		require.NoError(t, err)
+		require.Equal(t, breached, got, plain)
+	}
+}
+
+func TestNewRangeDirMissing(t *testing.T) {
+	_, err := NewRangeDir(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package password
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMismatchedPassword = errors.New("password does not match")
+	ErrUnsupportedHash    = errors.New("unsupported password hash")
+	ErrInvalidHash        = errors.New("invalid password hash")
+)
+
+// Verifier checks passwords against the hashes of one algorithm
+type Verifier interface {
+	// Identifiers of the algorithm in the PHC strings it can verify
+	IDs() []string
+
+	// Checks if password is the one encoded was made from.
+	Verify(password string, encoded string) error
+}
+
+// Hasher is a Verifier that also hashes new passwords
+type Hasher interface {
+	Verifier
+
+	// Hashes password into a PHC string, with a random salt.
+	Hash(password string) (string, error)
+
+	// Reports whether encoded was made with other parameters than the hasher uses.
+	NeedsRehash(encoded string) bool
+}
+
+// decoyHasher is a Verifier that cannot hash new passwords but can make a hash of a
+// random one, to spend the time checking its hashes takes
+type decoyHasher interface {
+	Verifier
+
+	decoyHash(password string) (string, error)
+}
+
+func hashID(encoded string) string {
+	/** Returns the algorithm identifier of a PHC string, like argon2id in
+	$argon2id$v=19$... */
+	fields := strings.SplitN(encoded, "$", 3)
+	if len(fields) < 3 || fields[0] != "" {
+		return ""
+	}
+	return fields[1]
+}
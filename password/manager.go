@@ -0,0 +1,123 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+// Manager hashes new passwords with its Hasher and checks them against the hashes
+// of every algorithm it knows, so hashes can be upgraded when users log in.
+type Manager struct {
+	hasher    Hasher
+	verifiers map[string]Verifier
+	// A hash of no one's password for every algorithm. Each check verifies one hash
+	// per algorithm, the user's own and decoys for the others, so a login costs the
+	// same whether the user exists and whichever algorithm their hash was made with.
+	decoys []decoy
+}
+
+type decoy struct {
+	verifier Verifier
+	hash     func() string
+}
+
+// Creates a new Manager hashing with hasher. The verifiers check hashes left by
+// algorithms that are no longer used.
+func NewManager(hasher Hasher, verifiers ...Verifier) *Manager {
+	manager := &Manager{
+		hasher:    hasher,
+		verifiers: map[string]Verifier{},
+	}
+	for _, verifier := range append([]Verifier{hasher}, verifiers...) {
+		for _, id := range verifier.IDs() {
+			manager.verifiers[id] = verifier
+		}
+		manager.decoys = append(manager.decoys, decoy{
+			verifier: verifier,
+			hash:     sync.OnceValue(func() string { return decoyHash(verifier) }),
+		})
+	}
+	return manager
+}
+
+func decoyHash(verifier Verifier) string {
+	/** Returns a hash of a random password made the way verifier's hashes are, or ""
+	when verifier cannot make one. */
+	var hashed string
+	switch verifier := verifier.(type) {
+	case Hasher:
+		hashed, _ = verifier.Hash(util.RandomString(16))
+	case decoyHasher:
+		hashed, _ = verifier.decoyHash(util.RandomString(16))
+	}
+	return hashed
+}
+
+// New creates the manager described by the config: argon2id with the configured
+// costs, falling back to DefaultArgon2idParams, and bcrypt for older hashes.
+func New(config util.Config) (*Manager, error) {
+	params := Argon2idParams{
+		Time:    config.Argon2Time,
+		Memory:  config.Argon2Memory,
+		Threads: config.Argon2Threads,
+	}
+	if params == (Argon2idParams{}) {
+		params = DefaultArgon2idParams
+	}
+
+	hasher, err := NewArgon2idHasher(params)
+	if err != nil {
+		return nil, err
+	}
+	return NewManager(hasher, NewBcryptVerifier()), nil
+}
+
+// Hashes password with the current algorithm and costs.
+func (manager *Manager) Hash(password string) (string, error) {
+	return manager.hasher.Hash(password)
+}
+
+// Check returns nil if password matches encoded. needsRehash is true when encoded
+// is outdated and should be replaced by a new hash of password. A hash that cannot
+// be used, like the empty one of a user who cannot log in, takes as long as a real
+// check and is reported as ErrMismatchedPassword too.
+func (manager *Manager) Check(password string, encoded string) (needsRehash bool, err error) {
+	verifier, ok := manager.verifiers[hashID(encoded)]
+	if !ok {
+		manager.checkDecoys(password, nil)
+		return false, fmt.Errorf("%w: %w", ErrMismatchedPassword, ErrUnsupportedHash)
+	}
+
+	err = verifier.Verify(password, encoded)
+	if errors.Is(err, ErrInvalidHash) {
+		manager.checkDecoys(password, nil)
+		return false, fmt.Errorf("%w: %w", ErrMismatchedPassword, err)
+	}
+	manager.checkDecoys(password, verifier)
+	if err != nil {
+		return false, err
+	}
+
+	return verifier != manager.hasher || manager.hasher.NeedsRehash(encoded), nil
+}
+
+// Spends the time Check would, so failed logins don't tell whether the username
+// exists.
+func (manager *Manager) CheckUnknownUser(password string) {
+	manager.checkDecoys(password, nil)
+}
+
+func (manager *Manager) checkDecoys(password string, checked Verifier) {
+	/** Verifies password against the decoy of every algorithm but checked's. */
+	for _, decoy := range manager.decoys {
+		if decoy.verifier == checked {
+			continue
+		}
+		if hashed := decoy.hash(); hashed != "" {
+			decoy.verifier.Verify(password, hashed)
+		}
+	}
+}
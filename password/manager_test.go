@@ -0,0 +1,136 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestManager(t *testing.T, params Argon2idParams) *Manager {
+	hasher, err := NewArgon2idHasher(params)
+	require.NoError(t, err)
+	return NewManager(hasher, NewBcryptVerifier())
+}
+
+func TestManagerCheck(t *testing.T) {
+	manager := newTestManager(t, testArgon2idParams)
+	password := util.RandomString(8)
+
+	encoded, err := manager.Hash(password)
+	require.NoError(t, err)
+
+	needsRehash, err := manager.Check(password, encoded)
+	require.NoError(t, err)
+	require.False(t, needsRehash)
+
+	_, err = manager.Check(util.RandomString(8), encoded)
+	require.ErrorIs(t, err, ErrMismatchedPassword)
+}
+
+func TestManagerRehashesBcrypt(t *testing.T) {
+	manager := newTestManager(t, testArgon2idParams)
+	password := util.RandomString(8)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	needsRehash, err := manager.Check(password, string(hashed))
+	require.NoError(t, err)
+	require.True(t, needsRehash)
+
+	_, err = manager.Check(util.RandomString(8), string(hashed))
+	require.ErrorIs(t, err, ErrMismatchedPassword)
+}
+
+func TestManagerRehashesOutdatedCosts(t *testing.T) {
+	password := util.RandomString(8)
+
+	encoded, err := newTestManager(t, testArgon2idParams).Hash(password)
+	require.NoError(t, err)
+
+	manager := newTestManager(t, Argon2idParams{Time: 2, Memory: 2048, Threads: 1})
+	needsRehash, err := manager.Check(password, encoded)
+	require.NoError(t, err)
+	require.True(t, needsRehash)
+}
+
+func TestManagerUnsupportedHash(t *testing.T) {
+	manager := newTestManager(t, testArgon2idParams)
+
+	_, err := manager.Check("secret", "$scrypt$ln=15,r=8,p=1$c2FsdA$a2V5")
+	require.ErrorIs(t, err, ErrUnsupportedHash)
+
+	_, err = manager.Check("secret", "plaintext")
+	require.ErrorIs(t, err, ErrUnsupportedHash)
+}
+
+func TestNew(t *testing.T) {
+	// Costs that aren't configured are the defaults
+	manager, err := New(util.Config{})
+	require.NoError(t, err)
+
+	encoded, err := manager.Hash(util.RandomString(8))
+	require.NoError(t, err)
+	require.False(t, newTestManager(t, DefaultArgon2idParams).hasher.NeedsRehash(encoded))
+
+	_, err = New(util.Config{Argon2Time: 1, Argon2Memory: 1024})
+	require.Error(t, err)
+}
+
+// countingVerifier counts the hashes it verifies, standing in for an old algorithm
+type countingVerifier struct {
+	verified *int
+}
+
+func (verifier countingVerifier) IDs() []string {
+	return []string{"counting"}
+}
+
+func (verifier countingVerifier) decoyHash(password string) (string, error) {
+	return "$counting$" + password, nil
+}
+
+func (verifier countingVerifier) Verify(password string, encoded string) error {
+	*verifier.verified++
+	if encoded != "$counting$"+password {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+func TestManagerChecksEveryAlgorithm(t *testing.T) {
+	hasher, err := NewArgon2idHasher(testArgon2idParams)
+	require.NoError(t, err)
+	var verified int
+	manager := NewManager(hasher, countingVerifier{verified: &verified})
+
+	password := util.RandomString(8)
+	encoded, err := manager.Hash(password)
+	require.NoError(t, err)
+
+	// Users of either algorithm and unknown users all cost one hash of each
+	_, err = manager.Check(password, encoded)
+	require.NoError(t, err)
+	require.Equal(t, 1, verified)
+
+	needsRehash, err := manager.Check(password, "$counting$"+password)
+	require.NoError(t, err)
+	require.True(t, needsRehash)
+	require.Equal(t, 2, verified)
+
+	_, err = manager.Check(util.RandomString(8), "$counting$"+password)
+	require.ErrorIs(t, err, ErrMismatchedPassword)
+	require.Equal(t, 3, verified)
+
+	manager.CheckUnknownUser(password)
+	require.Equal(t, 4, verified)
+
+	// A hash that cannot be used costs the same and is a mismatch
+	for _, encoded := range []string{"", "$scrypt$ln=15,r=8,p=1$c2FsdA$a2V5"} {
+		_, err = manager.Check(password, encoded)
+		require.ErrorIs(t, err, ErrMismatchedPassword)
+	}
+	require.Equal(t, 6, verified)
+}
@@ -0,0 +1,155 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jasonwebb3152/simplebank/util"
+)
+
+const (
+	DefaultMinLength  = 12
+	DefaultMinClasses = 3
+	// Longer passwords don't make sense and only cost hashing time
+	maxLength = 100
+	// Parts of the user's details shorter than this are too common to reject
+	minSimilarLength = 3
+)
+
+// PolicyUser holds the details of the user a password is for, it must not contain them
+type PolicyUser struct {
+	Username string
+	Email    string
+	FullName string
+}
+
+// Policy decides which passwords users may choose
+type Policy struct {
+	minLength  int
+	minClasses int
+	// nil when no breached password list is configured
+	breached BreachedList
+}
+
+// Creates a new Policy. breached may be nil to skip the breached password check.
+func NewPolicy(minLength int, minClasses int, breached BreachedList) (*Policy, error) {
+	if minLength < 1 || minLength > maxLength {
+		return nil, fmt.Errorf("invalid minimum password length: must be between 1 and %d", maxLength)
+	}
+	if minClasses < 1 || minClasses > 4 {
+		return nil, fmt.Errorf("invalid minimum password character classes: must be between 1 and 4")
+	}
+	return &Policy{
+		minLength:  minLength,
+		minClasses: minClasses,
+		breached:   breached,
+	}, nil
+}
+
+// NewPolicyFromConfig creates the policy described by the config. Settings that
+// aren't configured get their defaults, and the breached password check only runs
+// with a breached password list.
+func NewPolicyFromConfig(config util.Config) (*Policy, error) {
+	minLength := config.PasswordMinLength
+	if minLength == 0 {
+		minLength = DefaultMinLength
+	}
+	minClasses := config.PasswordMinClasses
+	if minClasses == 0 {
+		minClasses = DefaultMinClasses
+	}
+
+	var breached BreachedList
+	if config.PasswordBreachedDir != "" {
+		var err error
+		breached, err = NewRangeDir(config.PasswordBreachedDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewPolicy(minLength, minClasses, breached)
+}
+
+// Check returns every rule plain breaks, so users can fix them all at once. err is
+// only set when the breached password list could not be read.
+func (policy *Policy) Check(plain string, user PolicyUser) (violations []error, err error) {
+	length := len([]rune(plain))
+	if length < policy.minLength || length > maxLength {
+		violations = append(violations, fmt.Errorf("must contain from %d-%d characters", policy.minLength, maxLength))
+	}
+
+	if classes := characterClasses(plain); classes < policy.minClasses {
+		violations = append(violations, fmt.Errorf(
+			"must contain at least %d of lowercase letters, uppercase letters, digits and symbols",
+			policy.minClasses,
+		))
+	}
+
+	violations = append(violations, similarities(plain, user)...)
+
+	if policy.breached != nil {
+		breached, err := policy.breached.Contains(plain)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check breached password list: %w", err)
+		}
+		if breached {
+			violations = append(violations, errors.New("has appeared in a data breach, choose another one"))
+		}
+	}
+	return violations, nil
+}
+
+func characterClasses(plain string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, found := range []bool{lower, upper, digit, symbol} {
+		if found {
+			classes++
+		}
+	}
+	return classes
+}
+
+func similarities(plain string, user PolicyUser) (violations []error) {
+	/** Rejects passwords containing the username, the local part of the email or a
+	word of the full name, ignoring case. */
+	plain = strings.ToLower(plain)
+	contains := func(parts ...string) bool {
+		for _, part := range parts {
+			part = strings.ToLower(part)
+			if len(part) >= minSimilarLength && strings.Contains(plain, part) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if contains(user.Username) {
+		violations = append(violations, errors.New("must not contain the username"))
+	}
+
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	if contains(localPart) {
+		violations = append(violations, errors.New("must not contain the email address"))
+	}
+
+	if contains(strings.Fields(user.FullName)...) {
+		violations = append(violations, errors.New("must not contain the full name"))
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	breached, err := NewRangeDir(writeRangeDir(t, "Summer2024!Summer"))
+	require.NoError(t, err)
+
+	policy, err := NewPolicy(12, 3, breached)
+	require.NoError(t, err)
+
+	user := PolicyUser{
+		Username: "jason_webb",
+		Email:    "jwebb@example.com",
+		FullName: "Jason Webb",
+	}
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations []string
+	}{
+		{
+			name:     "OK",
+			password: "Correct-Horse-42",
+		},
+		{
+			name:     "TooShort",
+			password: "Ab1!",
+			violations: []string{
+				"must contain from 12-100 characters",
+			},
+		},
+		{
+			name:     "TooFewClasses",
+			password: "correcthorsebattery",
+			violations: []string{
+				"must contain at least 3 of lowercase letters, uppercase letters, digits and symbols",
+			},
+		},
+		{
+			name:     "SimilarToUser",
+			password: "JWEBB-jason_webb-1",
+			violations: []string{
+				"must not contain the username",
+				"must not contain the email address",
+				"must not contain the full name",
+			},
+		},
+		{
+			name:     "Breached",
+			password: "Summer2024!Summer",
+			violations: []string{
+				"has appeared in a data breach, choose another one",
+			},
+		},
+		{
+			name:     "EveryRule",
+			password: "jason",
+			violations: []string{
+				"must contain from 12-100 characters",
+				"must contain at least 3 of lowercase letters, uppercase letters, digits and symbols",
+				"must not contain the full name",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := policy.Check(tc.password, user)
+			require.NoError(t, err)
+
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.Error())
+			}
+			require.Equal(t, tc.violations, got)
+		})
+	}
+}
+
+func TestNewPolicyFromConfig(t *testing.T) {
+	// Without a breached password list, only the other rules apply
+	policy, err := NewPolicyFromConfig(util.Config{})
+	require.NoError(t, err)
+
+	violations, err := policy.Check("Summer2024!Summer", PolicyUser{})
+	require.NoError(t, err)
+	require.Empty(t, violations)
+
+	_, err = NewPolicyFromConfig(util.Config{PasswordMinClasses: 5})
+	require.Error(t, err)
+
+	_, err = NewPolicyFromConfig(util.Config{PasswordBreachedDir: t.TempDir() + "/missing"})
+	require.Error(t, err)
+}
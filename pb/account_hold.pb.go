@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: account_hold.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AccountHold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FromAccountId int64                  `protobuf:"varint,2,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	// Zero for the hold reserving a payroll job's total
+	ToAccountId   int64                  `protobuf:"varint,3,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	TransferId    *int64                 `protobuf:"varint,6,opt,name=transfer_id,json=transferId,proto3,oneof" json:"transfer_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ReleasedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=released_at,json=releasedAt,proto3,oneof" json:"released_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountHold) Reset() {
+	*x = AccountHold{}
+	mi := &file_account_hold_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountHold) ProtoMessage() {}
+
+func (x *AccountHold) ProtoReflect() protoreflect.Message {
+	mi := &file_account_hold_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountHold.ProtoReflect.Descriptor instead.
+func (*AccountHold) Descriptor() ([]byte, []int) {
+	return file_account_hold_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AccountHold) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AccountHold) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *AccountHold) GetToAccountId() int64 {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return 0
+}
+
+func (x *AccountHold) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AccountHold) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AccountHold) GetTransferId() int64 {
+	if x != nil && x.TransferId != nil {
+		return *x.TransferId
+	}
+	return 0
+}
+
+func (x *AccountHold) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *AccountHold) GetReleasedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleasedAt
+	}
+	return nil
+}
+
+func (x *AccountHold) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_account_hold_proto protoreflect.FileDescriptor
+
+const file_account_hold_proto_rawDesc = "" +
+	"\n" +
+	"\x12account_hold.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x03\n" +
+	"\vAccountHold\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12&\n" +
+	"\x0ffrom_account_id\x18\x02 \x01(\x03R\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x03 \x01(\x03R\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12$\n" +
+	"\vtransfer_id\x18\x06 \x01(\x03H\x00R\n" +
+	"transferId\x88\x01\x01\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12@\n" +
+	"\vreleased_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampH\x01R\n" +
+	"releasedAt\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\x0e\n" +
+	"\f_transfer_idB\x0e\n" +
+	"\f_released_atB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_account_hold_proto_rawDescOnce sync.Once
+	file_account_hold_proto_rawDescData []byte
+)
+
+func file_account_hold_proto_rawDescGZIP() []byte {
+	file_account_hold_proto_rawDescOnce.Do(func() {
+		file_account_hold_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_account_hold_proto_rawDesc), len(file_account_hold_proto_rawDesc)))
+	})
+	return file_account_hold_proto_rawDescData
+}
+
+var file_account_hold_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_account_hold_proto_goTypes = []any{
+	(*AccountHold)(nil),           // 0: pb.AccountHold
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_account_hold_proto_depIdxs = []int32{
+	1, // 0: pb.AccountHold.expires_at:type_name -> google.protobuf.Timestamp
+	1, // 1: pb.AccountHold.released_at:type_name -> google.protobuf.Timestamp
+	1, // 2: pb.AccountHold.created_at:type_name -> google.protobuf.Timestamp
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_account_hold_proto_init() }
+func file_account_hold_proto_init() {
+	if File_account_hold_proto != nil {
+		return
+	}
+	file_account_hold_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_account_hold_proto_rawDesc), len(file_account_hold_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_account_hold_proto_goTypes,
+		DependencyIndexes: file_account_hold_proto_depIdxs,
+		MessageInfos:      file_account_hold_proto_msgTypes,
+	}.Build()
+	File_account_hold_proto = out.File
+	file_account_hold_proto_goTypes = nil
+	file_account_hold_proto_depIdxs = nil
+}
@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: interest_rate.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InterestRate struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Currency         string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	AccountType      string                 `protobuf:"bytes,3,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`
+	AnnualRate       string                 `protobuf:"bytes,4,opt,name=annual_rate,json=annualRate,proto3" json:"annual_rate,omitempty"`
+	DayCount         string                 `protobuf:"bytes,5,opt,name=day_count,json=dayCount,proto3" json:"day_count,omitempty"`
+	ExpenseAccountId int64                  `protobuf:"varint,6,opt,name=expense_account_id,json=expenseAccountId,proto3" json:"expense_account_id,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *InterestRate) Reset() {
+	*x = InterestRate{}
+	mi := &file_interest_rate_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterestRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterestRate) ProtoMessage() {}
+
+func (x *InterestRate) ProtoReflect() protoreflect.Message {
+	mi := &file_interest_rate_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterestRate.ProtoReflect.Descriptor instead.
+func (*InterestRate) Descriptor() ([]byte, []int) {
+	return file_interest_rate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InterestRate) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InterestRate) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *InterestRate) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *InterestRate) GetAnnualRate() string {
+	if x != nil {
+		return x.AnnualRate
+	}
+	return ""
+}
+
+func (x *InterestRate) GetDayCount() string {
+	if x != nil {
+		return x.DayCount
+	}
+	return ""
+}
+
+func (x *InterestRate) GetExpenseAccountId() int64 {
+	if x != nil {
+		return x.ExpenseAccountId
+	}
+	return 0
+}
+
+func (x *InterestRate) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_interest_rate_proto protoreflect.FileDescriptor
+
+const file_interest_rate_proto_rawDesc = "" +
+	"\n" +
+	"\x13interest_rate.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x84\x02\n" +
+	"\fInterestRate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12!\n" +
+	"\faccount_type\x18\x03 \x01(\tR\vaccountType\x12\x1f\n" +
+	"\vannual_rate\x18\x04 \x01(\tR\n" +
+	"annualRate\x12\x1b\n" +
+	"\tday_count\x18\x05 \x01(\tR\bdayCount\x12,\n" +
+	"\x12expense_account_id\x18\x06 \x01(\x03R\x10expenseAccountId\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_interest_rate_proto_rawDescOnce sync.Once
+	file_interest_rate_proto_rawDescData []byte
+)
+
+func file_interest_rate_proto_rawDescGZIP() []byte {
+	file_interest_rate_proto_rawDescOnce.Do(func() {
+		file_interest_rate_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_interest_rate_proto_rawDesc), len(file_interest_rate_proto_rawDesc)))
+	})
+	return file_interest_rate_proto_rawDescData
+}
+
+var file_interest_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_interest_rate_proto_goTypes = []any{
+	(*InterestRate)(nil),          // 0: pb.InterestRate
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_interest_rate_proto_depIdxs = []int32{
+	1, // 0: pb.InterestRate.created_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_interest_rate_proto_init() }
+func file_interest_rate_proto_init() {
+	if File_interest_rate_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_interest_rate_proto_rawDesc), len(file_interest_rate_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_interest_rate_proto_goTypes,
+		DependencyIndexes: file_interest_rate_proto_depIdxs,
+		MessageInfos:      file_interest_rate_proto_msgTypes,
+	}.Build()
+	File_interest_rate_proto = out.File
+	file_interest_rate_proto_goTypes = nil
+	file_interest_rate_proto_depIdxs = nil
+}
@@ -0,0 +1,272 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: payroll_job.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PayrollJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	FromAccountId int64                  `protobuf:"varint,3,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	TotalRows     int32                  `protobuf:"varint,6,opt,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"`
+	ProcessedRows int32                  `protobuf:"varint,7,opt,name=processed_rows,json=processedRows,proto3" json:"processed_rows,omitempty"`
+	Error         *string                `protobuf:"bytes,8,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayrollJob) Reset() {
+	*x = PayrollJob{}
+	mi := &file_payroll_job_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayrollJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayrollJob) ProtoMessage() {}
+
+func (x *PayrollJob) ProtoReflect() protoreflect.Message {
+	mi := &file_payroll_job_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayrollJob.ProtoReflect.Descriptor instead.
+func (*PayrollJob) Descriptor() ([]byte, []int) {
+	return file_payroll_job_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PayrollJob) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PayrollJob) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *PayrollJob) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *PayrollJob) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PayrollJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PayrollJob) GetTotalRows() int32 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *PayrollJob) GetProcessedRows() int32 {
+	if x != nil {
+		return x.ProcessedRows
+	}
+	return 0
+}
+
+func (x *PayrollJob) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *PayrollJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *PayrollJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type PayrollRowError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Line in the uploaded file, the header is line 1
+	RowNumber     int32  `protobuf:"varint,1,opt,name=row_number,json=rowNumber,proto3" json:"row_number,omitempty"`
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayrollRowError) Reset() {
+	*x = PayrollRowError{}
+	mi := &file_payroll_job_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayrollRowError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayrollRowError) ProtoMessage() {}
+
+func (x *PayrollRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_payroll_job_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayrollRowError.ProtoReflect.Descriptor instead.
+func (*PayrollRowError) Descriptor() ([]byte, []int) {
+	return file_payroll_job_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PayrollRowError) GetRowNumber() int32 {
+	if x != nil {
+		return x.RowNumber
+	}
+	return 0
+}
+
+func (x *PayrollRowError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_payroll_job_proto protoreflect.FileDescriptor
+
+const file_payroll_job_proto_rawDesc = "" +
+	"\n" +
+	"\x11payroll_job.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x02\n" +
+	"\n" +
+	"PayrollJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12&\n" +
+	"\x0ffrom_account_id\x18\x03 \x01(\x03R\rfromAccountId\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"total_rows\x18\x06 \x01(\x05R\ttotalRows\x12%\n" +
+	"\x0eprocessed_rows\x18\a \x01(\x05R\rprocessedRows\x12\x19\n" +
+	"\x05error\x18\b \x01(\tH\x00R\x05error\x88\x01\x01\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\b\n" +
+	"\x06_error\"F\n" +
+	"\x0fPayrollRowError\x12\x1d\n" +
+	"\n" +
+	"row_number\x18\x01 \x01(\x05R\trowNumber\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05errorB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_payroll_job_proto_rawDescOnce sync.Once
+	file_payroll_job_proto_rawDescData []byte
+)
+
+func file_payroll_job_proto_rawDescGZIP() []byte {
+	file_payroll_job_proto_rawDescOnce.Do(func() {
+		file_payroll_job_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payroll_job_proto_rawDesc), len(file_payroll_job_proto_rawDesc)))
+	})
+	return file_payroll_job_proto_rawDescData
+}
+
+var file_payroll_job_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_payroll_job_proto_goTypes = []any{
+	(*PayrollJob)(nil),            // 0: pb.PayrollJob
+	(*PayrollRowError)(nil),       // 1: pb.PayrollRowError
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_payroll_job_proto_depIdxs = []int32{
+	2, // 0: pb.PayrollJob.updated_at:type_name -> google.protobuf.Timestamp
+	2, // 1: pb.PayrollJob.created_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_payroll_job_proto_init() }
+func file_payroll_job_proto_init() {
+	if File_payroll_job_proto != nil {
+		return
+	}
+	file_payroll_job_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payroll_job_proto_rawDesc), len(file_payroll_job_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_payroll_job_proto_goTypes,
+		DependencyIndexes: file_payroll_job_proto_depIdxs,
+		MessageInfos:      file_payroll_job_proto_msgTypes,
+	}.Build()
+	File_payroll_job_proto = out.File
+	file_payroll_job_proto_goTypes = nil
+	file_payroll_job_proto_depIdxs = nil
+}
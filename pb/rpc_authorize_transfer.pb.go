@@ -0,0 +1,208 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_authorize_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AuthorizeTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId int64                  `protobuf:"varint,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId   int64                  `protobuf:"varint,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeTransferRequest) Reset() {
+	*x = AuthorizeTransferRequest{}
+	mi := &file_rpc_authorize_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeTransferRequest) ProtoMessage() {}
+
+func (x *AuthorizeTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_authorize_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeTransferRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_authorize_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthorizeTransferRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *AuthorizeTransferRequest) GetToAccountId() int64 {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return 0
+}
+
+func (x *AuthorizeTransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AuthorizeTransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type AuthorizeTransferResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Hold             *AccountHold           `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	AvailableBalance int64                  `protobuf:"varint,2,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AuthorizeTransferResponse) Reset() {
+	*x = AuthorizeTransferResponse{}
+	mi := &file_rpc_authorize_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeTransferResponse) ProtoMessage() {}
+
+func (x *AuthorizeTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_authorize_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeTransferResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizeTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_authorize_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AuthorizeTransferResponse) GetHold() *AccountHold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+func (x *AuthorizeTransferResponse) GetAvailableBalance() int64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+var File_rpc_authorize_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_authorize_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x1crpc_authorize_transfer.proto\x12\x02pb\x1a\x12account_hold.proto\"\x9a\x01\n" +
+	"\x18AuthorizeTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\x03R\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\x03R\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"m\n" +
+	"\x19AuthorizeTransferResponse\x12#\n" +
+	"\x04hold\x18\x01 \x01(\v2\x0f.pb.AccountHoldR\x04hold\x12+\n" +
+	"\x11available_balance\x18\x02 \x01(\x03R\x10availableBalanceB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_authorize_transfer_proto_rawDescOnce sync.Once
+	file_rpc_authorize_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_authorize_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_authorize_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_authorize_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_authorize_transfer_proto_rawDesc), len(file_rpc_authorize_transfer_proto_rawDesc)))
+	})
+	return file_rpc_authorize_transfer_proto_rawDescData
+}
+
+var file_rpc_authorize_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_authorize_transfer_proto_goTypes = []any{
+	(*AuthorizeTransferRequest)(nil),  // 0: pb.AuthorizeTransferRequest
+	(*AuthorizeTransferResponse)(nil), // 1: pb.AuthorizeTransferResponse
+	(*AccountHold)(nil),               // 2: pb.AccountHold
+}
+var file_rpc_authorize_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.AuthorizeTransferResponse.hold:type_name -> pb.AccountHold
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_authorize_transfer_proto_init() }
+func file_rpc_authorize_transfer_proto_init() {
+	if File_rpc_authorize_transfer_proto != nil {
+		return
+	}
+	file_account_hold_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_authorize_transfer_proto_rawDesc), len(file_rpc_authorize_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_authorize_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_authorize_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_authorize_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_authorize_transfer_proto = out.File
+	file_rpc_authorize_transfer_proto_goTypes = nil
+	file_rpc_authorize_transfer_proto_depIdxs = nil
+}
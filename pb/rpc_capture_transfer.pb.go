@@ -0,0 +1,196 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_capture_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CaptureTransferRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	HoldId int64                  `protobuf:"varint,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	// Leave empty to capture the full held amount
+	Amount        *int64 `protobuf:"varint,2,opt,name=amount,proto3,oneof" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureTransferRequest) Reset() {
+	*x = CaptureTransferRequest{}
+	mi := &file_rpc_capture_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureTransferRequest) ProtoMessage() {}
+
+func (x *CaptureTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_capture_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureTransferRequest.ProtoReflect.Descriptor instead.
+func (*CaptureTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_capture_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CaptureTransferRequest) GetHoldId() int64 {
+	if x != nil {
+		return x.HoldId
+	}
+	return 0
+}
+
+func (x *CaptureTransferRequest) GetAmount() int64 {
+	if x != nil && x.Amount != nil {
+		return *x.Amount
+	}
+	return 0
+}
+
+type CaptureTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hold          *AccountHold           `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	Transfer      *Transfer              `protobuf:"bytes,2,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureTransferResponse) Reset() {
+	*x = CaptureTransferResponse{}
+	mi := &file_rpc_capture_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureTransferResponse) ProtoMessage() {}
+
+func (x *CaptureTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_capture_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureTransferResponse.ProtoReflect.Descriptor instead.
+func (*CaptureTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_capture_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CaptureTransferResponse) GetHold() *AccountHold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+func (x *CaptureTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+var File_rpc_capture_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_capture_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x1arpc_capture_transfer.proto\x12\x02pb\x1a\x12account_hold.proto\x1a\x0etransfer.proto\"Y\n" +
+	"\x16CaptureTransferRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\x03R\x06holdId\x12\x1b\n" +
+	"\x06amount\x18\x02 \x01(\x03H\x00R\x06amount\x88\x01\x01B\t\n" +
+	"\a_amount\"h\n" +
+	"\x17CaptureTransferResponse\x12#\n" +
+	"\x04hold\x18\x01 \x01(\v2\x0f.pb.AccountHoldR\x04hold\x12(\n" +
+	"\btransfer\x18\x02 \x01(\v2\f.pb.TransferR\btransferB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_capture_transfer_proto_rawDescOnce sync.Once
+	file_rpc_capture_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_capture_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_capture_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_capture_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_capture_transfer_proto_rawDesc), len(file_rpc_capture_transfer_proto_rawDesc)))
+	})
+	return file_rpc_capture_transfer_proto_rawDescData
+}
+
+var file_rpc_capture_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_capture_transfer_proto_goTypes = []any{
+	(*CaptureTransferRequest)(nil),  // 0: pb.CaptureTransferRequest
+	(*CaptureTransferResponse)(nil), // 1: pb.CaptureTransferResponse
+	(*AccountHold)(nil),             // 2: pb.AccountHold
+	(*Transfer)(nil),                // 3: pb.Transfer
+}
+var file_rpc_capture_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.CaptureTransferResponse.hold:type_name -> pb.AccountHold
+	3, // 1: pb.CaptureTransferResponse.transfer:type_name -> pb.Transfer
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_capture_transfer_proto_init() }
+func file_rpc_capture_transfer_proto_init() {
+	if File_rpc_capture_transfer_proto != nil {
+		return
+	}
+	file_account_hold_proto_init()
+	file_transfer_proto_init()
+	file_rpc_capture_transfer_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_capture_transfer_proto_rawDesc), len(file_rpc_capture_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_capture_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_capture_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_capture_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_capture_transfer_proto = out.File
+	file_rpc_capture_transfer_proto_goTypes = nil
+	file_rpc_capture_transfer_proto_depIdxs = nil
+}
@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_create_payroll_job.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreatePayrollJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId int64                  `protobuf:"varint,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	// CSV with a header row: to_account_id or username, amount, currency and an optional memo
+	CsvData       string `protobuf:"bytes,2,opt,name=csv_data,json=csvData,proto3" json:"csv_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePayrollJobRequest) Reset() {
+	*x = CreatePayrollJobRequest{}
+	mi := &file_rpc_create_payroll_job_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePayrollJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayrollJobRequest) ProtoMessage() {}
+
+func (x *CreatePayrollJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_payroll_job_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayrollJobRequest.ProtoReflect.Descriptor instead.
+func (*CreatePayrollJobRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_create_payroll_job_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePayrollJobRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *CreatePayrollJobRequest) GetCsvData() string {
+	if x != nil {
+		return x.CsvData
+	}
+	return ""
+}
+
+type CreatePayrollJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *PayrollJob            `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePayrollJobResponse) Reset() {
+	*x = CreatePayrollJobResponse{}
+	mi := &file_rpc_create_payroll_job_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePayrollJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayrollJobResponse) ProtoMessage() {}
+
+func (x *CreatePayrollJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_payroll_job_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayrollJobResponse.ProtoReflect.Descriptor instead.
+func (*CreatePayrollJobResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_create_payroll_job_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePayrollJobResponse) GetJob() *PayrollJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+var File_rpc_create_payroll_job_proto protoreflect.FileDescriptor
+
+const file_rpc_create_payroll_job_proto_rawDesc = "" +
+	"\n" +
+	"\x1crpc_create_payroll_job.proto\x12\x02pb\x1a\x11payroll_job.proto\"\\\n" +
+	"\x17CreatePayrollJobRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\x03R\rfromAccountId\x12\x19\n" +
+	"\bcsv_data\x18\x02 \x01(\tR\acsvData\"<\n" +
+	"\x18CreatePayrollJobResponse\x12 \n" +
+	"\x03job\x18\x01 \x01(\v2\x0e.pb.PayrollJobR\x03jobB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_create_payroll_job_proto_rawDescOnce sync.Once
+	file_rpc_create_payroll_job_proto_rawDescData []byte
+)
+
+func file_rpc_create_payroll_job_proto_rawDescGZIP() []byte {
+	file_rpc_create_payroll_job_proto_rawDescOnce.Do(func() {
+		file_rpc_create_payroll_job_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_create_payroll_job_proto_rawDesc), len(file_rpc_create_payroll_job_proto_rawDesc)))
+	})
+	return file_rpc_create_payroll_job_proto_rawDescData
+}
+
+var file_rpc_create_payroll_job_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_create_payroll_job_proto_goTypes = []any{
+	(*CreatePayrollJobRequest)(nil),  // 0: pb.CreatePayrollJobRequest
+	(*CreatePayrollJobResponse)(nil), // 1: pb.CreatePayrollJobResponse
+	(*PayrollJob)(nil),               // 2: pb.PayrollJob
+}
+var file_rpc_create_payroll_job_proto_depIdxs = []int32{
+	2, // 0: pb.CreatePayrollJobResponse.job:type_name -> pb.PayrollJob
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_create_payroll_job_proto_init() }
+func file_rpc_create_payroll_job_proto_init() {
+	if File_rpc_create_payroll_job_proto != nil {
+		return
+	}
+	file_payroll_job_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_create_payroll_job_proto_rawDesc), len(file_rpc_create_payroll_job_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_create_payroll_job_proto_goTypes,
+		DependencyIndexes: file_rpc_create_payroll_job_proto_depIdxs,
+		MessageInfos:      file_rpc_create_payroll_job_proto_msgTypes,
+	}.Build()
+	File_rpc_create_payroll_job_proto = out.File
+	file_rpc_create_payroll_job_proto_goTypes = nil
+	file_rpc_create_payroll_job_proto_depIdxs = nil
+}
@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_export_audit_events.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportAuditEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *AuditEventFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// csv or json
+	Format        string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAuditEventsRequest) Reset() {
+	*x = ExportAuditEventsRequest{}
+	mi := &file_rpc_export_audit_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAuditEventsRequest) ProtoMessage() {}
+
+func (x *ExportAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_export_audit_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ExportAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_export_audit_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportAuditEventsRequest) GetFilter() *AuditEventFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ExportAuditEventsRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+var File_rpc_export_audit_events_proto protoreflect.FileDescriptor
+
+const file_rpc_export_audit_events_proto_rawDesc = "" +
+	"\n" +
+	"\x1drpc_export_audit_events.proto\x12\x02pb\x1a\x11audit_event.proto\"`\n" +
+	"\x18ExportAuditEventsRequest\x12,\n" +
+	"\x06filter\x18\x01 \x01(\v2\x14.pb.AuditEventFilterR\x06filter\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06formatB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_export_audit_events_proto_rawDescOnce sync.Once
+	file_rpc_export_audit_events_proto_rawDescData []byte
+)
+
+func file_rpc_export_audit_events_proto_rawDescGZIP() []byte {
+	file_rpc_export_audit_events_proto_rawDescOnce.Do(func() {
+		file_rpc_export_audit_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_export_audit_events_proto_rawDesc), len(file_rpc_export_audit_events_proto_rawDesc)))
+	})
+	return file_rpc_export_audit_events_proto_rawDescData
+}
+
+var file_rpc_export_audit_events_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_rpc_export_audit_events_proto_goTypes = []any{
+	(*ExportAuditEventsRequest)(nil), // 0: pb.ExportAuditEventsRequest
+	(*AuditEventFilter)(nil),         // 1: pb.AuditEventFilter
+}
+var file_rpc_export_audit_events_proto_depIdxs = []int32{
+	1, // 0: pb.ExportAuditEventsRequest.filter:type_name -> pb.AuditEventFilter
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_export_audit_events_proto_init() }
+func file_rpc_export_audit_events_proto_init() {
+	if File_rpc_export_audit_events_proto != nil {
+		return
+	}
+	file_audit_event_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_export_audit_events_proto_rawDesc), len(file_rpc_export_audit_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_export_audit_events_proto_goTypes,
+		DependencyIndexes: file_rpc_export_audit_events_proto_depIdxs,
+		MessageInfos:      file_rpc_export_audit_events_proto_msgTypes,
+	}.Build()
+	File_rpc_export_audit_events_proto = out.File
+	file_rpc_export_audit_events_proto_goTypes = nil
+	file_rpc_export_audit_events_proto_depIdxs = nil
+}
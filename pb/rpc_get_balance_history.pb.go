@@ -0,0 +1,272 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_get_balance_history.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetBalanceHistoryRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// First and last day (UTC) of the series, both included
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceHistoryRequest) Reset() {
+	*x = GetBalanceHistoryRequest{}
+	mi := &file_rpc_get_balance_history_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceHistoryRequest) ProtoMessage() {}
+
+func (x *GetBalanceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_balance_history_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_get_balance_history_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetBalanceHistoryRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *GetBalanceHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetBalanceHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type DailyBalance struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Midnight (UTC) at the start of the day
+	Date *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Balance at the end of the day, or the current balance for today
+	Balance       int64 `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyBalance) Reset() {
+	*x = DailyBalance{}
+	mi := &file_rpc_get_balance_history_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyBalance) ProtoMessage() {}
+
+func (x *DailyBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_balance_history_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyBalance.ProtoReflect.Descriptor instead.
+func (*DailyBalance) Descriptor() ([]byte, []int) {
+	return file_rpc_get_balance_history_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DailyBalance) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *DailyBalance) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type GetBalanceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balances      []*DailyBalance        `protobuf:"bytes,3,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceHistoryResponse) Reset() {
+	*x = GetBalanceHistoryResponse{}
+	mi := &file_rpc_get_balance_history_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceHistoryResponse) ProtoMessage() {}
+
+func (x *GetBalanceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_balance_history_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_get_balance_history_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetBalanceHistoryResponse) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *GetBalanceHistoryResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetBalanceHistoryResponse) GetBalances() []*DailyBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+var File_rpc_get_balance_history_proto protoreflect.FileDescriptor
+
+const file_rpc_get_balance_history_proto_rawDesc = "" +
+	"\n" +
+	"\x1drpc_get_balance_history.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x01\n" +
+	"\x18GetBalanceHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"X\n" +
+	"\fDailyBalance\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\"\x84\x01\n" +
+	"\x19GetBalanceHistoryResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12,\n" +
+	"\bbalances\x18\x03 \x03(\v2\x10.pb.DailyBalanceR\bbalancesB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_get_balance_history_proto_rawDescOnce sync.Once
+	file_rpc_get_balance_history_proto_rawDescData []byte
+)
+
+func file_rpc_get_balance_history_proto_rawDescGZIP() []byte {
+	file_rpc_get_balance_history_proto_rawDescOnce.Do(func() {
+		file_rpc_get_balance_history_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_get_balance_history_proto_rawDesc), len(file_rpc_get_balance_history_proto_rawDesc)))
+	})
+	return file_rpc_get_balance_history_proto_rawDescData
+}
+
+var file_rpc_get_balance_history_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_rpc_get_balance_history_proto_goTypes = []any{
+	(*GetBalanceHistoryRequest)(nil),  // 0: pb.GetBalanceHistoryRequest
+	(*DailyBalance)(nil),              // 1: pb.DailyBalance
+	(*GetBalanceHistoryResponse)(nil), // 2: pb.GetBalanceHistoryResponse
+	(*timestamppb.Timestamp)(nil),     // 3: google.protobuf.Timestamp
+}
+var file_rpc_get_balance_history_proto_depIdxs = []int32{
+	3, // 0: pb.GetBalanceHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	3, // 1: pb.GetBalanceHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	3, // 2: pb.DailyBalance.date:type_name -> google.protobuf.Timestamp
+	1, // 3: pb.GetBalanceHistoryResponse.balances:type_name -> pb.DailyBalance
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_get_balance_history_proto_init() }
+func file_rpc_get_balance_history_proto_init() {
+	if File_rpc_get_balance_history_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_get_balance_history_proto_rawDesc), len(file_rpc_get_balance_history_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_get_balance_history_proto_goTypes,
+		DependencyIndexes: file_rpc_get_balance_history_proto_depIdxs,
+		MessageInfos:      file_rpc_get_balance_history_proto_msgTypes,
+	}.Build()
+	File_rpc_get_balance_history_proto = out.File
+	file_rpc_get_balance_history_proto_goTypes = nil
+	file_rpc_get_balance_history_proto_depIdxs = nil
+}
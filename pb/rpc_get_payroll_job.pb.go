@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_get_payroll_job.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetPayrollJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPayrollJobRequest) Reset() {
+	*x = GetPayrollJobRequest{}
+	mi := &file_rpc_get_payroll_job_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayrollJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayrollJobRequest) ProtoMessage() {}
+
+func (x *GetPayrollJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_payroll_job_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayrollJobRequest.ProtoReflect.Descriptor instead.
+func (*GetPayrollJobRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_get_payroll_job_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetPayrollJobRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetPayrollJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *PayrollJob            `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	RowErrors     []*PayrollRowError     `protobuf:"bytes,2,rep,name=row_errors,json=rowErrors,proto3" json:"row_errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPayrollJobResponse) Reset() {
+	*x = GetPayrollJobResponse{}
+	mi := &file_rpc_get_payroll_job_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayrollJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayrollJobResponse) ProtoMessage() {}
+
+func (x *GetPayrollJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_payroll_job_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayrollJobResponse.ProtoReflect.Descriptor instead.
+func (*GetPayrollJobResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_get_payroll_job_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPayrollJobResponse) GetJob() *PayrollJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *GetPayrollJobResponse) GetRowErrors() []*PayrollRowError {
+	if x != nil {
+		return x.RowErrors
+	}
+	return nil
+}
+
+var File_rpc_get_payroll_job_proto protoreflect.FileDescriptor
+
+const file_rpc_get_payroll_job_proto_rawDesc = "" +
+	"\n" +
+	"\x19rpc_get_payroll_job.proto\x12\x02pb\x1a\x11payroll_job.proto\"&\n" +
+	"\x14GetPayrollJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"m\n" +
+	"\x15GetPayrollJobResponse\x12 \n" +
+	"\x03job\x18\x01 \x01(\v2\x0e.pb.PayrollJobR\x03job\x122\n" +
+	"\n" +
+	"row_errors\x18\x02 \x03(\v2\x13.pb.PayrollRowErrorR\trowErrorsB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_get_payroll_job_proto_rawDescOnce sync.Once
+	file_rpc_get_payroll_job_proto_rawDescData []byte
+)
+
+func file_rpc_get_payroll_job_proto_rawDescGZIP() []byte {
+	file_rpc_get_payroll_job_proto_rawDescOnce.Do(func() {
+		file_rpc_get_payroll_job_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_get_payroll_job_proto_rawDesc), len(file_rpc_get_payroll_job_proto_rawDesc)))
+	})
+	return file_rpc_get_payroll_job_proto_rawDescData
+}
+
+var file_rpc_get_payroll_job_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_get_payroll_job_proto_goTypes = []any{
+	(*GetPayrollJobRequest)(nil),  // 0: pb.GetPayrollJobRequest
+	(*GetPayrollJobResponse)(nil), // 1: pb.GetPayrollJobResponse
+	(*PayrollJob)(nil),            // 2: pb.PayrollJob
+	(*PayrollRowError)(nil),       // 3: pb.PayrollRowError
+}
+var file_rpc_get_payroll_job_proto_depIdxs = []int32{
+	2, // 0: pb.GetPayrollJobResponse.job:type_name -> pb.PayrollJob
+	3, // 1: pb.GetPayrollJobResponse.row_errors:type_name -> pb.PayrollRowError
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_get_payroll_job_proto_init() }
+func file_rpc_get_payroll_job_proto_init() {
+	if File_rpc_get_payroll_job_proto != nil {
+		return
+	}
+	file_payroll_job_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_get_payroll_job_proto_rawDesc), len(file_rpc_get_payroll_job_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_get_payroll_job_proto_goTypes,
+		DependencyIndexes: file_rpc_get_payroll_job_proto_depIdxs,
+		MessageInfos:      file_rpc_get_payroll_job_proto_msgTypes,
+	}.Build()
+	File_rpc_get_payroll_job_proto = out.File
+	file_rpc_get_payroll_job_proto_goTypes = nil
+	file_rpc_get_payroll_job_proto_depIdxs = nil
+}
@@ -0,0 +1,190 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_list_scheduled_transfer_runs.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListScheduledTransferRunsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PageId        int32                  `protobuf:"varint,2,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledTransferRunsRequest) Reset() {
+	*x = ListScheduledTransferRunsRequest{}
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledTransferRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledTransferRunsRequest) ProtoMessage() {}
+
+func (x *ListScheduledTransferRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledTransferRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledTransferRunsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListScheduledTransferRunsRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ListScheduledTransferRunsRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListScheduledTransferRunsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListScheduledTransferRunsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Runs          []*ScheduledTransferRun `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledTransferRunsResponse) Reset() {
+	*x = ListScheduledTransferRunsResponse{}
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledTransferRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledTransferRunsResponse) ProtoMessage() {}
+
+func (x *ListScheduledTransferRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledTransferRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledTransferRunsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListScheduledTransferRunsResponse) GetRuns() []*ScheduledTransferRun {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+var File_rpc_list_scheduled_transfer_runs_proto protoreflect.FileDescriptor
+
+const file_rpc_list_scheduled_transfer_runs_proto_rawDesc = "" +
+	"\n" +
+	"&rpc_list_scheduled_transfer_runs.proto\x12\x02pb\x1a\x18scheduled_transfer.proto\"h\n" +
+	" ListScheduledTransferRunsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\apage_id\x18\x02 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"Q\n" +
+	"!ListScheduledTransferRunsResponse\x12,\n" +
+	"\x04runs\x18\x01 \x03(\v2\x18.pb.ScheduledTransferRunR\x04runsB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescOnce sync.Once
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescData []byte
+)
+
+func file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP() []byte {
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescOnce.Do(func() {
+		file_rpc_list_scheduled_transfer_runs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_list_scheduled_transfer_runs_proto_rawDesc), len(file_rpc_list_scheduled_transfer_runs_proto_rawDesc)))
+	})
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescData
+}
+
+var file_rpc_list_scheduled_transfer_runs_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_list_scheduled_transfer_runs_proto_goTypes = []any{
+	(*ListScheduledTransferRunsRequest)(nil),  // 0: pb.ListScheduledTransferRunsRequest
+	(*ListScheduledTransferRunsResponse)(nil), // 1: pb.ListScheduledTransferRunsResponse
+	(*ScheduledTransferRun)(nil),              // 2: pb.ScheduledTransferRun
+}
+var file_rpc_list_scheduled_transfer_runs_proto_depIdxs = []int32{
+	2, // 0: pb.ListScheduledTransferRunsResponse.runs:type_name -> pb.ScheduledTransferRun
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_list_scheduled_transfer_runs_proto_init() }
+func file_rpc_list_scheduled_transfer_runs_proto_init() {
+	if File_rpc_list_scheduled_transfer_runs_proto != nil {
+		return
+	}
+	file_scheduled_transfer_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_list_scheduled_transfer_runs_proto_rawDesc), len(file_rpc_list_scheduled_transfer_runs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_list_scheduled_transfer_runs_proto_goTypes,
+		DependencyIndexes: file_rpc_list_scheduled_transfer_runs_proto_depIdxs,
+		MessageInfos:      file_rpc_list_scheduled_transfer_runs_proto_msgTypes,
+	}.Build()
+	File_rpc_list_scheduled_transfer_runs_proto = out.File
+	file_rpc_list_scheduled_transfer_runs_proto_goTypes = nil
+	file_rpc_list_scheduled_transfer_runs_proto_depIdxs = nil
+}
@@ -0,0 +1,194 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_replay_webhook_events.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReplayWebhookEventsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EndpointId int64                  `protobuf:"varint,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Events created at or after from and before to are sent again,
+	// including those that were already delivered or dead-lettered
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookEventsRequest) Reset() {
+	*x = ReplayWebhookEventsRequest{}
+	mi := &file_rpc_replay_webhook_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookEventsRequest) ProtoMessage() {}
+
+func (x *ReplayWebhookEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_replay_webhook_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookEventsRequest.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookEventsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_replay_webhook_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReplayWebhookEventsRequest) GetEndpointId() int64 {
+	if x != nil {
+		return x.EndpointId
+	}
+	return 0
+}
+
+func (x *ReplayWebhookEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReplayWebhookEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ReplayWebhookEventsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReplayedEvents int64                  `protobuf:"varint,1,opt,name=replayed_events,json=replayedEvents,proto3" json:"replayed_events,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookEventsResponse) Reset() {
+	*x = ReplayWebhookEventsResponse{}
+	mi := &file_rpc_replay_webhook_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookEventsResponse) ProtoMessage() {}
+
+func (x *ReplayWebhookEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_replay_webhook_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookEventsResponse.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookEventsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_replay_webhook_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReplayWebhookEventsResponse) GetReplayedEvents() int64 {
+	if x != nil {
+		return x.ReplayedEvents
+	}
+	return 0
+}
+
+var File_rpc_replay_webhook_events_proto protoreflect.FileDescriptor
+
+const file_rpc_replay_webhook_events_proto_rawDesc = "" +
+	"\n" +
+	"\x1frpc_replay_webhook_events.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x99\x01\n" +
+	"\x1aReplayWebhookEventsRequest\x12\x1f\n" +
+	"\vendpoint_id\x18\x01 \x01(\x03R\n" +
+	"endpointId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"F\n" +
+	"\x1bReplayWebhookEventsResponse\x12'\n" +
+	"\x0freplayed_events\x18\x01 \x01(\x03R\x0ereplayedEventsB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_replay_webhook_events_proto_rawDescOnce sync.Once
+	file_rpc_replay_webhook_events_proto_rawDescData []byte
+)
+
+func file_rpc_replay_webhook_events_proto_rawDescGZIP() []byte {
+	file_rpc_replay_webhook_events_proto_rawDescOnce.Do(func() {
+		file_rpc_replay_webhook_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_replay_webhook_events_proto_rawDesc), len(file_rpc_replay_webhook_events_proto_rawDesc)))
+	})
+	return file_rpc_replay_webhook_events_proto_rawDescData
+}
+
+var file_rpc_replay_webhook_events_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_replay_webhook_events_proto_goTypes = []any{
+	(*ReplayWebhookEventsRequest)(nil),  // 0: pb.ReplayWebhookEventsRequest
+	(*ReplayWebhookEventsResponse)(nil), // 1: pb.ReplayWebhookEventsResponse
+	(*timestamppb.Timestamp)(nil),       // 2: google.protobuf.Timestamp
+}
+var file_rpc_replay_webhook_events_proto_depIdxs = []int32{
+	2, // 0: pb.ReplayWebhookEventsRequest.from:type_name -> google.protobuf.Timestamp
+	2, // 1: pb.ReplayWebhookEventsRequest.to:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_replay_webhook_events_proto_init() }
+func file_rpc_replay_webhook_events_proto_init() {
+	if File_rpc_replay_webhook_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_replay_webhook_events_proto_rawDesc), len(file_rpc_replay_webhook_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_replay_webhook_events_proto_goTypes,
+		DependencyIndexes: file_rpc_replay_webhook_events_proto_depIdxs,
+		MessageInfos:      file_rpc_replay_webhook_events_proto_msgTypes,
+	}.Build()
+	File_rpc_replay_webhook_events_proto = out.File
+	file_rpc_replay_webhook_events_proto_goTypes = nil
+	file_rpc_replay_webhook_events_proto_depIdxs = nil
+}
@@ -0,0 +1,172 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_resume_scheduled_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ResumeScheduledTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeScheduledTransferRequest) Reset() {
+	*x = ResumeScheduledTransferRequest{}
+	mi := &file_rpc_resume_scheduled_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeScheduledTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeScheduledTransferRequest) ProtoMessage() {}
+
+func (x *ResumeScheduledTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_resume_scheduled_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeScheduledTransferRequest.ProtoReflect.Descriptor instead.
+func (*ResumeScheduledTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_resume_scheduled_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResumeScheduledTransferRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ResumeScheduledTransferResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledTransfer *ScheduledTransfer     `protobuf:"bytes,1,opt,name=scheduled_transfer,json=scheduledTransfer,proto3" json:"scheduled_transfer,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ResumeScheduledTransferResponse) Reset() {
+	*x = ResumeScheduledTransferResponse{}
+	mi := &file_rpc_resume_scheduled_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeScheduledTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeScheduledTransferResponse) ProtoMessage() {}
+
+func (x *ResumeScheduledTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_resume_scheduled_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeScheduledTransferResponse.ProtoReflect.Descriptor instead.
+func (*ResumeScheduledTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_resume_scheduled_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResumeScheduledTransferResponse) GetScheduledTransfer() *ScheduledTransfer {
+	if x != nil {
+		return x.ScheduledTransfer
+	}
+	return nil
+}
+
+var File_rpc_resume_scheduled_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_resume_scheduled_transfer_proto_rawDesc = "" +
+	"\n" +
+	"#rpc_resume_scheduled_transfer.proto\x12\x02pb\x1a\x18scheduled_transfer.proto\"0\n" +
+	"\x1eResumeScheduledTransferRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"g\n" +
+	"\x1fResumeScheduledTransferResponse\x12D\n" +
+	"\x12scheduled_transfer\x18\x01 \x01(\v2\x15.pb.ScheduledTransferR\x11scheduledTransferB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_resume_scheduled_transfer_proto_rawDescOnce sync.Once
+	file_rpc_resume_scheduled_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_resume_scheduled_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_resume_scheduled_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_resume_scheduled_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_resume_scheduled_transfer_proto_rawDesc), len(file_rpc_resume_scheduled_transfer_proto_rawDesc)))
+	})
+	return file_rpc_resume_scheduled_transfer_proto_rawDescData
+}
+
+var file_rpc_resume_scheduled_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_resume_scheduled_transfer_proto_goTypes = []any{
+	(*ResumeScheduledTransferRequest)(nil),  // 0: pb.ResumeScheduledTransferRequest
+	(*ResumeScheduledTransferResponse)(nil), // 1: pb.ResumeScheduledTransferResponse
+	(*ScheduledTransfer)(nil),               // 2: pb.ScheduledTransfer
+}
+var file_rpc_resume_scheduled_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.ResumeScheduledTransferResponse.scheduled_transfer:type_name -> pb.ScheduledTransfer
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_resume_scheduled_transfer_proto_init() }
+func file_rpc_resume_scheduled_transfer_proto_init() {
+	if File_rpc_resume_scheduled_transfer_proto != nil {
+		return
+	}
+	file_scheduled_transfer_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_resume_scheduled_transfer_proto_rawDesc), len(file_rpc_resume_scheduled_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_resume_scheduled_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_resume_scheduled_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_resume_scheduled_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_resume_scheduled_transfer_proto = out.File
+	file_rpc_resume_scheduled_transfer_proto_goTypes = nil
+	file_rpc_resume_scheduled_transfer_proto_depIdxs = nil
+}
@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_set_fee_schedule.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetFeeScheduleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Currency string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Only "transfer" is supported for now
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	FlatFee   int64  `protobuf:"varint,3,opt,name=flat_fee,json=flatFee,proto3" json:"flat_fee,omitempty"`
+	// Hundredths of a percent of the amount, rounded down
+	PercentageBps int32 `protobuf:"varint,4,opt,name=percentage_bps,json=percentageBps,proto3" json:"percentage_bps,omitempty"`
+	// Operations per calendar month (UTC) that are not charged
+	FreePerMonth int32 `protobuf:"varint,5,opt,name=free_per_month,json=freePerMonth,proto3" json:"free_per_month,omitempty"`
+	// Bank-owned account in the same currency that fees are paid into
+	RevenueAccountId int64 `protobuf:"varint,6,opt,name=revenue_account_id,json=revenueAccountId,proto3" json:"revenue_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SetFeeScheduleRequest) Reset() {
+	*x = SetFeeScheduleRequest{}
+	mi := &file_rpc_set_fee_schedule_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeScheduleRequest) ProtoMessage() {}
+
+func (x *SetFeeScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_set_fee_schedule_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeScheduleRequest.ProtoReflect.Descriptor instead.
+func (*SetFeeScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_set_fee_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetFeeScheduleRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetFlatFee() int64 {
+	if x != nil {
+		return x.FlatFee
+	}
+	return 0
+}
+
+func (x *SetFeeScheduleRequest) GetPercentageBps() int32 {
+	if x != nil {
+		return x.PercentageBps
+	}
+	return 0
+}
+
+func (x *SetFeeScheduleRequest) GetFreePerMonth() int32 {
+	if x != nil {
+		return x.FreePerMonth
+	}
+	return 0
+}
+
+func (x *SetFeeScheduleRequest) GetRevenueAccountId() int64 {
+	if x != nil {
+		return x.RevenueAccountId
+	}
+	return 0
+}
+
+type SetFeeScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeeSchedule   *FeeSchedule           `protobuf:"bytes,1,opt,name=fee_schedule,json=feeSchedule,proto3" json:"fee_schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeeScheduleResponse) Reset() {
+	*x = SetFeeScheduleResponse{}
+	mi := &file_rpc_set_fee_schedule_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeScheduleResponse) ProtoMessage() {}
+
+func (x *SetFeeScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_set_fee_schedule_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeScheduleResponse.ProtoReflect.Descriptor instead.
+func (*SetFeeScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_set_fee_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetFeeScheduleResponse) GetFeeSchedule() *FeeSchedule {
+	if x != nil {
+		return x.FeeSchedule
+	}
+	return nil
+}
+
+var File_rpc_set_fee_schedule_proto protoreflect.FileDescriptor
+
+const file_rpc_set_fee_schedule_proto_rawDesc = "" +
+	"\n" +
+	"\x1arpc_set_fee_schedule.proto\x12\x02pb\x1a\x12fee_schedule.proto\"\xe7\x01\n" +
+	"\x15SetFeeScheduleRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12\x19\n" +
+	"\bflat_fee\x18\x03 \x01(\x03R\aflatFee\x12%\n" +
+	"\x0epercentage_bps\x18\x04 \x01(\x05R\rpercentageBps\x12$\n" +
+	"\x0efree_per_month\x18\x05 \x01(\x05R\ffreePerMonth\x12,\n" +
+	"\x12revenue_account_id\x18\x06 \x01(\x03R\x10revenueAccountId\"L\n" +
+	"\x16SetFeeScheduleResponse\x122\n" +
+	"\ffee_schedule\x18\x01 \x01(\v2\x0f.pb.FeeScheduleR\vfeeScheduleB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_set_fee_schedule_proto_rawDescOnce sync.Once
+	file_rpc_set_fee_schedule_proto_rawDescData []byte
+)
+
+func file_rpc_set_fee_schedule_proto_rawDescGZIP() []byte {
+	file_rpc_set_fee_schedule_proto_rawDescOnce.Do(func() {
+		file_rpc_set_fee_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_set_fee_schedule_proto_rawDesc), len(file_rpc_set_fee_schedule_proto_rawDesc)))
+	})
+	return file_rpc_set_fee_schedule_proto_rawDescData
+}
+
+var file_rpc_set_fee_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_set_fee_schedule_proto_goTypes = []any{
+	(*SetFeeScheduleRequest)(nil),  // 0: pb.SetFeeScheduleRequest
+	(*SetFeeScheduleResponse)(nil), // 1: pb.SetFeeScheduleResponse
+	(*FeeSchedule)(nil),            // 2: pb.FeeSchedule
+}
+var file_rpc_set_fee_schedule_proto_depIdxs = []int32{
+	2, // 0: pb.SetFeeScheduleResponse.fee_schedule:type_name -> pb.FeeSchedule
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_set_fee_schedule_proto_init() }
+func file_rpc_set_fee_schedule_proto_init() {
+	if File_rpc_set_fee_schedule_proto != nil {
+		return
+	}
+	file_fee_schedule_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_set_fee_schedule_proto_rawDesc), len(file_rpc_set_fee_schedule_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_set_fee_schedule_proto_goTypes,
+		DependencyIndexes: file_rpc_set_fee_schedule_proto_depIdxs,
+		MessageInfos:      file_rpc_set_fee_schedule_proto_msgTypes,
+	}.Build()
+	File_rpc_set_fee_schedule_proto = out.File
+	file_rpc_set_fee_schedule_proto_goTypes = nil
+	file_rpc_set_fee_schedule_proto_depIdxs = nil
+}
@@ -0,0 +1,223 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_update_account_status.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateAccountStatusRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// active, frozen or closed
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Only when freezing, also reject incoming transfers
+	FreezeCredits bool `protobuf:"varint,4,opt,name=freeze_credits,json=freezeCredits,proto3" json:"freeze_credits,omitempty"`
+	// Only when closing an account that still has money, another account of the same owner and currency
+	SweepToAccountId int64 `protobuf:"varint,5,opt,name=sweep_to_account_id,json=sweepToAccountId,proto3" json:"sweep_to_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateAccountStatusRequest) Reset() {
+	*x = UpdateAccountStatusRequest{}
+	mi := &file_rpc_update_account_status_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAccountStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAccountStatusRequest) ProtoMessage() {}
+
+func (x *UpdateAccountStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_account_status_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAccountStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAccountStatusRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_update_account_status_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateAccountStatusRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UpdateAccountStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateAccountStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateAccountStatusRequest) GetFreezeCredits() bool {
+	if x != nil {
+		return x.FreezeCredits
+	}
+	return false
+}
+
+func (x *UpdateAccountStatusRequest) GetSweepToAccountId() int64 {
+	if x != nil {
+		return x.SweepToAccountId
+	}
+	return 0
+}
+
+type UpdateAccountStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Change        *AccountStatusChange   `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAccountStatusResponse) Reset() {
+	*x = UpdateAccountStatusResponse{}
+	mi := &file_rpc_update_account_status_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAccountStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAccountStatusResponse) ProtoMessage() {}
+
+func (x *UpdateAccountStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_account_status_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAccountStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAccountStatusResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_update_account_status_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateAccountStatusResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *UpdateAccountStatusResponse) GetChange() *AccountStatusChange {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+var File_rpc_update_account_status_proto protoreflect.FileDescriptor
+
+const file_rpc_update_account_status_proto_rawDesc = "" +
+	"\n" +
+	"\x1frpc_update_account_status.proto\x12\x02pb\x1a\raccount.proto\"\xc1\x01\n" +
+	"\x1aUpdateAccountStatusRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12%\n" +
+	"\x0efreeze_credits\x18\x04 \x01(\bR\rfreezeCredits\x12-\n" +
+	"\x13sweep_to_account_id\x18\x05 \x01(\x03R\x10sweepToAccountId\"u\n" +
+	"\x1bUpdateAccountStatusResponse\x12%\n" +
+	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\x12/\n" +
+	"\x06change\x18\x02 \x01(\v2\x17.pb.AccountStatusChangeR\x06changeB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_update_account_status_proto_rawDescOnce sync.Once
+	file_rpc_update_account_status_proto_rawDescData []byte
+)
+
+func file_rpc_update_account_status_proto_rawDescGZIP() []byte {
+	file_rpc_update_account_status_proto_rawDescOnce.Do(func() {
+		file_rpc_update_account_status_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_update_account_status_proto_rawDesc), len(file_rpc_update_account_status_proto_rawDesc)))
+	})
+	return file_rpc_update_account_status_proto_rawDescData
+}
+
+var file_rpc_update_account_status_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_update_account_status_proto_goTypes = []any{
+	(*UpdateAccountStatusRequest)(nil),  // 0: pb.UpdateAccountStatusRequest
+	(*UpdateAccountStatusResponse)(nil), // 1: pb.UpdateAccountStatusResponse
+	(*Account)(nil),                     // 2: pb.Account
+	(*AccountStatusChange)(nil),         // 3: pb.AccountStatusChange
+}
+var file_rpc_update_account_status_proto_depIdxs = []int32{
+	2, // 0: pb.UpdateAccountStatusResponse.account:type_name -> pb.Account
+	3, // 1: pb.UpdateAccountStatusResponse.change:type_name -> pb.AccountStatusChange
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_update_account_status_proto_init() }
+func file_rpc_update_account_status_proto_init() {
+	if File_rpc_update_account_status_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_update_account_status_proto_rawDesc), len(file_rpc_update_account_status_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_update_account_status_proto_goTypes,
+		DependencyIndexes: file_rpc_update_account_status_proto_depIdxs,
+		MessageInfos:      file_rpc_update_account_status_proto_msgTypes,
+	}.Build()
+	File_rpc_update_account_status_proto = out.File
+	file_rpc_update_account_status_proto_goTypes = nil
+	file_rpc_update_account_status_proto_depIdxs = nil
+}
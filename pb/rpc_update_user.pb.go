@@ -9,6 +9,7 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,11 +23,22 @@ const (
 )
 
 type UpdateUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password      *string                `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
-	FullName      *string                `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3,oneof" json:"full_name,omitempty"`
-	Email         *string                `protobuf:"bytes,4,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password *string                `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	FullName *string                `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3,oneof" json:"full_name,omitempty"`
+	Email    *string                `protobuf:"bytes,4,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	// E.164, like +14155552671
+	Phone   string   `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address *Address `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	// ISO 8601 date, like 1990-04-21
+	DateOfBirth string `protobuf:"bytes,7,opt,name=date_of_birth,json=dateOfBirth,proto3" json:"date_of_birth,omitempty"`
+	// ISO 3166-1 alpha-2 code, like US
+	Nationality string `protobuf:"bytes,8,opt,name=nationality,proto3" json:"nationality,omitempty"`
+	// Fields to update: password, full_name, email, phone, address, date_of_birth
+	// and nationality. A field in the mask that is empty in the request is cleared.
+	// Without a mask, the fields that are set in the request are updated.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,9,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -89,6 +101,41 @@ func (x *UpdateUserRequest) GetEmail() string {
 	return ""
 }
 
+func (x *UpdateUserRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetDateOfBirth() string {
+	if x != nil {
+		return x.DateOfBirth
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetNationality() string {
+	if x != nil {
+		return x.Nationality
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 type UpdateUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
@@ -138,12 +185,18 @@ var File_rpc_update_user_proto protoreflect.FileDescriptor
 const file_rpc_update_user_proto_rawDesc = "" +
 	"\n" +
 	"\x15rpc_update_user.proto\x12\x02pb\x1a\n" +
-	"user.proto\"\xb2\x01\n" +
+	"user.proto\x1a google/protobuf/field_mask.proto\"\xf2\x02\n" +
 	"\x11UpdateUserRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1f\n" +
 	"\bpassword\x18\x02 \x01(\tH\x00R\bpassword\x88\x01\x01\x12 \n" +
 	"\tfull_name\x18\x03 \x01(\tH\x01R\bfullName\x88\x01\x01\x12\x19\n" +
-	"\x05email\x18\x04 \x01(\tH\x02R\x05email\x88\x01\x01B\v\n" +
+	"\x05email\x18\x04 \x01(\tH\x02R\x05email\x88\x01\x01\x12\x14\n" +
+	"\x05phone\x18\x05 \x01(\tR\x05phone\x12%\n" +
+	"\aaddress\x18\x06 \x01(\v2\v.pb.AddressR\aaddress\x12\"\n" +
+	"\rdate_of_birth\x18\a \x01(\tR\vdateOfBirth\x12 \n" +
+	"\vnationality\x18\b \x01(\tR\vnationality\x12;\n" +
+	"\vupdate_mask\x18\t \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMaskB\v\n" +
 	"\t_passwordB\f\n" +
 	"\n" +
 	"_full_nameB\b\n" +
@@ -165,17 +218,21 @@ func file_rpc_update_user_proto_rawDescGZIP() []byte {
 
 var file_rpc_update_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_rpc_update_user_proto_goTypes = []any{
-	(*UpdateUserRequest)(nil),  // 0: pb.UpdateUserRequest
-	(*UpdateUserResponse)(nil), // 1: pb.UpdateUserResponse
-	(*User)(nil),               // 2: pb.User
+	(*UpdateUserRequest)(nil),     // 0: pb.UpdateUserRequest
+	(*UpdateUserResponse)(nil),    // 1: pb.UpdateUserResponse
+	(*Address)(nil),               // 2: pb.Address
+	(*fieldmaskpb.FieldMask)(nil), // 3: google.protobuf.FieldMask
+	(*User)(nil),                  // 4: pb.User
 }
 var file_rpc_update_user_proto_depIdxs = []int32{
-	2, // 0: pb.UpdateUserResponse.user:type_name -> pb.User
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 0: pb.UpdateUserRequest.address:type_name -> pb.Address
+	3, // 1: pb.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	4, // 2: pb.UpdateUserResponse.user:type_name -> pb.User
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_rpc_update_user_proto_init() }
@@ -0,0 +1,172 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: rpc_void_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VoidTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        int64                  `protobuf:"varint,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidTransferRequest) Reset() {
+	*x = VoidTransferRequest{}
+	mi := &file_rpc_void_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidTransferRequest) ProtoMessage() {}
+
+func (x *VoidTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_void_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidTransferRequest.ProtoReflect.Descriptor instead.
+func (*VoidTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_void_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VoidTransferRequest) GetHoldId() int64 {
+	if x != nil {
+		return x.HoldId
+	}
+	return 0
+}
+
+type VoidTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hold          *AccountHold           `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidTransferResponse) Reset() {
+	*x = VoidTransferResponse{}
+	mi := &file_rpc_void_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidTransferResponse) ProtoMessage() {}
+
+func (x *VoidTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_void_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidTransferResponse.ProtoReflect.Descriptor instead.
+func (*VoidTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_void_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VoidTransferResponse) GetHold() *AccountHold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+var File_rpc_void_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_void_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x17rpc_void_transfer.proto\x12\x02pb\x1a\x12account_hold.proto\".\n" +
+	"\x13VoidTransferRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\x03R\x06holdId\";\n" +
+	"\x14VoidTransferResponse\x12#\n" +
+	"\x04hold\x18\x01 \x01(\v2\x0f.pb.AccountHoldR\x04holdB(Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
+
+var (
+	file_rpc_void_transfer_proto_rawDescOnce sync.Once
+	file_rpc_void_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_void_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_void_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_void_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_void_transfer_proto_rawDesc), len(file_rpc_void_transfer_proto_rawDesc)))
+	})
+	return file_rpc_void_transfer_proto_rawDescData
+}
+
+var file_rpc_void_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_void_transfer_proto_goTypes = []any{
+	(*VoidTransferRequest)(nil),  // 0: pb.VoidTransferRequest
+	(*VoidTransferResponse)(nil), // 1: pb.VoidTransferResponse
+	(*AccountHold)(nil),          // 2: pb.AccountHold
+}
+var file_rpc_void_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.VoidTransferResponse.hold:type_name -> pb.AccountHold
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_void_transfer_proto_init() }
+func file_rpc_void_transfer_proto_init() {
+	if File_rpc_void_transfer_proto != nil {
+		return
+	}
+	file_account_hold_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_void_transfer_proto_rawDesc), len(file_rpc_void_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_void_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_void_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_void_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_void_transfer_proto = out.File
+	file_rpc_void_transfer_proto_goTypes = nil
+	file_rpc_void_transfer_proto_depIdxs = nil
+}
@@ -9,6 +9,7 @@ package pb
 import (
 	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -26,39 +27,163 @@ var File_service_simple_bank_proto protoreflect.FileDescriptor
 
 const file_service_simple_bank_proto_rawDesc = "" +
 	"\n" +
-	"\x19service_simple_bank.proto\x12\x02pb\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x15rpc_update_user.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xc9\x03\n" +
+	"\x19service_simple_bank.proto\x12\x02pb\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x1crpc_renew_access_token.proto\x1a\x15rpc_update_user.proto\x1a\x1arpc_reverse_transfer.proto\x1a\x1crpc_authorize_transfer.proto\x1a\x1arpc_capture_transfer.proto\x1a\x17rpc_void_transfer.proto\x1a#rpc_create_scheduled_transfer.proto\x1a\"rpc_list_scheduled_transfers.proto\x1a&rpc_list_scheduled_transfer_runs.proto\x1a\"rpc_pause_scheduled_transfer.proto\x1a#rpc_resume_scheduled_transfer.proto\x1a#rpc_cancel_scheduled_transfer.proto\x1a\x1crpc_create_payroll_job.proto\x1a\x19rpc_get_payroll_job.proto\x1a\x1crpc_set_transfer_limit.proto\x1a\x1arpc_set_fee_schedule.proto\x1a\x1brpc_set_interest_rate.proto\x1a\x1frpc_update_account_status.proto\x1a\x17rpc_get_statement.proto\x1a\x18rpc_get_balance_at.proto\x1a\x1drpc_get_balance_history.proto\x1a!rpc_create_webhook_endpoint.proto\x1a\x1frpc_replay_webhook_events.proto\x1a\x17rpc_watch_account.proto\x1a\x1brpc_list_audit_events.proto\x1a\x1drpc_export_audit_events.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xe50\n" +
 	"\n" +
 	"SimpleBank\x12\x8e\x01\n" +
 	"\n" +
 	"CreateUser\x12\x15.pb.CreateUserRequest\x1a\x16.pb.CreateUserResponse\"Q\x92A4\x12\x0fCreate new user\x1a!Use this API to create a new user\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/create_user\x12\xa0\x01\n" +
 	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x15.pb.LoginUserResponse\"f\x92AJ\x12\n" +
-	"Login user\x1a<Use this API to login user and get access and refresh tokens\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12\x86\x01\n" +
+	"Login user\x1a<Use this API to login user and get access and refresh tokens\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12\xa8\x02\n" +
+	"\x10RenewAccessToken\x12\x1b.pb.RenewAccessTokenRequest\x1a\x1c.pb.RenewAccessTokenResponse\"\xd8\x01\x92A\xb3\x01\x12\x12Renew access token\x1a\x9c\x01Use this API to get a new access token with a refresh token. Renewals from another client than the one that logged in are handled by the session risk policy\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/renew_access_token\x12\x86\x01\n" +
 	"\n" +
-	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"I\x92A,\x12\vUpdate user\x1a\x1dUse this API to update a user\x82\xd3\xe4\x93\x02\x14:\x01*2\x0f/v1/update_userB\x8d\x01\x92Ab\x12`\n" +
+	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"I\x92A,\x12\vUpdate user\x1a\x1dUse this API to update a user\x82\xd3\xe4\x93\x02\x14:\x01*2\x0f/v1/update_user\x12\xe2\x01\n" +
+	"\x0fReverseTransfer\x12\x1a.pb.ReverseTransferRequest\x1a\x1b.pb.ReverseTransferResponse\"\x95\x01\x92As\x12\x10Reverse transfer\x1a_Use this API to refund all or part of a transfer you received. Bankers can reverse any transfer\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/reverse_transfer\x12\xce\x01\n" +
+	"\x11AuthorizeTransfer\x12\x1c.pb.AuthorizeTransferRequest\x1a\x1d.pb.AuthorizeTransferResponse\"|\x92AX\x12\x12Authorize transfer\x1aBUse this API to reserve funds on your account for a later transfer\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/authorize_transfer\x12\xc3\x01\n" +
+	"\x0fCaptureTransfer\x12\x1a.pb.CaptureTransferRequest\x1a\x1b.pb.CaptureTransferResponse\"w\x92AU\x12\x10Capture transfer\x1aAUse this API to settle all or part of a hold placed in your favor\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/capture_transfer\x12\xbb\x01\n" +
+	"\fVoidTransfer\x12\x17.pb.VoidTransferRequest\x1a\x18.pb.VoidTransferResponse\"x\x92AY\x12\rVoid transfer\x1aHUse this API to cancel a hold placed in your favor and release the funds\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/void_transfer\x12\xea\x01\n" +
+	"\x17CreateScheduledTransfer\x12\".pb.CreateScheduledTransferRequest\x1a#.pb.CreateScheduledTransferResponse\"\x85\x01\x92AZ\x12\x19Create scheduled transfer\x1a=Use this API to set up a recurring transfer from your account\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/create_scheduled_transfer\x12\xec\x01\n" +
+	"\x16ListScheduledTransfers\x12!.pb.ListScheduledTransfersRequest\x1a\".pb.ListScheduledTransfersResponse\"\x8a\x01\x92A`\x12\x18List scheduled transfers\x1aDUse this API to list the scheduled transfers paid from your accounts\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/list_scheduled_transfers\x12\x8c\x02\n" +
+	"\x19ListScheduledTransferRuns\x12$.pb.ListScheduledTransferRunsRequest\x1a%.pb.ListScheduledTransferRunsResponse\"\xa1\x01\x92As\x12\x1cList scheduled transfer runs\x1aSUse this API to see when a scheduled transfer ran and whether each run went through\x82\xd3\xe4\x93\x02%:\x01*\" /v1/list_scheduled_transfer_runs\x12\xe5\x01\n" +
+	"\x16PauseScheduledTransfer\x12!.pb.PauseScheduledTransferRequest\x1a\".pb.PauseScheduledTransferResponse\"\x83\x01\x92AY\x12\x18Pause scheduled transfer\x1a=Use this API to stop a scheduled transfer until it is resumed\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/pause_scheduled_transfer\x12\xdf\x01\n" +
+	"\x17ResumeScheduledTransfer\x12\".pb.ResumeScheduledTransferRequest\x1a#.pb.ResumeScheduledTransferResponse\"{\x92AP\x12\x19Resume scheduled transfer\x1a3Use this API to restart a paused scheduled transfer\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/resume_scheduled_transfer\x12\xe1\x01\n" +
+	"\x17CancelScheduledTransfer\x12\".pb.CancelScheduledTransferRequest\x1a#.pb.CancelScheduledTransferResponse\"}\x92AR\x12\x19Cancel scheduled transfer\x1a5Use this API to permanently stop a scheduled transfer\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/cancel_scheduled_transfer\x12\xdd\x01\n" +
+	"\x10CreatePayrollJob\x12\x1b.pb.CreatePayrollJobRequest\x1a\x1c.pb.CreatePayrollJobResponse\"\x8d\x01\x92Ai\x12\x12Create payroll job\x1aSUse this API to upload a payroll CSV. Rows are validated and paid in the background\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/create_payroll_job\x12\xbd\x01\n" +
+	"\rGetPayrollJob\x12\x18.pb.GetPayrollJobRequest\x1a\x19.pb.GetPayrollJobResponse\"w\x92AV\x12\x0fGet payroll job\x1aCUse this API to poll the status of a payroll job and its row errors\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/get_payroll_job\x12\xdb\x01\n" +
+	"\x10SetTransferLimit\x12\x1b.pb.SetTransferLimitRequest\x1a\x1c.pb.SetTransferLimitResponse\"\x8b\x01\x92Ag\x12\x12Set transfer limit\x1aQUse this API to override the transfer limits of one user. Only bankers can use it\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/set_transfer_limit\x12\xde\x01\n" +
+	"\x0eSetFeeSchedule\x12\x19.pb.SetFeeScheduleRequest\x1a\x1a.pb.SetFeeScheduleResponse\"\x94\x01\x92Ar\x12\x10Set fee schedule\x1a^Use this API to set the fees charged for an operation in one currency. Only bankers can use it\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/set_fee_schedule\x12\xec\x01\n" +
+	"\x0fSetInterestRate\x12\x1a.pb.SetInterestRateRequest\x1a\x1b.pb.SetInterestRateResponse\"\x9f\x01\x92A|\x12\x11Set interest rate\x1agUse this API to set the interest earned by one type of account in one currency. Only bankers can use it\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/set_interest_rate\x12\x84\x02\n" +
+	"\x13UpdateAccountStatus\x12\x1e.pb.UpdateAccountStatusRequest\x1a\x1f.pb.UpdateAccountStatusResponse\"\xab\x01\x92A\x83\x01\x12\x15Update account status\x1ajUse this API to freeze, unfreeze, close or reopen an account. Depositors can only close their own accounts\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/update_account_status\x12\xd6\x01\n" +
+	"\fGetStatement\x12\x17.pb.GetStatementRequest\x1a\x14.google.api.HttpBody\"\x96\x01\x92Ah\x12\rGet statement\x1aWUse this API to download the statement of an account for any period as CSV, JSON or PDF\x82\xd3\xe4\x93\x02%\x12#/v1/accounts/{account_id}/statement\x12\xc5\x01\n" +
+	"\fGetBalanceAt\x12\x17.pb.GetBalanceAtRequest\x1a\x18.pb.GetBalanceAtResponse\"\x81\x01\x92AU\x12\x0eGet balance at\x1aCUse this API to get the balance an account had at any point in time\x82\xd3\xe4\x93\x02#\x12!/v1/accounts/{account_id}/balance\x12\xed\x01\n" +
+	"\x11GetBalanceHistory\x12\x1c.pb.GetBalanceHistoryRequest\x1a\x1d.pb.GetBalanceHistoryResponse\"\x9a\x01\x92Af\x12\x13Get balance history\x1aOUse this API to get the closing balance of an account for every day of a period\x82\xd3\xe4\x93\x02+\x12)/v1/accounts/{account_id}/balance_history\x12\xfe\x01\n" +
+	"\x15CreateWebhookEndpoint\x12 .pb.CreateWebhookEndpointRequest\x1a!.pb.CreateWebhookEndpointResponse\"\x9f\x01\x92Av\x12\x17Create webhook endpoint\x1a[Use this API to register a URL that domain events are delivered to. Only bankers can use it\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/create_webhook_endpoint\x12\xf9\x01\n" +
+	"\x13ReplayWebhookEvents\x12\x1e.pb.ReplayWebhookEventsRequest\x1a\x1f.pb.ReplayWebhookEventsResponse\"\xa0\x01\x92Ay\x12\x15Replay webhook events\x1a`Use this API to send the events of a period to a webhook endpoint again. Only bankers can use it\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/replay_webhook_events\x12\xd4\x01\n" +
+	"\fWatchAccount\x12\x17.pb.WatchAccountRequest\x1a\x18.pb.WatchAccountResponse\"\x8e\x01\x92Ad\x12\rWatch account\x1aSUse this API to follow new entries and balance changes of an account as they happen\x82\xd3\xe4\x93\x02!\x12\x1f/v1/accounts/{account_id}/watch0\x01\x12\xf5\x01\n" +
+	"\x0fListAuditEvents\x12\x1a.pb.ListAuditEventsRequest\x1a\x1b.pb.ListAuditEventsResponse\"\xa8\x01\x92A\x84\x01\x12\x11List audit events\x1aoUse this API to search the audit log of logins, user and account changes and transfers. Only bankers can use it\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/list_audit_events\x12\xe9\x01\n" +
+	"\x11ExportAuditEvents\x12\x1c.pb.ExportAuditEventsRequest\x1a\x14.google.api.HttpBody\"\x9f\x01\x92Az\x12\x13Export audit events\x1acUse this API to download the audit events matching a filter as CSV or JSON. Only bankers can use it\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/export_audit_eventsB\x8d\x01\x92Ab\x12`\n" +
 	"\x0fSimple Bank API\"H\n" +
 	"\n" +
 	"Jason Webb\x12 https://github.com/jasonwebb2455\x1a\x18jason.webb2455@gmail.com2\x031.2Z&github.com/jasonwebb3152/simplebank/pbb\x06proto3"
 
 var file_service_simple_bank_proto_goTypes = []any{
-	(*CreateUserRequest)(nil),  // 0: pb.CreateUserRequest
-	(*LoginUserRequest)(nil),   // 1: pb.LoginUserRequest
-	(*UpdateUserRequest)(nil),  // 2: pb.UpdateUserRequest
-	(*CreateUserResponse)(nil), // 3: pb.CreateUserResponse
-	(*LoginUserResponse)(nil),  // 4: pb.LoginUserResponse
-	(*UpdateUserResponse)(nil), // 5: pb.UpdateUserResponse
+	(*CreateUserRequest)(nil),                 // 0: pb.CreateUserRequest
+	(*LoginUserRequest)(nil),                  // 1: pb.LoginUserRequest
+	(*RenewAccessTokenRequest)(nil),           // 2: pb.RenewAccessTokenRequest
+	(*UpdateUserRequest)(nil),                 // 3: pb.UpdateUserRequest
+	(*ReverseTransferRequest)(nil),            // 4: pb.ReverseTransferRequest
+	(*AuthorizeTransferRequest)(nil),          // 5: pb.AuthorizeTransferRequest
+	(*CaptureTransferRequest)(nil),            // 6: pb.CaptureTransferRequest
+	(*VoidTransferRequest)(nil),               // 7: pb.VoidTransferRequest
+	(*CreateScheduledTransferRequest)(nil),    // 8: pb.CreateScheduledTransferRequest
+	(*ListScheduledTransfersRequest)(nil),     // 9: pb.ListScheduledTransfersRequest
+	(*ListScheduledTransferRunsRequest)(nil),  // 10: pb.ListScheduledTransferRunsRequest
+	(*PauseScheduledTransferRequest)(nil),     // 11: pb.PauseScheduledTransferRequest
+	(*ResumeScheduledTransferRequest)(nil),    // 12: pb.ResumeScheduledTransferRequest
+	(*CancelScheduledTransferRequest)(nil),    // 13: pb.CancelScheduledTransferRequest
+	(*CreatePayrollJobRequest)(nil),           // 14: pb.CreatePayrollJobRequest
+	(*GetPayrollJobRequest)(nil),              // 15: pb.GetPayrollJobRequest
+	(*SetTransferLimitRequest)(nil),           // 16: pb.SetTransferLimitRequest
+	(*SetFeeScheduleRequest)(nil),             // 17: pb.SetFeeScheduleRequest
+	(*SetInterestRateRequest)(nil),            // 18: pb.SetInterestRateRequest
+	(*UpdateAccountStatusRequest)(nil),        // 19: pb.UpdateAccountStatusRequest
+	(*GetStatementRequest)(nil),               // 20: pb.GetStatementRequest
+	(*GetBalanceAtRequest)(nil),               // 21: pb.GetBalanceAtRequest
+	(*GetBalanceHistoryRequest)(nil),          // 22: pb.GetBalanceHistoryRequest
+	(*CreateWebhookEndpointRequest)(nil),      // 23: pb.CreateWebhookEndpointRequest
+	(*ReplayWebhookEventsRequest)(nil),        // 24: pb.ReplayWebhookEventsRequest
+	(*WatchAccountRequest)(nil),               // 25: pb.WatchAccountRequest
+	(*ListAuditEventsRequest)(nil),            // 26: pb.ListAuditEventsRequest
+	(*ExportAuditEventsRequest)(nil),          // 27: pb.ExportAuditEventsRequest
+	(*CreateUserResponse)(nil),                // 28: pb.CreateUserResponse
+	(*LoginUserResponse)(nil),                 // 29: pb.LoginUserResponse
+	(*RenewAccessTokenResponse)(nil),          // 30: pb.RenewAccessTokenResponse
+	(*UpdateUserResponse)(nil),                // 31: pb.UpdateUserResponse
+	(*ReverseTransferResponse)(nil),           // 32: pb.ReverseTransferResponse
+	(*AuthorizeTransferResponse)(nil),         // 33: pb.AuthorizeTransferResponse
+	(*CaptureTransferResponse)(nil),           // 34: pb.CaptureTransferResponse
+	(*VoidTransferResponse)(nil),              // 35: pb.VoidTransferResponse
+	(*CreateScheduledTransferResponse)(nil),   // 36: pb.CreateScheduledTransferResponse
+	(*ListScheduledTransfersResponse)(nil),    // 37: pb.ListScheduledTransfersResponse
+	(*ListScheduledTransferRunsResponse)(nil), // 38: pb.ListScheduledTransferRunsResponse
+	(*PauseScheduledTransferResponse)(nil),    // 39: pb.PauseScheduledTransferResponse
+	(*ResumeScheduledTransferResponse)(nil),   // 40: pb.ResumeScheduledTransferResponse
+	(*CancelScheduledTransferResponse)(nil),   // 41: pb.CancelScheduledTransferResponse
+	(*CreatePayrollJobResponse)(nil),          // 42: pb.CreatePayrollJobResponse
+	(*GetPayrollJobResponse)(nil),             // 43: pb.GetPayrollJobResponse
+	(*SetTransferLimitResponse)(nil),          // 44: pb.SetTransferLimitResponse
+	(*SetFeeScheduleResponse)(nil),            // 45: pb.SetFeeScheduleResponse
+	(*SetInterestRateResponse)(nil),           // 46: pb.SetInterestRateResponse
+	(*UpdateAccountStatusResponse)(nil),       // 47: pb.UpdateAccountStatusResponse
+	(*httpbody.HttpBody)(nil),                 // 48: google.api.HttpBody
+	(*GetBalanceAtResponse)(nil),              // 49: pb.GetBalanceAtResponse
+	(*GetBalanceHistoryResponse)(nil),         // 50: pb.GetBalanceHistoryResponse
+	(*CreateWebhookEndpointResponse)(nil),     // 51: pb.CreateWebhookEndpointResponse
+	(*ReplayWebhookEventsResponse)(nil),       // 52: pb.ReplayWebhookEventsResponse
+	(*WatchAccountResponse)(nil),              // 53: pb.WatchAccountResponse
+	(*ListAuditEventsResponse)(nil),           // 54: pb.ListAuditEventsResponse
 }
 var file_service_simple_bank_proto_depIdxs = []int32{
-	0, // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
-	1, // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
-	2, // 2: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
-	3, // 3: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
-	4, // 4: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
-	5, // 5: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
+	1,  // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
+	2,  // 2: pb.SimpleBank.RenewAccessToken:input_type -> pb.RenewAccessTokenRequest
+	3,  // 3: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
+	4,  // 4: pb.SimpleBank.ReverseTransfer:input_type -> pb.ReverseTransferRequest
+	5,  // 5: pb.SimpleBank.AuthorizeTransfer:input_type -> pb.AuthorizeTransferRequest
+	6,  // 6: pb.SimpleBank.CaptureTransfer:input_type -> pb.CaptureTransferRequest
+	7,  // 7: pb.SimpleBank.VoidTransfer:input_type -> pb.VoidTransferRequest
+	8,  // 8: pb.SimpleBank.CreateScheduledTransfer:input_type -> pb.CreateScheduledTransferRequest
+	9,  // 9: pb.SimpleBank.ListScheduledTransfers:input_type -> pb.ListScheduledTransfersRequest
+	10, // 10: pb.SimpleBank.ListScheduledTransferRuns:input_type -> pb.ListScheduledTransferRunsRequest
+	11, // 11: pb.SimpleBank.PauseScheduledTransfer:input_type -> pb.PauseScheduledTransferRequest
+	12, // 12: pb.SimpleBank.ResumeScheduledTransfer:input_type -> pb.ResumeScheduledTransferRequest
+	13, // 13: pb.SimpleBank.CancelScheduledTransfer:input_type -> pb.CancelScheduledTransferRequest
+	14, // 14: pb.SimpleBank.CreatePayrollJob:input_type -> pb.CreatePayrollJobRequest
+	15, // 15: pb.SimpleBank.GetPayrollJob:input_type -> pb.GetPayrollJobRequest
+	16, // 16: pb.SimpleBank.SetTransferLimit:input_type -> pb.SetTransferLimitRequest
+	17, // 17: pb.SimpleBank.SetFeeSchedule:input_type -> pb.SetFeeScheduleRequest
+	18, // 18: pb.SimpleBank.SetInterestRate:input_type -> pb.SetInterestRateRequest
+	19, // 19: pb.SimpleBank.UpdateAccountStatus:input_type -> pb.UpdateAccountStatusRequest
+	20, // 20: pb.SimpleBank.GetStatement:input_type -> pb.GetStatementRequest
+	21, // 21: pb.SimpleBank.GetBalanceAt:input_type -> pb.GetBalanceAtRequest
+	22, // 22: pb.SimpleBank.GetBalanceHistory:input_type -> pb.GetBalanceHistoryRequest
+	23, // 23: pb.SimpleBank.CreateWebhookEndpoint:input_type -> pb.CreateWebhookEndpointRequest
+	24, // 24: pb.SimpleBank.ReplayWebhookEvents:input_type -> pb.ReplayWebhookEventsRequest
+	25, // 25: pb.SimpleBank.WatchAccount:input_type -> pb.WatchAccountRequest
+	26, // 26: pb.SimpleBank.ListAuditEvents:input_type -> pb.ListAuditEventsRequest
+	27, // 27: pb.SimpleBank.ExportAuditEvents:input_type -> pb.ExportAuditEventsRequest
+	28, // 28: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
+	29, // 29: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
+	30, // 30: pb.SimpleBank.RenewAccessToken:output_type -> pb.RenewAccessTokenResponse
+	31, // 31: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
+	32, // 32: pb.SimpleBank.ReverseTransfer:output_type -> pb.ReverseTransferResponse
+	33, // 33: pb.SimpleBank.AuthorizeTransfer:output_type -> pb.AuthorizeTransferResponse
+	34, // 34: pb.SimpleBank.CaptureTransfer:output_type -> pb.CaptureTransferResponse
+	35, // 35: pb.SimpleBank.VoidTransfer:output_type -> pb.VoidTransferResponse
+	36, // 36: pb.SimpleBank.CreateScheduledTransfer:output_type -> pb.CreateScheduledTransferResponse
+	37, // 37: pb.SimpleBank.ListScheduledTransfers:output_type -> pb.ListScheduledTransfersResponse
+	38, // 38: pb.SimpleBank.ListScheduledTransferRuns:output_type -> pb.ListScheduledTransferRunsResponse
+	39, // 39: pb.SimpleBank.PauseScheduledTransfer:output_type -> pb.PauseScheduledTransferResponse
+	40, // 40: pb.SimpleBank.ResumeScheduledTransfer:output_type -> pb.ResumeScheduledTransferResponse
+	41, // 41: pb.SimpleBank.CancelScheduledTransfer:output_type -> pb.CancelScheduledTransferResponse
+	42, // 42: pb.SimpleBank.CreatePayrollJob:output_type -> pb.CreatePayrollJobResponse
+	43, // 43: pb.SimpleBank.GetPayrollJob:output_type -> pb.GetPayrollJobResponse
+	44, // 44: pb.SimpleBank.SetTransferLimit:output_type -> pb.SetTransferLimitResponse
+	45, // 45: pb.SimpleBank.SetFeeSchedule:output_type -> pb.SetFeeScheduleResponse
+	46, // 46: pb.SimpleBank.SetInterestRate:output_type -> pb.SetInterestRateResponse
+	47, // 47: pb.SimpleBank.UpdateAccountStatus:output_type -> pb.UpdateAccountStatusResponse
+	48, // 48: pb.SimpleBank.GetStatement:output_type -> google.api.HttpBody
+	49, // 49: pb.SimpleBank.GetBalanceAt:output_type -> pb.GetBalanceAtResponse
+	50, // 50: pb.SimpleBank.GetBalanceHistory:output_type -> pb.GetBalanceHistoryResponse
+	51, // 51: pb.SimpleBank.CreateWebhookEndpoint:output_type -> pb.CreateWebhookEndpointResponse
+	52, // 52: pb.SimpleBank.ReplayWebhookEvents:output_type -> pb.ReplayWebhookEventsResponse
+	53, // 53: pb.SimpleBank.WatchAccount:output_type -> pb.WatchAccountResponse
+	54, // 54: pb.SimpleBank.ListAuditEvents:output_type -> pb.ListAuditEventsResponse
+	48, // 55: pb.SimpleBank.ExportAuditEvents:output_type -> google.api.HttpBody
+	28, // [28:56] is the sub-list for method output_type
+	0,  // [0:28] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_service_simple_bank_proto_init() }
@@ -68,7 +193,32 @@ func file_service_simple_bank_proto_init() {
 	}
 	file_rpc_create_user_proto_init()
 	file_rpc_login_user_proto_init()
+	file_rpc_renew_access_token_proto_init()
 	file_rpc_update_user_proto_init()
+	file_rpc_reverse_transfer_proto_init()
+	file_rpc_authorize_transfer_proto_init()
+	file_rpc_capture_transfer_proto_init()
+	file_rpc_void_transfer_proto_init()
+	file_rpc_create_scheduled_transfer_proto_init()
+	file_rpc_list_scheduled_transfers_proto_init()
+	file_rpc_list_scheduled_transfer_runs_proto_init()
+	file_rpc_pause_scheduled_transfer_proto_init()
+	file_rpc_resume_scheduled_transfer_proto_init()
+	file_rpc_cancel_scheduled_transfer_proto_init()
+	file_rpc_create_payroll_job_proto_init()
+	file_rpc_get_payroll_job_proto_init()
+	file_rpc_set_transfer_limit_proto_init()
+	file_rpc_set_fee_schedule_proto_init()
+	file_rpc_set_interest_rate_proto_init()
+	file_rpc_update_account_status_proto_init()
+	file_rpc_get_statement_proto_init()
+	file_rpc_get_balance_at_proto_init()
+	file_rpc_get_balance_history_proto_init()
+	file_rpc_create_webhook_endpoint_proto_init()
+	file_rpc_replay_webhook_events_proto_init()
+	file_rpc_watch_account_proto_init()
+	file_rpc_list_audit_events_proto_init()
+	file_rpc_export_audit_events_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+// pruner runs Prune at most once per pruneInterval, from whichever call comes first.
+type pruner struct {
+	last atomic.Int64
+}
+
+func (p *pruner) maybePrune(ctx context.Context, backend Backend) {
+	now := time.Now().UnixNano()
+	last := p.last.Load()
+	if now-last < int64(pruneInterval) || !p.last.CompareAndSwap(last, now) {
+		return
+	}
+
+	if err := backend.Prune(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot prune rate limits")
+	}
+}
+
+// MemoryBackend keeps the buckets of this process only, so every replica applies
+// the limits on its own.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	now      func() time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		limiters: make(map[string]*rate.Limiter),
+		now:      time.Now,
+	}
+}
+
+func (backend *MemoryBackend) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	limiter, ok := backend.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		backend.limiters[key] = limiter
+	}
+
+	now := backend.now()
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	return result, nil
+}
+
+func (backend *MemoryBackend) Prune(ctx context.Context) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	now := backend.now()
+	for key, limiter := range backend.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(backend.limiters, key)
+		}
+	}
+	return nil
+}
+
+// PostgresBackend shares the buckets between every replica. Each bucket is a single
+// row updated atomically, see TakeRateLimitToken.
+type PostgresBackend struct {
+	store db.Querier
+}
+
+func NewPostgresBackend(store db.Querier) *PostgresBackend {
+	return &PostgresBackend{
+		store: store,
+	}
+}
+
+func (backend *PostgresBackend) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	// Every call pushes the time the bucket is full back by one interval, and the
+	// bucket is empty once that time is more than burst intervals away
+	interval := 1 / limit.Rate
+	tolerance := float64(limit.Burst) * interval
+
+	secondsUntilFull, err := backend.store.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:              key,
+		EmissionInterval: interval,
+		Tolerance:        tolerance,
+	})
+	if err == nil {
+		return Result{
+			Allowed:   true,
+			Limit:     limit.Burst,
+			Remaining: int(math.Floor((tolerance - secondsUntilFull) / interval)),
+			Reset:     secondsToDuration(secondsUntilFull),
+		}, nil
+	}
+	if err != sql.ErrNoRows {
+		return Result{}, err
+	}
+
+	// The bucket is empty
+	secondsUntilFull, err = backend.store.GetRateLimitSecondsUntilFull(ctx, key)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Allowed:    false,
+		Limit:      limit.Burst,
+		Remaining:  0,
+		Reset:      secondsToDuration(secondsUntilFull),
+		RetryAfter: secondsToDuration(secondsUntilFull + interval - tolerance),
+	}, nil
+}
+
+func (backend *PostgresBackend) Prune(ctx context.Context) error {
+	_, err := backend.store.DeleteFullRateLimits(ctx)
+	return err
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,191 @@
+// Package ratelimit limits how often a user or client IP may call each method,
+// with token buckets kept in memory or in Postgres.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// DefaultMethod is the name of the limit used by methods without one of their own
+const DefaultMethod = "*"
+
+// Standard rate limit headers. Reset and Retry-After are in seconds.
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// Full buckets are forgotten this often, they behave the same as missing ones
+const pruneInterval = time.Minute
+
+// Limit allows Rate calls per second on average, and up to Burst at once.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result tells whether a call is allowed and how much of the limit is left.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Headers returns the rate limit headers describing the result.
+func (result Result) Headers() map[string]string {
+	headers := map[string]string{
+		HeaderLimit:     strconv.Itoa(result.Limit),
+		HeaderRemaining: strconv.Itoa(result.Remaining),
+		HeaderReset:     strconv.Itoa(ceilSeconds(result.Reset)),
+	}
+	if !result.Allowed {
+		headers[HeaderRetryAfter] = strconv.Itoa(ceilSeconds(result.RetryAfter))
+	}
+	return headers
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Backend keeps the token buckets.
+type Backend interface {
+	// Take removes a token from the bucket of key if there is one.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Prune forgets the buckets that are full.
+	Prune(ctx context.Context) error
+}
+
+// Limiter applies the configured limit of each method.
+type Limiter struct {
+	backend Backend
+	limits  map[string]Limit
+	pruner  pruner
+}
+
+func NewLimiter(backend Backend, limits map[string]Limit) *Limiter {
+	return &Limiter{
+		backend: backend,
+		limits:  limits,
+	}
+}
+
+// New creates the limiter described by the config. The Postgres backend shares the
+// buckets between every replica.
+func New(config util.Config, store db.Querier) (*Limiter, error) {
+	limits, err := ParseLimits(config.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.RateLimitBackend {
+	case BackendMemory, "":
+		return NewLimiter(NewMemoryBackend(), limits), nil
+	case BackendPostgres:
+		return NewLimiter(NewPostgresBackend(store), limits), nil
+	}
+	return nil, fmt.Errorf("unsupported rate limit backend %q", config.RateLimitBackend)
+}
+
+// Allow takes a token for a call of method by the caller identified by key. ok is
+// false when no limit applies to the method. The call is let through when the
+// backend fails, so an outage of the database does not take the whole API with it.
+func (limiter *Limiter) Allow(ctx context.Context, method string, key string) (result Result, ok bool) {
+	limit, ok := limiter.limits[method]
+	if !ok {
+		limit, ok = limiter.limits[DefaultMethod]
+		if !ok {
+			return Result{Allowed: true}, false
+		}
+	}
+
+	limiter.pruner.maybePrune(ctx, limiter.backend)
+
+	result, err := limiter.backend.Take(ctx, method+":"+key, limit)
+	if err != nil {
+		log.Error().Err(err).Str("method", method).Msg("cannot check rate limit")
+		return Result{Allowed: true}, false
+	}
+	return result, true
+}
+
+// ParseLimits reads limits written as "LoginUser=5/m:10,*=20/s", meaning 5 calls a
+// minute with bursts of 10 for LoginUser and 20 a second for every other method.
+// The burst defaults to the number of calls.
+func ParseLimits(value string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		method, spec, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rate limit %q: want method=calls/unit", item)
+		}
+
+		limit, err := parseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit %q: %w", item, err)
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+	return limits, nil
+}
+
+func parseLimit(spec string) (Limit, error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+
+	count, unit, found := strings.Cut(rate, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("missing unit")
+	}
+
+	calls, err := strconv.Atoi(count)
+	if err != nil || calls <= 0 {
+		return Limit{}, fmt.Errorf("calls must be a positive number")
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("unit must be s, m or h")
+	}
+
+	limit := Limit{
+		Rate:  float64(calls) / period.Seconds(),
+		Burst: calls,
+	}
+	if hasBurst {
+		limit.Burst, err = strconv.Atoi(burst)
+		if err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("burst must be a positive number")
+		}
+	}
+	return limit, nil
+}
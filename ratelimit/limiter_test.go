@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("LoginUser=5/m:10, *=20/s,CreateUser=3/h")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Limit{
+		"LoginUser":  {Rate: 5.0 / 60, Burst: 10},
+		"*":          {Rate: 20, Burst: 20},
+		"CreateUser": {Rate: 3.0 / 3600, Burst: 3},
+	}, limits)
+
+	limits, err = ParseLimits("")
+	require.NoError(t, err)
+	require.Empty(t, limits)
+
+	for _, value := range []string{"LoginUser", "LoginUser=5", "LoginUser=5/d", "LoginUser=0/s", "LoginUser=5/s:x", "LoginUser=5/s:0"} {
+		_, err = ParseLimits(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	backend := NewMemoryBackend()
+	now := time.Now()
+	backend.now = func() time.Time { return now }
+
+	// A token every 10 seconds, 2 at once
+	limit := Limit{Rate: 0.1, Burst: 2}
+
+	result, err := backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Limit)
+	require.Equal(t, 1, result.Remaining)
+	require.Equal(t, 10*time.Second, result.Reset)
+
+	result, err = backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	result, err = backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 10*time.Second, result.RetryAfter)
+	require.Equal(t, 20*time.Second, result.Reset)
+
+	// Other keys have their own bucket
+	result, err = backend.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	now = now.Add(10 * time.Second)
+	result, err = backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// Only buckets that refilled completely are forgotten
+	now = now.Add(15 * time.Second)
+	require.NoError(t, backend.Prune(context.Background()))
+	require.Len(t, backend.limiters, 1)
+	require.Contains(t, backend.limiters, "key")
+}
+
+func TestPostgresBackend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	backend := NewPostgresBackend(store)
+
+	// A token every 10 seconds, 3 at once
+	limit := Limit{Rate: 0.1, Burst: 3}
+	arg := db.TakeRateLimitTokenParams{
+		Key:              "key",
+		EmissionInterval: 10,
+		Tolerance:        30,
+	}
+
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(20.0, nil)
+
+	result, err := backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		Allowed:   true,
+		Limit:     3,
+		Remaining: 1,
+		Reset:     20 * time.Second,
+	}, result)
+
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(0.0, sql.ErrNoRows)
+	store.EXPECT().
+		GetRateLimitSecondsUntilFull(gomock.Any(), gomock.Eq("key")).
+		Times(1).
+		Return(26.0, nil)
+
+	result, err = backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		Allowed:    false,
+		Limit:      3,
+		Remaining:  0,
+		Reset:      26 * time.Second,
+		RetryAfter: 6 * time.Second,
+	}, result)
+}
+
+func TestLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limiter := NewLimiter(NewMemoryBackend(), map[string]Limit{
+		"LoginUser":   {Rate: 1, Burst: 1},
+		DefaultMethod: {Rate: 1, Burst: 2},
+	})
+
+	result, limited := limiter.Allow(context.Background(), "LoginUser", "ip:1.2.3.4")
+	require.True(t, limited)
+	require.True(t, result.Allowed)
+	require.Equal(t, 1, result.Limit)
+
+	result, limited = limiter.Allow(context.Background(), "LoginUser", "ip:1.2.3.4")
+	require.True(t, limited)
+	require.False(t, result.Allowed)
+
+	// Every method has its own bucket, even those sharing the default limit
+	result, _ = limiter.Allow(context.Background(), "GetAccount", "ip:1.2.3.4")
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Limit)
+
+	// Without a limit, calls go through untouched
+	limiter = NewLimiter(NewMemoryBackend(), nil)
+	result, limited = limiter.Allow(context.Background(), "LoginUser", "ip:1.2.3.4")
+	require.False(t, limited)
+	require.True(t, result.Allowed)
+
+	// A failing backend lets calls through
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().DeleteFullRateLimits(gomock.Any()).AnyTimes().Return(int64(0), nil)
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(0.0, errors.New("connection refused"))
+
+	limiter = NewLimiter(NewPostgresBackend(store), map[string]Limit{DefaultMethod: {Rate: 1, Burst: 1}})
+	result, limited = limiter.Allow(context.Background(), "LoginUser", "ip:1.2.3.4")
+	require.False(t, limited)
+	require.True(t, result.Allowed)
+}
+
+func TestHeaders(t *testing.T) {
+	result := Result{
+		Allowed:   true,
+		Limit:     10,
+		Remaining: 4,
+		Reset:     1500 * time.Millisecond,
+	}
+	require.Equal(t, map[string]string{
+		HeaderLimit:     "10",
+		HeaderRemaining: "4",
+		HeaderReset:     "2",
+	}, result.Headers())
+
+	result = Result{
+		Allowed:    false,
+		Limit:      10,
+		Reset:      time.Minute,
+		RetryAfter: 6 * time.Second,
+	}
+	require.Equal(t, map[string]string{
+		HeaderLimit:      "10",
+		HeaderRemaining:  "0",
+		HeaderReset:      "60",
+		HeaderRetryAfter: "6",
+	}, result.Headers())
+}
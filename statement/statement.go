@@ -0,0 +1,173 @@
+// Package statement renders account statements for download.
+package statement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatPDF  = "pdf"
+)
+
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatPDF:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of a rendered statement.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSON:
+		return "application/json"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Filename suggests a name for the downloaded statement.
+func Filename(statement db.Statement, format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s",
+		statement.Account.ID,
+		statement.From.UTC().Format("20060102"),
+		statement.To.UTC().Format("20060102"),
+		format,
+	)
+}
+
+// Render writes the statement to w in the given format. Amounts are in minor units
+// like everywhere else in the API.
+func Render(w io.Writer, statement db.Statement, format string) error {
+	switch format {
+	case FormatCSV:
+		return renderCSV(w, statement)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(statement)
+	case FormatPDF:
+		return renderPDF(w, statement)
+	}
+	return fmt.Errorf("unsupported statement format %q", format)
+}
+
+func renderCSV(w io.Writer, statement db.Statement) error {
+	/** One row per line of the statement. The type column tells entries apart from
+	the opening, closing and total rows so the file stays easy to import. */
+	writer := csv.NewWriter(w)
+	amount := func(value int64) string {
+		return strconv.FormatInt(value, 10)
+	}
+
+	records := [][]string{
+		{"type", "entry_id", "created_at", "amount", "balance"},
+		{"opening_balance", "", formatTime(statement.From), "", amount(statement.OpeningBalance)},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			"entry",
+			strconv.FormatInt(line.Entry.ID, 10),
+			formatTime(line.Entry.CreatedAt),
+			amount(line.Entry.Amount),
+			amount(line.Balance),
+		})
+	}
+	records = append(records,
+		[]string{"total_credits", "", "", amount(statement.TotalCredits), ""},
+		[]string{"total_debits", "", "", amount(-statement.TotalDebits), ""},
+		[]string{"closing_balance", "", formatTime(statement.To), "", amount(statement.ClosingBalance)},
+	)
+
+	return writer.WriteAll(records)
+}
+
+func renderPDF(w io.Writer, statement db.Statement) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Statement of account %d", statement.Account.ID), true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Account statement")
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "", 10)
+	summary := [][2]string{
+		{"Account", strconv.FormatInt(statement.Account.ID, 10)},
+		{"Owner", statement.Account.Owner},
+		{"Currency", statement.Account.Currency},
+		{"Period", fmt.Sprintf("%s to %s", formatTime(statement.From), formatTime(statement.To))},
+		{"Opening balance", strconv.FormatInt(statement.OpeningBalance, 10)},
+	}
+	for _, row := range summary {
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, row[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	widths := []float64{60, 30, 50, 50}
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 10)
+		for i, title := range []string{"Date", "Entry", "Amount", "Balance"} {
+			pdf.CellFormat(widths[i], 7, title, "B", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 10)
+	}
+	header()
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	for _, line := range statement.Lines {
+		// Repeat the column titles at the top of every page
+		if pdf.GetY()+6 > pageHeight-bottomMargin-15 {
+			pdf.AddPage()
+			header()
+		}
+		cells := []string{
+			formatTime(line.Entry.CreatedAt),
+			strconv.FormatInt(line.Entry.ID, 10),
+			strconv.FormatInt(line.Entry.Amount, 10),
+			strconv.FormatInt(line.Balance, 10),
+		}
+		for i, cell := range cells {
+			pdf.CellFormat(widths[i], 6, cell, "", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	totals := [][2]string{
+		{"Total credits", strconv.FormatInt(statement.TotalCredits, 10)},
+		{"Total debits", strconv.FormatInt(statement.TotalDebits, 10)},
+		{"Closing balance", strconv.FormatInt(statement.ClosingBalance, 10)},
+	}
+	for _, row := range totals {
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, row[1], "", 1, "L", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
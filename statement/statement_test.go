@@ -0,0 +1,91 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomStatement(lines int) db.Statement {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	statement := db.Statement{
+		Account: db.Account{
+			ID:       util.RandomInt(1, 1000),
+			Owner:    util.RandomOwner(),
+			Currency: util.USD,
+		},
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+	}
+
+	balance := statement.OpeningBalance
+	for i := 0; i < lines; i++ {
+		amount := util.RandomInt(-100, 100)
+		balance += amount
+		if amount > 0 {
+			statement.TotalCredits += amount
+		} else {
+			statement.TotalDebits -= amount
+		}
+		statement.Lines = append(statement.Lines, db.StatementLine{
+			Entry: db.Entry{
+				ID:        int64(i + 1),
+				AccountID: statement.Account.ID,
+				Amount:    amount,
+				CreatedAt: from.Add(time.Duration(i) * time.Hour),
+			},
+			Balance: balance,
+		})
+	}
+	statement.ClosingBalance = balance
+	return statement
+}
+
+func TestRenderCSV(t *testing.T) {
+	statement := randomStatement(3)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, statement, FormatCSV))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	// Header, opening, entries, two totals and closing
+	require.Len(t, records, 1+1+3+3)
+	require.Equal(t, []string{"opening_balance", "", "2025-01-01T00:00:00Z", "", "1000"}, records[1])
+	require.Equal(t, "entry", records[2][0])
+	require.Equal(t, "closing_balance", records[7][0])
+}
+
+func TestRenderJSON(t *testing.T) {
+	statement := randomStatement(3)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, statement, FormatJSON))
+
+	var decoded db.Statement
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, statement.ClosingBalance, decoded.ClosingBalance)
+	require.Len(t, decoded.Lines, 3)
+}
+
+func TestRenderPDF(t *testing.T) {
+	// Enough lines to need several pages
+	statement := randomStatement(120)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, statement, FormatPDF))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	require.Error(t, Render(&buf, randomStatement(0), "xlsx"))
+	require.False(t, IsSupportedFormat("xlsx"))
+}
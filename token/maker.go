@@ -4,8 +4,8 @@ import "time"
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// Creates new token for specified user and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// Creates new token for specified user, role and duration
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
 
 	// Checks if token is valid or not.
 	VerifyToken(token string) (*Payload, error)
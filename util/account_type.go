@@ -0,0 +1,17 @@
+package util
+
+const (
+	CheckingAccount = "checking"
+	SavingsAccount  = "savings"
+	// Ledger accounts the bank keeps for itself, customers cannot open them
+	RevenueAccount = "revenue"
+	ExpenseAccount = "expense"
+)
+
+func IsCustomerAccountType(accountType string) bool {
+	switch accountType {
+	case CheckingAccount, SavingsAccount:
+		return true
+	}
+	return false
+}
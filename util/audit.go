@@ -0,0 +1,46 @@
+package util
+
+// Actions recorded in the audit log
+const (
+	AuditUserLogin           = "user.login"
+	AuditUserUpdated         = "user.updated"
+	AuditAccountCreated      = "account.created"
+	AuditAccountClosed       = "account.closed"
+	AuditAccountStatusChange = "account.status_changed"
+	AuditTransferCreated     = "transfer.created"
+	AuditSessionSuspicious   = "session.suspicious"
+)
+
+// Kinds of targets of audited actions
+const (
+	AuditTargetUser     = "user"
+	AuditTargetAccount  = "account"
+	AuditTargetTransfer = "transfer"
+	AuditTargetSession  = "session"
+)
+
+// Outcomes of audited actions
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// Reasons a login failed, also the labels of the login failure metric
+const (
+	AuditReasonUnknownUser   = "unknown_user"
+	AuditReasonWrongPassword = "wrong_password"
+)
+
+// Actors of audited actions that no user is signed in for
+const (
+	AuditActorAnonymous = "anonymous"
+	AuditActorSystem    = "system"
+)
+
+func IsSupportedAuditOutcome(outcome string) bool {
+	switch outcome {
+	case AuditSuccess, AuditFailure:
+		return true
+	}
+	return false
+}
@@ -14,9 +14,51 @@ type Config struct {
 	MigrationURL         string        `mapstructure:"MIGRATION_URL"`
 	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
 	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	MetricsServerAddress string        `mapstructure:"METRICS_SERVER_ADDRESS"`
+	ShutdownTimeout      time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	RateLimitBackend     string        `mapstructure:"RATE_LIMIT_BACKEND"`
+	RateLimits           string        `mapstructure:"RATE_LIMITS"`
+	// Addresses and CIDR ranges whose X-Forwarded-For is believed, including the
+	// gateway's own address when it dials the gRPC server
+	TrustedProxies       string        `mapstructure:"TRUSTED_PROXIES"`
+	TLSCertFile          string        `mapstructure:"TLS_CERT_FILE"`
+	TLSKeyFile           string        `mapstructure:"TLS_KEY_FILE"`
+	TLSClientCAFile      string        `mapstructure:"TLS_CLIENT_CA_FILE"`
+	TLSClientNames       string        `mapstructure:"TLS_CLIENT_NAMES"`
+	TLSRootCAFile        string        `mapstructure:"TLS_ROOT_CA_FILE"`
+	TLSServerName        string        `mapstructure:"TLS_SERVER_NAME"`
+	HealthCheckInterval  time.Duration `mapstructure:"HEALTH_CHECK_INTERVAL"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	SessionRiskPolicy    string        `mapstructure:"SESSION_RISK_POLICY"`
+	// Costs of new password hashes, argon2id's memory is in KiB
+	Argon2Time           uint32        `mapstructure:"PASSWORD_ARGON2_TIME"`
+	Argon2Memory         uint32        `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	Argon2Threads        uint8         `mapstructure:"PASSWORD_ARGON2_THREADS"`
+	PasswordMinLength    int           `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMinClasses   int           `mapstructure:"PASSWORD_MIN_CLASSES"`
+	PasswordBreachedDir  string        `mapstructure:"PASSWORD_BREACHED_DIR"`
+	HoldTTL              time.Duration `mapstructure:"HOLD_TTL"`
+	HoldSweepInterval    time.Duration `mapstructure:"HOLD_SWEEP_INTERVAL"`
+	SchedulerInterval    time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	PayrollInterval      time.Duration `mapstructure:"PAYROLL_INTERVAL"`
+	PayrollChunkSize     int32         `mapstructure:"PAYROLL_CHUNK_SIZE"`
+	InterestInterval     time.Duration `mapstructure:"INTEREST_INTERVAL"`
+	InterestLookbackDays int           `mapstructure:"INTEREST_LOOKBACK_DAYS"`
+	SnapshotInterval     time.Duration `mapstructure:"SNAPSHOT_INTERVAL"`
+	SnapshotLookbackDays int           `mapstructure:"SNAPSHOT_LOOKBACK_DAYS"`
+	WebhookInterval      time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	WebhookTimeout       time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts   int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	TraceExporter        string        `mapstructure:"TRACE_EXPORTER"`
+	OTLPEndpoint         string        `mapstructure:"OTLP_ENDPOINT"`
+	OTLPInsecure         bool          `mapstructure:"OTLP_INSECURE"`
+	// Accept http endpoints on private addresses, for local development only
+	WebhookAllowInsecure bool `mapstructure:"WEBHOOK_ALLOW_INSECURE"`
+	// How long to report not ready before stopping, at least the readiness probe's
+	// periodSeconds times its failureThreshold
+	ShutdownDrainDelay time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
 }
 
 // LoadConfig read configuration from file or environment variables.
@@ -0,0 +1,18 @@
+package util
+
+// Domain events written to the outbox and delivered to webhooks
+const (
+	EventTransferCreated = "transfer.created"
+	EventAccountCreated  = "account.created"
+	EventUserUpdated     = "user.updated"
+	// A refresh token was used from another client than the one that logged in
+	EventSessionSuspicious = "session.suspicious"
+)
+
+func IsSupportedEventType(eventType string) bool {
+	switch eventType {
+	case EventTransferCreated, EventAccountCreated, EventUserUpdated, EventSessionSuspicious:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package util
+
+// Owner of the bank's own accounts, such as the ones fees are paid into
+const BankOwner = "simplebank"
+
+const (
+	FeeOperationTransfer = "transfer"
+)
+
+func IsSupportedFeeOperation(operation string) bool {
+	switch operation {
+	case FeeOperationTransfer:
+		return true
+	}
+	return false
+}
+
+// PercentageFee returns basisPoints hundredths of a percent of amount, rounded down
+// so the customer is never charged more than the published rate.
+func PercentageFee(amount int64, basisPoints int32) int64 {
+	// Split the amount so large balances cannot overflow the multiplication
+	bps := int64(basisPoints)
+	return amount/10000*bps + amount%10000*bps/10000
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentageFee(t *testing.T) {
+	testCases := []struct {
+		name        string
+		amount      int64
+		basisPoints int32
+		fee         int64
+	}{
+		{"Zero", 1000, 0, 0},
+		{"OnePercent", 1000, 100, 10},
+		{"RoundsDown", 999, 100, 9},
+		{"TooSmall", 50, 100, 0},
+		{"Everything", 1234, 10000, 1234},
+		{"NoOverflow", math.MaxInt64, 10000, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.fee, PercentageFee(tc.amount, tc.basisPoints))
+		})
+	}
+}
+
+func TestIsSupportedFeeOperation(t *testing.T) {
+	require.True(t, IsSupportedFeeOperation(FeeOperationTransfer))
+	require.False(t, IsSupportedFeeOperation("withdrawal"))
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Day-count conventions deciding how much of the annual rate one day earns
+const (
+	// Every year has 365 days
+	DayCountActual365 = "ACT/365"
+	// Every year has 360 days
+	DayCountActual360 = "ACT/360"
+	// A year has as many days as the calendar year the day falls in
+	DayCountActualActual = "ACT/ACT"
+)
+
+func IsSupportedDayCount(dayCount string) bool {
+	switch dayCount {
+	case DayCountActual365, DayCountActual360, DayCountActualActual:
+		return true
+	}
+	return false
+}
+
+// DaysInYear returns the length of the year day falls in under the convention.
+func DaysInYear(dayCount string, day time.Time) (int64, error) {
+	switch dayCount {
+	case DayCountActual365:
+		return 365, nil
+	case DayCountActual360:
+		return 360, nil
+	case DayCountActualActual:
+		year := day.Year()
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 366, nil
+		}
+		return 365, nil
+	}
+	return 0, fmt.Errorf("unsupported day count %q", dayCount)
+}
+
+// ParseInterestRate reads an annual rate written as a decimal fraction, such as
+// "0.0425" for 4.25%, without losing precision.
+func ParseInterestRate(rate string) (*big.Rat, error) {
+	// big.Rat also reads "a/b", which the database cannot store
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || strings.Contains(rate, "/") {
+		return nil, fmt.Errorf("invalid interest rate %q", rate)
+	}
+	if r.Sign() < 0 || r.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, fmt.Errorf("interest rate %q must be between 0 and 1", rate)
+	}
+	return r, nil
+}
+
+// DailyInterest returns the exact interest, in minor units, that balance earns on day.
+// Negative balances earn nothing.
+func DailyInterest(balance int64, annualRate *big.Rat, dayCount string, day time.Time) (*big.Rat, error) {
+	daysInYear, err := DaysInYear(dayCount, day)
+	if err != nil {
+		return nil, err
+	}
+
+	interest := new(big.Rat)
+	if balance <= 0 {
+		return interest, nil
+	}
+
+	interest.SetInt64(balance)
+	interest.Mul(interest, annualRate)
+	return interest.Quo(interest, big.NewRat(daysInYear, 1)), nil
+}
+
+// SplitInterest splits accrued interest into the whole minor units that can be paid
+// and the fraction left over for the next posting.
+func SplitInterest(accrued *big.Rat) (whole int64, remainder *big.Rat) {
+	quotient := new(big.Int).Quo(accrued.Num(), accrued.Denom())
+	remainder = new(big.Rat).Sub(accrued, new(big.Rat).SetInt(quotient))
+	return quotient.Int64(), remainder
+}
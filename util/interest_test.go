@@ -0,0 +1,77 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaysInYear(t *testing.T) {
+	testCases := []struct {
+		dayCount string
+		day      time.Time
+		days     int64
+	}{
+		{DayCountActual365, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 365},
+		{DayCountActual360, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 360},
+		{DayCountActualActual, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 366},
+		{DayCountActualActual, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), 365},
+		{DayCountActualActual, time.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC), 365},
+		{DayCountActualActual, time.Date(2000, 6, 1, 0, 0, 0, 0, time.UTC), 366},
+	}
+
+	for _, tc := range testCases {
+		days, err := DaysInYear(tc.dayCount, tc.day)
+		require.NoError(t, err)
+		require.Equal(t, tc.days, days, "%s in %d", tc.dayCount, tc.day.Year())
+	}
+
+	_, err := DaysInYear("30/360", time.Now())
+	require.Error(t, err)
+}
+
+func TestParseInterestRate(t *testing.T) {
+	rate, err := ParseInterestRate("0.0425")
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(17, 400), rate)
+
+	for _, invalid := range []string{"", "abc", "-0.01", "1.5", "1/2"} {
+		_, err := ParseInterestRate(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestDailyInterest(t *testing.T) {
+	rate, err := ParseInterestRate("0.05")
+	require.NoError(t, err)
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	interest, err := DailyInterest(1000, rate, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(10, 73), interest)
+
+	interest, err = DailyInterest(-1000, rate, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Zero(t, interest.Sign())
+
+	// A whole year of daily interest adds up to exactly the annual rate
+	total := new(big.Rat)
+	for i := 0; i < 365; i++ {
+		interest, err := DailyInterest(1000, rate, DayCountActualActual, day.AddDate(0, 0, i))
+		require.NoError(t, err)
+		total.Add(total, interest)
+	}
+	require.Equal(t, big.NewRat(50, 1), total)
+}
+
+func TestSplitInterest(t *testing.T) {
+	whole, remainder := SplitInterest(big.NewRat(17, 5))
+	require.Equal(t, int64(3), whole)
+	require.Equal(t, big.NewRat(2, 5), remainder)
+
+	whole, remainder = SplitInterest(big.NewRat(2, 7))
+	require.Zero(t, whole)
+	require.Equal(t, big.NewRat(2, 7), remainder)
+}
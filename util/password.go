@@ -1,21 +0,0 @@
-package util
-
-import (
-	"fmt"
-
-	"golang.org/x/crypto/bcrypt"
-)
-
-// HashPassword returns the bcrypt hash of the password
-func HashPassword(password string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash password %w", err)
-	}
-	return string(hashed), nil
-}
-
-func CheckPassword(password string, hashedPassword string) error {
-	/** Checks if provided password is correct. */
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
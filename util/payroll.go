@@ -0,0 +1,126 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PayrollRow is one payment parsed from an uploaded payroll file.
+type PayrollRow struct {
+	// Line in the file, the header is line 1
+	Line int
+	// Zero when the recipient is given by username
+	ToAccountID int64
+	Username    string
+	Amount      int64
+	Currency    string
+	Memo        string
+}
+
+// PayrollFieldError describes a problem with one field of one row.
+type PayrollFieldError struct {
+	Line  int
+	Field string
+	Err   error
+}
+
+func (e PayrollFieldError) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Err)
+}
+
+// ParsePayrollCSV reads a payroll file with a header row naming its columns.
+// The columns are to_account_id or username (or both, filled in per row), amount,
+// currency and an optional memo. Malformed files return an error, while problems
+// with individual rows are collected so the whole file can be reported at once.
+func ParsePayrollCSV(r io.Reader) (rows []PayrollRow, fieldErrors []PayrollFieldError, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = errors.New("file is empty")
+		}
+		return nil, nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"amount", "currency"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+	_, hasAccountID := columns["to_account_id"]
+	_, hasUsername := columns["username"]
+	if !hasAccountID && !hasUsername {
+		return nil, nil, errors.New("missing to_account_id or username column")
+	}
+
+	// Rows may leave trailing columns such as memo empty
+	reader.FieldsPerRecord = -1
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		fail := func(name string, err error) {
+			fieldErrors = append(fieldErrors, PayrollFieldError{Line: line, Field: name, Err: err})
+		}
+
+		row := PayrollRow{
+			Line:     line,
+			Username: field("username"),
+			Currency: field("currency"),
+			Memo:     field("memo"),
+		}
+
+		accountID := field("to_account_id")
+		switch {
+		case accountID == "" && row.Username == "":
+			fail("to_account_id", errors.New("either to_account_id or username is required"))
+		case accountID != "" && row.Username != "":
+			fail("to_account_id", errors.New("set only one of to_account_id and username"))
+		case accountID != "":
+			row.ToAccountID, err = strconv.ParseInt(accountID, 10, 64)
+			if err != nil || row.ToAccountID <= 0 {
+				fail("to_account_id", errors.New("must be a positive integer"))
+			}
+		}
+
+		row.Amount, err = strconv.ParseInt(field("amount"), 10, 64)
+		if err != nil || row.Amount <= 0 {
+			fail("amount", errors.New("must be a positive integer"))
+		}
+
+		if !IsSupportedCurrency(row.Currency) {
+			fail("currency", fmt.Errorf("unsupported currency %q", row.Currency))
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, errors.New("file has no rows")
+	}
+	return rows, fieldErrors, nil
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayrollCSV(t *testing.T) {
+	data := `to_account_id,username,amount,currency,memo
+12,,1500,USD,March salary
+,alice,2000,USD
+7,bob,100,USD,both recipients
+,,100,USD,no recipient
+-3,,100,USD,bad account
+13,,ten,USD,bad amount
+14,,100,XYZ,bad currency
+`
+
+	rows, fieldErrors, err := ParsePayrollCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+
+	require.Equal(t, PayrollRow{Line: 2, ToAccountID: 12, Amount: 1500, Currency: USD, Memo: "March salary"}, rows[0])
+	require.Equal(t, PayrollRow{Line: 3, Username: "alice", Amount: 2000, Currency: USD}, rows[1])
+
+	var got []string
+	for _, fieldError := range fieldErrors {
+		got = append(got, fieldError.Field)
+	}
+	require.Equal(t, []string{"to_account_id", "to_account_id", "to_account_id", "amount", "currency"}, got)
+	require.Equal(t, 4, fieldErrors[0].Line)
+	require.Equal(t, 8, fieldErrors[4].Line)
+}
+
+func TestParsePayrollCSVMalformed(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"Empty", ""},
+		{"HeaderOnly", "username,amount,currency\n"},
+		{"NoAmount", "username,currency\nalice,USD\n"},
+		{"NoRecipient", "amount,currency\n100,USD\n"},
+		{"BadQuoting", "username,amount,currency\n\"alice,100,USD\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParsePayrollCSV(strings.NewReader(tc.data))
+			require.Error(t, err)
+		})
+	}
+}
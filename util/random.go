@@ -46,3 +46,8 @@ func RandomCurrency() string {
 func RandomEmail() string {
 	return fmt.Sprintf("%s@email.com", RandomString(10))
 }
+
+func RandomPassword() string {
+	/** Generates a random password meeting the default password policy. */
+	return fmt.Sprintf("%s%s-%d", strings.ToUpper(RandomString(1)), RandomString(11), RandomInt(0, 9))
+}
@@ -0,0 +1,8 @@
+package util
+
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+	// Owns the bank's own accounts, such as the fee revenue accounts. Cannot log in.
+	SystemRole = "system"
+)
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Standard 5-field cron expressions plus descriptors such as @daily or @every 1h
+var scheduleParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// NextScheduledRun returns the first occurrence of the schedule strictly after the given time.
+func NextScheduledRun(schedule string, after time.Time) (time.Time, error) {
+	parsed, err := scheduleParser.Parse(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := parsed.Next(after)
+	if next.IsZero() {
+		return next, fmt.Errorf("schedule %q never fires", schedule)
+	}
+	return next, nil
+}
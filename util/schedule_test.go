@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextScheduledRun(t *testing.T) {
+	after := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	next, err := NextScheduledRun("0 9 1 * *", after)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC), next)
+
+	next, err = NextScheduledRun("@monthly", after)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), next)
+
+	next, err = NextScheduledRun("@every 1h", after)
+	require.NoError(t, err)
+	require.Equal(t, after.Add(time.Hour), next)
+
+	// February 30th does not exist
+	_, err = NextScheduledRun("0 0 30 2 *", after)
+	require.Error(t, err)
+
+	_, err = NextScheduledRun("not a schedule", after)
+	require.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package util
+
+import "net"
+
+// What to do when a refresh token is used from another client than the one that
+// logged in
+const (
+	// Renew the access token, only audit it and notify webhooks
+	SessionRiskNotify = "notify"
+	// Refuse to renew, the user has to log in again
+	SessionRiskReauth = "reauth"
+	// Refuse to renew and block the session for good
+	SessionRiskBlock = "block"
+)
+
+// Differences between the client renewing a session and the one that started it
+const (
+	SessionClientIPChanged  = "client_ip_changed"
+	SessionUserAgentChanged = "user_agent_changed"
+)
+
+func IsSupportedSessionRiskPolicy(policy string) bool {
+	switch policy {
+	case SessionRiskNotify, SessionRiskReauth, SessionRiskBlock:
+		return true
+	}
+	return false
+}
+
+// SessionRiskSignals compares the client renewing a session with the one that
+// started it. IPs are compared without their port, which changes on every connection.
+func SessionRiskSignals(sessionIP, sessionUserAgent, clientIP, userAgent string) []string {
+	var signals []string
+	if ipHost(sessionIP) != ipHost(clientIP) {
+		signals = append(signals, SessionClientIPChanged)
+	}
+	if sessionUserAgent != userAgent {
+		signals = append(signals, SessionUserAgentChanged)
+	}
+	return signals
+}
+
+func ipHost(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
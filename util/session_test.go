@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRiskSignals(t *testing.T) {
+	const userAgent = "Mozilla/5.0"
+
+	// Sessions started before client IPs were stored without their port
+	require.Empty(t, SessionRiskSignals("203.0.113.7:51234", userAgent, "203.0.113.7", userAgent))
+	require.Empty(t, SessionRiskSignals("[2001:db8::1]:443", userAgent, "2001:db8::1", userAgent))
+
+	require.Equal(t,
+		[]string{SessionClientIPChanged},
+		SessionRiskSignals("203.0.113.7", userAgent, "198.51.100.2", userAgent),
+	)
+	require.Equal(t,
+		[]string{SessionClientIPChanged, SessionUserAgentChanged},
+		SessionRiskSignals("203.0.113.7", userAgent, "198.51.100.2", "curl/8.5.0"),
+	)
+}
+
+func TestIsSupportedSessionRiskPolicy(t *testing.T) {
+	require.True(t, IsSupportedSessionRiskPolicy(SessionRiskNotify))
+	require.True(t, IsSupportedSessionRiskPolicy(SessionRiskBlock))
+	require.False(t, IsSupportedSessionRiskPolicy(""))
+}
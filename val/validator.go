@@ -3,12 +3,19 @@ package val
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 	"regexp"
+	"time"
+
+	"github.com/jasonwebb3152/simplebank/util"
 )
 
 var (
 	isValidUsername = regexp.MustCompile(`^[A-Za-z0-9_]+$`).MatchString
 	isValidFullName = regexp.MustCompile(`^[A-Za-z\s]+$`).MatchString
+	// E.164: a plus sign and up to 15 digits, the first one being a country code
+	isValidPhone      = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`).MatchString
+	isValidPostalCode = regexp.MustCompile(`^[A-Za-z0-9 -]{1,20}$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -53,3 +60,186 @@ func ValidateFullName(value string) error {
 	}
 	return nil
 }
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("unsupported currency %q", value)
+	}
+	return nil
+}
+
+func ValidatePageID(value int32) error {
+	if value < 1 {
+		return fmt.Errorf("must be at least 1")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 10 {
+		return fmt.Errorf("must be between 5 and 10")
+	}
+	return nil
+}
+
+func ValidateSchedule(value string) error {
+	if _, err := util.NextScheduledRun(value, time.Now()); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	return nil
+}
+
+func ValidateFeeOperation(value string) error {
+	if !util.IsSupportedFeeOperation(value) {
+		return fmt.Errorf("unsupported operation %q", value)
+	}
+	return nil
+}
+
+func ValidateBasisPoints(value int32) error {
+	if value < 0 || value > 10000 {
+		return fmt.Errorf("must be between 0 and 10000")
+	}
+	return nil
+}
+
+func ValidateNonNegative(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidateAccountType(value string) error {
+	if !util.IsCustomerAccountType(value) {
+		return fmt.Errorf("unsupported account type %q", value)
+	}
+	return nil
+}
+
+func ValidateInterestRate(value string) error {
+	if _, err := util.ParseInterestRate(value); err != nil {
+		return fmt.Errorf("must be a decimal fraction between 0 and 1")
+	}
+	return nil
+}
+
+func ValidateDayCount(value string) error {
+	if !util.IsSupportedDayCount(value) {
+		return fmt.Errorf("unsupported day count %q", value)
+	}
+	return nil
+}
+
+func ValidateStatementPeriod(from time.Time, to time.Time) error {
+	if !to.After(from) {
+		return fmt.Errorf("must be after from")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return fmt.Errorf("period must not be longer than a year")
+	}
+	return nil
+}
+
+func ValidateBalanceHistoryPeriod(from time.Time, to time.Time) error {
+	if to.Before(from) {
+		return fmt.Errorf("must not be before from")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return fmt.Errorf("period must not be longer than a year")
+	}
+	return nil
+}
+
+func ValidateWebhookURL(value string, allowHTTP bool) error {
+	if err := ValidateString(value, 1, 2048); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("must be an absolute https URL")
+	}
+	if u.Scheme != "https" && !(allowHTTP && u.Scheme == "http") {
+		return fmt.Errorf("must be an absolute https URL")
+	}
+	return nil
+}
+
+func ValidateEventType(value string) error {
+	if !util.IsSupportedEventType(value) {
+		return fmt.Errorf("unsupported event type %q", value)
+	}
+	return nil
+}
+
+func ValidateAuditOutcome(value string) error {
+	if !util.IsSupportedAuditOutcome(value) {
+		return fmt.Errorf("unsupported outcome %q", value)
+	}
+	return nil
+}
+
+func ValidateAuditPageSize(value int32) error {
+	if value < 1 || value > 100 {
+		return fmt.Errorf("must be between 1 and 100")
+	}
+	return nil
+}
+
+func ValidatePhone(value string) error {
+	if !isValidPhone(value) {
+		return fmt.Errorf("must be an E.164 phone number, like +14155552671")
+	}
+	return nil
+}
+
+func ValidateDateOfBirth(value string) error {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return fmt.Errorf("must be a date like 1990-04-21")
+	}
+	if date.After(time.Now()) {
+		return fmt.Errorf("must not be in the future")
+	}
+	if date.Year() < 1900 {
+		return fmt.Errorf("must not be before 1900")
+	}
+	return nil
+}
+
+func ValidateCountry(value string) error {
+	if !util.IsSupportedCountry(value) {
+		return fmt.Errorf("must be an ISO 3166-1 alpha-2 country code, like US")
+	}
+	return nil
+}
+
+func ValidateAddressLine(value string) error {
+	return ValidateString(value, 1, 200)
+}
+
+func ValidateCity(value string) error {
+	return ValidateString(value, 1, 100)
+}
+
+func ValidatePostalCode(value string) error {
+	if !isValidPostalCode(value) {
+		return fmt.Errorf("must contain up to 20 letters, digits, spaces or hyphens")
+	}
+	return nil
+}
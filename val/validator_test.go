@@ -0,0 +1,64 @@
+package val
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePhone(t *testing.T) {
+	testCases := []struct {
+		name  string
+		phone string
+		valid bool
+	}{
+		{"E164", "+14155552671", true},
+		{"Shortest", "+12", true},
+		{"Longest", "+123456789012345", true},
+		{"TooLong", "+1234567890123456", false},
+		{"NoPlus", "14155552671", false},
+		{"LeadingZero", "+04155552671", false},
+		{"Spaces", "+1 415 555 2671", false},
+		{"Empty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePhone(tc.phone)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDateOfBirth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dateOfBirth string
+		valid       bool
+	}{
+		{"Date", "1990-04-21", true},
+		{"Today", time.Now().Format(time.DateOnly), true},
+		{"Earliest", "1900-01-01", true},
+		{"BeforeEarliest", "1899-12-31", false},
+		{"Future", time.Now().AddDate(0, 0, 2).Format(time.DateOnly), false},
+		{"NotADay", "1990-02-30", false},
+		{"WithTime", "1990-04-21T00:00:00Z", false},
+		{"Empty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDateOfBirth(tc.dateOfBirth)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
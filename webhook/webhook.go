@@ -0,0 +1,196 @@
+// Package webhook delivers outbox events to the endpoints that subscribed to them.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+)
+
+// Headers sent with every delivery. Receivers should recompute the signature over
+// "<timestamp>.<body>" with their secret, and use the id to drop duplicates since an
+// event can be delivered more than once.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderEvent     = "Webhook-Event"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+const (
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = 6 * time.Hour
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrPrivateAddress   = errors.New("webhook endpoint resolves to a private address")
+)
+
+// Carrier-grade NAT, which IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Envelope is the body of a delivery.
+type Envelope struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewSecret generates the signing key of a new endpoint.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Sign returns the value of the signature header for a body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature the way a receiver would. Deliveries older than
+// tolerance are rejected so a captured request cannot be replayed later.
+func Verify(secret string, timestamp string, body []byte, signature string, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sentAt := time.Unix(seconds, 0)
+	if age := time.Since(sentAt); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, sentAt, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Backoff returns how long to wait before retrying after the given failed attempt.
+// The delay doubles with every attempt, up to a few hours.
+func Backoff(attempt int32) time.Duration {
+	delay := firstRetryDelay
+	for i := int32(1); i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Sender posts events to endpoints.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender that gives up on a request after timeout. Endpoints are
+// registered by customers, so unless allowPrivate is set the sender refuses to connect
+// to loopback, private and link-local addresses, and never follows redirects, which
+// would otherwise lead it back inside the network.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// No proxy, the address checked must be the endpoint's
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	// Runs on the resolved address right before connecting, so a DNS answer
+	// that changes after validation cannot point the request elsewhere
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Send delivers the event to the endpoint. Any response other than 2xx is an error,
+// redirects included.
+// The status code is returned whenever the endpoint answered.
+func (sender *Sender) Send(ctx context.Context, endpoint db.WebhookEndpoint, event db.OutboxEvent) (int, error) {
+	body, err := json.Marshal(Envelope{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, strconv.FormatInt(event.ID, 10))
+	req.Header.Set(HeaderEvent, event.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, now, body))
+
+	rsp, err := sender.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(rsp.Body, 64<<10))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return rsp.StatusCode, fmt.Errorf("endpoint responded with %s", rsp.Status)
+	}
+	return rsp.StatusCode, nil
+}
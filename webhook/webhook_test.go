@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvent() db.OutboxEvent {
+	return db.OutboxEvent{
+		ID:        util.RandomInt(1, 1000),
+		EventType: util.EventTransferCreated,
+		Payload:   json.RawMessage(`{"id":1,"amount":100}`),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 64)
+
+	body := []byte(`{"id":1}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign(secret, now, body)
+
+	require.NoError(t, Verify(secret, timestamp, body, signature, time.Minute))
+	require.ErrorIs(t, Verify("other secret", timestamp, body, signature, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, timestamp, []byte(`{"id":2}`), signature, time.Minute), ErrInvalidSignature)
+
+	// A signature that is too old is not accepted even though it matches
+	old := now.Add(-time.Hour)
+	require.ErrorIs(t, Verify(secret, strconv.FormatInt(old.Unix(), 10), body, Sign(secret, old, body), time.Minute), ErrInvalidSignature)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, Backoff(1))
+	require.Equal(t, time.Minute, Backoff(2))
+	require.Equal(t, 2*time.Minute, Backoff(3))
+	require.Equal(t, maxRetryDelay, Backoff(20))
+	require.Equal(t, maxRetryDelay, Backoff(1000))
+}
+
+func TestSend(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	event := randomEvent()
+
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	statusCode, err := sender.Send(context.Background(), db.WebhookEndpoint{
+		Url:    server.URL,
+		Secret: secret,
+	}, event)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, statusCode)
+
+	err = Verify(secret, header.Get(HeaderTimestamp), body, header.Get(HeaderSignature), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, strconv.FormatInt(event.ID, 10), header.Get(HeaderID))
+	require.Equal(t, event.EventType, header.Get(HeaderEvent))
+
+	var received Envelope
+	require.NoError(t, json.Unmarshal(body, &received))
+	require.Equal(t, event.ID, received.ID)
+	require.Equal(t, event.EventType, received.Type)
+	require.True(t, event.CreatedAt.Equal(received.CreatedAt))
+	require.JSONEq(t, string(event.Payload), string(received.Data))
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	statusCode, err := sender.Send(context.Background(), db.WebhookEndpoint{
+		Url:    server.URL,
+		Secret: "secret",
+	}, randomEvent())
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+}
+
+func TestSendPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback
+	sender := NewSender(time.Second, false)
+	statusCode, err := sender.Send(context.Background(), db.WebhookEndpoint{
+		Url:    server.URL,
+		Secret: "secret",
+	}, randomEvent())
+	require.ErrorIs(t, err, ErrPrivateAddress)
+	require.Zero(t, statusCode)
+	require.False(t, called)
+}
+
+func TestSendRedirect(t *testing.T) {
+	called := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	statusCode, err := sender.Send(context.Background(), db.WebhookEndpoint{
+		Url:    server.URL,
+		Secret: "secret",
+	}, randomEvent())
+	require.Error(t, err)
+	require.Equal(t, http.StatusTemporaryRedirect, statusCode)
+	require.False(t, called)
+}
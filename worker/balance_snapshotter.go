@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// Transfers that began just before midnight may still be committing when the day
+// ends, so a day is only snapshotted once this much time has passed after it.
+const snapshotDelay = 5 * time.Minute
+
+// BalanceSnapshotter records the closing balance of every account once a day has ended.
+type BalanceSnapshotter struct {
+	store        db.Store
+	interval     time.Duration
+	lookbackDays int
+}
+
+// NewBalanceSnapshotter creates a snapshotter that runs every interval. Days missed
+// while the service was down are caught up as long as they are within lookbackDays.
+func NewBalanceSnapshotter(store db.Store, interval time.Duration, lookbackDays int) *BalanceSnapshotter {
+	return &BalanceSnapshotter{
+		store:        store,
+		interval:     interval,
+		lookbackDays: lookbackDays,
+	}
+}
+
+// Start runs the snapshotter until ctx is cancelled.
+func (snapshotter *BalanceSnapshotter) Start(ctx context.Context) {
+	ticker := time.NewTicker(snapshotter.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			snapshotter.run(ctx, time.Now().UTC().Add(-snapshotDelay))
+		}
+	}
+}
+
+func (snapshotter *BalanceSnapshotter) run(ctx context.Context, now time.Time) {
+	// Reruns are safe, accounts that already have a snapshot for the day are skipped
+	for days := snapshotter.lookbackDays; days >= 1 && ctx.Err() == nil; days-- {
+		day := now.AddDate(0, 0, -days)
+		count, err := snapshotter.store.SnapshotBalancesTx(ctx, db.SnapshotBalancesTxParams{
+			Date: day,
+		})
+		if err != nil {
+			log.Error().Err(err).Time("day", day).Msg("cannot snapshot balances")
+			return
+		}
+
+		if count > 0 {
+			log.Info().
+				Time("day", day).
+				Int64("accounts", count).
+				Msg("snapshotted balances")
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// HoldSweeper periodically releases account holds whose TTL has passed,
+// so their reserved funds become available again.
+type HoldSweeper struct {
+	store    db.Store
+	interval time.Duration
+}
+
+// NewHoldSweeper creates a sweeper that runs every interval.
+func NewHoldSweeper(store db.Store, interval time.Duration) *HoldSweeper {
+	return &HoldSweeper{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the sweeper until ctx is cancelled.
+func (sweeper *HoldSweeper) Start(ctx context.Context) {
+	ticker := time.NewTicker(sweeper.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweeper.sweep(ctx)
+		}
+	}
+}
+
+func (sweeper *HoldSweeper) sweep(ctx context.Context) {
+	expired, err := sweeper.store.ExpireAccountHolds(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot expire account holds")
+		return
+	}
+
+	if expired > 0 {
+		log.Info().Int64("count", expired).Msg("expired account holds")
+	}
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// InterestAccruer accrues daily interest on accounts that earn it and pays it out
+// once a month has ended.
+type InterestAccruer struct {
+	store        db.Store
+	interval     time.Duration
+	lookbackDays int
+}
+
+// NewInterestAccruer creates an accruer that runs every interval. Days missed while
+// the service was down are caught up as long as they are within lookbackDays.
+func NewInterestAccruer(store db.Store, interval time.Duration, lookbackDays int) *InterestAccruer {
+	return &InterestAccruer{
+		store:        store,
+		interval:     interval,
+		lookbackDays: lookbackDays,
+	}
+}
+
+// Start runs the accruer until ctx is cancelled.
+func (accruer *InterestAccruer) Start(ctx context.Context) {
+	ticker := time.NewTicker(accruer.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			accruer.run(ctx, time.Now().UTC())
+		}
+	}
+}
+
+func (accruer *InterestAccruer) run(ctx context.Context, now time.Time) {
+	// Only whole days are accrued, and they are accrued oldest first so a month
+	// is complete before it is posted. Other accounts keep accruing when one of
+	// them fails, but nothing is posted until every day is complete.
+	complete := true
+	for days := accruer.lookbackDays; days >= 1 && ctx.Err() == nil; days-- {
+		if !accruer.accrueDay(ctx, now.AddDate(0, 0, -days)) {
+			complete = false
+		}
+	}
+	if !complete {
+		return
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	accruer.postMonth(ctx, monthStart)
+}
+
+func (accruer *InterestAccruer) accrueDay(ctx context.Context, day time.Time) bool {
+	// Reruns are safe, accounts already accrued for the day are skipped. An account
+	// that fails is left for the next run so it does not hold up the others.
+	var afterAccountID int64
+	complete := true
+	for ctx.Err() == nil {
+		accrual, err := accruer.store.AccrueInterestTx(ctx, db.AccrueInterestTxParams{
+			Date:           day,
+			AfterAccountID: afterAccountID,
+		})
+		if errors.Is(err, db.ErrNoInterestToAccrue) {
+			return complete
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("account_id", accrual.AccountID).Time("day", day).Msg("cannot accrue interest")
+			if accrual.AccountID == 0 {
+				return false
+			}
+			interestFailuresTotal.WithLabelValues("accrue").Inc()
+			afterAccountID = accrual.AccountID
+			complete = false
+			continue
+		}
+
+		log.Debug().
+			Int64("account_id", accrual.AccountID).
+			Time("day", accrual.AccrualDate).
+			Msg("accrued interest")
+		afterAccountID = accrual.AccountID
+	}
+	return false
+}
+
+func (accruer *InterestAccruer) postMonth(ctx context.Context, monthStart time.Time) {
+	var afterAccountID int64
+	for ctx.Err() == nil {
+		posting, err := accruer.store.PostInterestTx(ctx, db.PostInterestTxParams{
+			Before:         monthStart,
+			AfterAccountID: afterAccountID,
+		})
+		if errors.Is(err, db.ErrNoInterestToPost) {
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("account_id", posting.AccountID).Msg("cannot post interest")
+			if posting.AccountID == 0 {
+				return
+			}
+			interestFailuresTotal.WithLabelValues("post").Inc()
+			afterAccountID = posting.AccountID
+			continue
+		}
+
+		log.Info().
+			Int64("account_id", posting.AccountID).
+			Int64("amount", posting.Amount).
+			Msg("posted interest")
+		afterAccountID = posting.AccountID
+	}
+}
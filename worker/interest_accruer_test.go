@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterestAccruerSkipsFailingAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	day := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	failure := errors.New("numeric field overflow")
+
+	gomock.InOrder(
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{Date: day})).
+			Times(1).
+			Return(db.InterestAccrual{AccountID: 3}, failure),
+		// The accounts after the failing one are still accrued
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{Date: day, AfterAccountID: 3})).
+			Times(1).
+			Return(db.InterestAccrual{AccountID: 5, AccrualDate: day}, nil),
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{Date: day, AfterAccountID: 5})).
+			Times(1).
+			Return(db.InterestAccrual{}, db.ErrNoInterestToAccrue),
+	)
+
+	// The day is incomplete, so the month must not be posted yet
+	require.False(t, NewInterestAccruer(store, time.Hour, 1).accrueDay(context.Background(), day))
+}
+
+func TestInterestAccruerStopsWithoutAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	day := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	// Nothing to skip when no account was picked, the next run tries again
+	store.EXPECT().
+		AccrueInterestTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.InterestAccrual{}, errors.New("driver: bad connection"))
+
+	require.False(t, NewInterestAccruer(store, time.Hour, 1).accrueDay(context.Background(), day))
+}
+
+func TestInterestAccruerPostsAfterFailingAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	monthStart := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	gomock.InOrder(
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{Before: monthStart})).
+			Times(1).
+			Return(db.InterestPosting{AccountID: 3}, db.ErrAccountFrozen),
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{Before: monthStart, AfterAccountID: 3})).
+			Times(1).
+			Return(db.InterestPosting{AccountID: 5, Amount: 12}, nil),
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{Before: monthStart, AfterAccountID: 5})).
+			Times(1).
+			Return(db.InterestPosting{}, db.ErrNoInterestToPost),
+	)
+
+	NewInterestAccruer(store, time.Hour, 1).postMonth(context.Background(), monthStart)
+}
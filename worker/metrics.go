@@ -0,0 +1,12 @@
+package worker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var interestFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "simplebank",
+	Name:      "interest_failures_total",
+	Help:      "Accounts skipped by the interest accruer because accruing or posting their interest failed.",
+}, []string{"step"})
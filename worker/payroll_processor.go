@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// PayrollProcessor validates and pays uploaded payroll jobs in chunks, outside of
+// the request that uploaded them.
+type PayrollProcessor struct {
+	store     db.Store
+	interval  time.Duration
+	chunkSize int32
+}
+
+// NewPayrollProcessor creates a processor that polls for jobs every interval and
+// handles up to chunkSize rows per transaction.
+func NewPayrollProcessor(store db.Store, interval time.Duration, chunkSize int32) *PayrollProcessor {
+	return &PayrollProcessor{
+		store:     store,
+		interval:  interval,
+		chunkSize: chunkSize,
+	}
+}
+
+// Start runs the processor until ctx is cancelled.
+func (processor *PayrollProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processor.processPending(ctx)
+		}
+	}
+}
+
+func (processor *PayrollProcessor) processPending(ctx context.Context) {
+	// Keep going until every job is finished, one chunk per transaction
+	for ctx.Err() == nil {
+		job, err := processor.store.ProcessPayrollJobTx(ctx, db.ProcessPayrollJobTxParams{
+			ChunkSize: processor.chunkSize,
+		})
+		if errors.Is(err, db.ErrNoPayrollJobPending) {
+			return
+		}
+
+		if err == nil {
+			log.Debug().
+				Int64("payroll_job_id", job.ID).
+				Str("status", job.Status).
+				Int32("processed_rows", job.ProcessedRows).
+				Msg("processed payroll chunk")
+			continue
+		}
+
+		if job.ID == 0 {
+			log.Error().Err(err).Msg("cannot pick pending payroll job")
+			return
+		}
+
+		// Only a row that cannot be paid is recorded; anything else, like a lost
+		// connection, leaves the job untouched for the next tick
+		var rowErr *db.PayrollRowError
+		if !errors.As(err, &rowErr) {
+			log.Error().Err(err).Int64("payroll_job_id", job.ID).Msg("payroll chunk failed, will retry")
+			return
+		}
+
+		log.Warn().Err(err).Int64("payroll_job_id", job.ID).Msg("payroll row cannot be paid")
+
+		_, err = processor.store.FailPayrollJobTx(ctx, db.FailPayrollJobTxParams{
+			JobID:     job.ID,
+			RowNumber: rowErr.RowNumber,
+			Error:     rowErr.Err.Error(),
+		})
+		if err != nil {
+			// Stop here, otherwise the same job would be picked again right away
+			log.Error().Err(err).Int64("payroll_job_id", job.ID).Msg("cannot record payroll row failure")
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// TransferScheduler executes due scheduled transfers. Several instances can run
+// side by side, since each due row is locked by exactly one of them.
+type TransferScheduler struct {
+	store    db.Store
+	interval time.Duration
+}
+
+// NewTransferScheduler creates a scheduler that polls for due transfers every interval.
+func NewTransferScheduler(store db.Store, interval time.Duration) *TransferScheduler {
+	return &TransferScheduler{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (scheduler *TransferScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			scheduler.runDue(ctx)
+		}
+	}
+}
+
+func (scheduler *TransferScheduler) runDue(ctx context.Context) {
+	// Keep going until nothing is due, so a backlog is cleared in a single tick
+	for ctx.Err() == nil {
+		result, err := scheduler.store.ExecuteScheduledTransferTx(ctx)
+		if errors.Is(err, db.ErrNoScheduledTransferDue) {
+			return
+		}
+
+		scheduled := result.ScheduledTransfer
+		if err == nil {
+			log.Info().
+				Int64("scheduled_transfer_id", scheduled.ID).
+				Int64("transfer_id", result.Transfer.ID).
+				Msg("executed scheduled transfer")
+			continue
+		}
+
+		if scheduled.ID == 0 {
+			log.Error().Err(err).Msg("cannot pick due scheduled transfer")
+			return
+		}
+
+		// Only a refused transfer consumes the occurrence; anything else, like a lost
+		// connection or a shutdown, leaves it due for the next tick
+		if !db.IsTransferRejection(err) {
+			log.Error().Err(err).
+				Int64("scheduled_transfer_id", scheduled.ID).
+				Msg("scheduled transfer failed, will retry")
+			return
+		}
+
+		log.Warn().Err(err).
+			Int64("scheduled_transfer_id", scheduled.ID).
+			Msg("scheduled transfer was refused")
+
+		_, err = scheduler.store.FailScheduledTransferTx(ctx, db.FailScheduledTransferTxParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor:        scheduled.NextRunAt,
+			Error:               err.Error(),
+		})
+		if err != nil {
+			// Stop here, otherwise the same row would be picked again right away
+			log.Error().Err(err).
+				Int64("scheduled_transfer_id", scheduled.ID).
+				Msg("cannot record scheduled transfer failure")
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/jasonwebb3152/simplebank/db/mock"
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+)
+
+func TestTransferSchedulerRunDue(t *testing.T) {
+	scheduled := db.ScheduledTransfer{
+		ID:        7,
+		NextRunAt: time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name       string
+		err        error
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "Refused",
+			err:  fmt.Errorf("account 3: %w", db.ErrInsufficientFunds),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FailScheduledTransferTx(gomock.Any(), gomock.Eq(db.FailScheduledTransferTxParams{
+						ScheduledTransferID: scheduled.ID,
+						ScheduledFor:        scheduled.NextRunAt,
+						Error:               "account 3: " + db.ErrInsufficientFunds.Error(),
+					})).
+					Times(1).
+					Return(scheduled, nil)
+				// The next one is picked right away
+				store.EXPECT().
+					ExecuteScheduledTransferTx(gomock.Any()).
+					Times(1).
+					Return(db.ExecuteScheduledTransferTxResult{}, db.ErrNoScheduledTransferDue)
+			},
+		},
+		{
+			// The occurrence stays due and the tick ends
+			name: "ConnectionLost",
+			err:  errors.New("driver: bad connection"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FailScheduledTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+		},
+		{
+			name: "Canceled",
+			err:  context.Canceled,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FailScheduledTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().
+				ExecuteScheduledTransferTx(gomock.Any()).
+				Times(1).
+				Return(db.ExecuteScheduledTransferTxResult{ScheduledTransfer: scheduled}, tc.err)
+			tc.buildStubs(store)
+
+			NewTransferScheduler(store, time.Minute).runDue(context.Background())
+		})
+	}
+}
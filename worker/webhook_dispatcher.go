@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/jasonwebb3152/simplebank/db/sqlc"
+	"github.com/jasonwebb3152/simplebank/webhook"
+	"github.com/rs/zerolog/log"
+)
+
+// WebhookDispatcher queues outbox events for the endpoints that subscribed to them
+// and delivers them, retrying failures with exponential backoff.
+type WebhookDispatcher struct {
+	store       db.Store
+	interval    time.Duration
+	sender      *webhook.Sender
+	lease       time.Duration
+	maxAttempts int32
+}
+
+// NewWebhookDispatcher creates a dispatcher that runs every interval. A delivery that
+// still fails after maxAttempts is dead-lettered and only sent again when replayed.
+// allowPrivate lets deliveries reach private addresses, for local development.
+func NewWebhookDispatcher(store db.Store, interval time.Duration, timeout time.Duration, maxAttempts int32, allowPrivate bool) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		store:    store,
+		interval: interval,
+		sender:   webhook.NewSender(timeout, allowPrivate),
+		// Leaves time to record the outcome after the request timed out
+		lease:       timeout + time.Minute,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start runs the dispatcher until ctx is cancelled.
+func (dispatcher *WebhookDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dispatcher.publishEvents(ctx)
+			dispatcher.deliverWebhooks(ctx)
+		}
+	}
+}
+
+func (dispatcher *WebhookDispatcher) publishEvents(ctx context.Context) {
+	for ctx.Err() == nil {
+		result, err := dispatcher.store.PublishOutboxEventTx(ctx)
+		if errors.Is(err, db.ErrNoEventToPublish) {
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("cannot publish outbox event")
+			return
+		}
+
+		log.Debug().
+			Int64("event_id", result.Event.ID).
+			Str("event_type", result.Event.EventType).
+			Int64("deliveries", result.Deliveries).
+			Msg("published outbox event")
+	}
+}
+
+func (dispatcher *WebhookDispatcher) deliverWebhooks(ctx context.Context) {
+	for ctx.Err() == nil {
+		claim, err := dispatcher.store.ClaimWebhookDeliveryTx(ctx, db.ClaimWebhookDeliveryTxParams{
+			Lease: dispatcher.lease,
+		})
+		if errors.Is(err, db.ErrNoWebhookToDeliver) {
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("cannot claim webhook delivery")
+			return
+		}
+
+		dispatcher.deliver(ctx, claim)
+	}
+}
+
+func (dispatcher *WebhookDispatcher) deliver(ctx context.Context, claim db.ClaimWebhookDeliveryTxResult) {
+	delivery := claim.Delivery
+	statusCode, sendErr := dispatcher.sender.Send(ctx, claim.Endpoint, claim.Event)
+
+	now := time.Now()
+	arg := db.RecordWebhookAttemptParams{
+		ID:            delivery.ID,
+		Status:        db.WebhookDelivered,
+		NextAttemptAt: now,
+		LastStatusCode: sql.NullInt32{
+			Int32: int32(statusCode),
+			Valid: statusCode != 0,
+		},
+		DeliveredAt: sql.NullTime{
+			Time:  now,
+			Valid: sendErr == nil,
+		},
+	}
+	if sendErr != nil {
+		arg.LastError = sql.NullString{
+			String: sendErr.Error(),
+			Valid:  true,
+		}
+		arg.Status = db.WebhookPending
+		arg.NextAttemptAt = now.Add(webhook.Backoff(delivery.Attempts))
+		if delivery.Attempts >= dispatcher.maxAttempts {
+			arg.Status = db.WebhookDead
+		}
+	}
+
+	// If this fails the lease runs out and the event is sent again, which receivers
+	// have to tolerate anyway
+	_, err := dispatcher.store.RecordWebhookAttempt(ctx, arg)
+	if err != nil {
+		log.Error().Err(err).Int64("delivery_id", delivery.ID).Msg("cannot record webhook attempt")
+		return
+	}
+
+	logger := log.Info()
+	if sendErr != nil {
+		logger = log.Warn().Err(sendErr)
+	}
+	logger.
+		Int64("delivery_id", delivery.ID).
+		Int64("event_id", delivery.EventID).
+		Str("url", claim.Endpoint.Url).
+		Int32("attempt", delivery.Attempts).
+		Str("status", arg.Status).
+		Msg("delivered webhook")
+}